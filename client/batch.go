@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+
+	"github.com/labyla/solana-go-sdk/rpc"
+)
+
+// Batch queues several calls and sends them in a single http request. results
+// are converted the same way as the non-batched methods.
+type Batch struct {
+	batch *rpc.Batch
+}
+
+// BatchCall is a handle of a queued call. its result is available after the batch is sent.
+type BatchCall[T any] struct {
+	result func() (T, error)
+}
+
+// Result returns the converted result, or the rpc error of this call
+func (c BatchCall[T]) Result() (T, error) {
+	return c.result()
+}
+
+func newBatchCall[A any, B any](call rpc.BatchCall[A], convert func(A) (B, error)) BatchCall[B] {
+	return BatchCall[B]{
+		result: func() (B, error) {
+			return process(call.Result, convert)
+		},
+	}
+}
+
+// NewBatch creates an empty batch
+func (c *Client) NewBatch() *Batch {
+	return &Batch{batch: c.RpcClient.NewBatch()}
+}
+
+// Send posts all queued calls in one http request
+func (b *Batch) Send(ctx context.Context) error {
+	return b.batch.Send(ctx)
+}
+
+// Len returns the number of queued calls
+func (b *Batch) Len() int {
+	return b.batch.Len()
+}
+
+// GetBalance queues a call which fetch users lamports(SOL) balance
+func (b *Batch) GetBalance(base58Addr string) BatchCall[uint64] {
+	return newBatchCall(b.batch.GetBalance(base58Addr), value[uint64])
+}
+
+// GetBalanceWithConfig queues a call which fetch users lamports(SOL) balance with specific commitment
+func (b *Batch) GetBalanceWithConfig(base58Addr string, cfg GetBalanceConfig) BatchCall[uint64] {
+	return newBatchCall(b.batch.GetBalanceWithConfig(base58Addr, cfg.toRpc()), value[uint64])
+}
+
+// GetAccountInfo queues a call which return account's info
+func (b *Batch) GetAccountInfo(base58Addr string) BatchCall[AccountInfo] {
+	return newBatchCall(b.batch.GetAccountInfoWithConfig(base58Addr, GetAccountInfoConfig{}.toRpc()), convertGetAccountInfo)
+}
+
+// GetAccountInfoWithConfig queues a call which return account's info
+func (b *Batch) GetAccountInfoWithConfig(base58Addr string, cfg GetAccountInfoConfig) BatchCall[AccountInfo] {
+	return newBatchCall(b.batch.GetAccountInfoWithConfig(base58Addr, cfg.toRpc()), convertGetAccountInfo)
+}
+
+// GetMultipleAccounts queues a call which returns multiple accounts info
+func (b *Batch) GetMultipleAccounts(addrs []string) BatchCall[[]AccountInfo] {
+	return newBatchCall(b.batch.GetMultipleAccountsWithConfig(addrs, GetMultipleAccountsConfig{}.toRpc()), convertGetMultipleAccounts)
+}
+
+// GetMultipleAccountsWithConfig queues a call which returns multiple accounts info
+func (b *Batch) GetMultipleAccountsWithConfig(addrs []string, cfg GetMultipleAccountsConfig) BatchCall[[]AccountInfo] {
+	return newBatchCall(b.batch.GetMultipleAccountsWithConfig(addrs, cfg.toRpc()), convertGetMultipleAccounts)
+}
+
+// GetSignatureStatuses queues a `getSignatureStatuses` call
+func (b *Batch) GetSignatureStatuses(signatures []string) BatchCall[rpc.SignatureStatuses] {
+	return newBatchCall(b.batch.GetSignatureStatuses(signatures), value[rpc.SignatureStatuses])
+}
+
+// GetSignatureStatusesWithConfig queues a `getSignatureStatuses` call
+func (b *Batch) GetSignatureStatusesWithConfig(signatures []string, cfg GetSignatureStatusesConfig) BatchCall[rpc.SignatureStatuses] {
+	return newBatchCall(b.batch.GetSignatureStatusesWithConfig(signatures, cfg.toRpc()), value[rpc.SignatureStatuses])
+}
+
+// GetTokenAccountBalance queues a `getTokenAccountBalance` call
+func (b *Batch) GetTokenAccountBalance(addr string) BatchCall[TokenAmount] {
+	return newBatchCall(b.batch.GetTokenAccountBalance(addr), convertGetTokenAccountBalance)
+}
+
+// GetTokenAccountBalanceWithConfig queues a `getTokenAccountBalance` call
+func (b *Batch) GetTokenAccountBalanceWithConfig(addr string, cfg GetTokenAccountBalanceConfig) BatchCall[TokenAmount] {
+	return newBatchCall(b.batch.GetTokenAccountBalanceWithConfig(addr, cfg.toRpc()), convertGetTokenAccountBalance)
+}
+
+// GetSlot queues a `getSlot` call
+func (b *Batch) GetSlot() BatchCall[uint64] {
+	return newBatchCall(b.batch.GetSlot(), forward[uint64])
+}
+
+// GetLatestBlockhash queues a `getLatestBlockhash` call
+func (b *Batch) GetLatestBlockhash() BatchCall[rpc.GetLatestBlockhashValue] {
+	return newBatchCall(b.batch.GetLatestBlockhash(), convertGetLatestBlockhash)
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/internal/client_test"
+	"github.com/labyla/solana-go-sdk/rpc"
+)
+
+func TestClient_Batch(t *testing.T) {
+	type results struct {
+		Balance     uint64
+		AccountInfo AccountInfo
+		AccountErr  error
+	}
+	client_test.TestAll(
+		t,
+		[]client_test.Param{
+			{
+				RequestBody:  `[{"jsonrpc":"2.0","id":1,"method":"getBalance","params":["RNfp4xTbBb4C3kcv2KqtAj8mu4YhMHxqm1Skg9uchZ7"]},{"jsonrpc":"2.0","id":2,"method":"getAccountInfo","params":["F5RYi7FMPefkc7okJNh21Hcsch7RUaLVr8Rzc8SQqxUb",{"encoding":"base64"}]},{"jsonrpc":"2.0","id":3,"method":"getAccountInfo","params":["11111111111111111111111111111111",{"encoding":"base64"}]}]`,
+				ResponseBody: `[{"jsonrpc":"2.0","result":{"context":{"slot":77382573},"value":{"data":["AQID","base64"],"executable":false,"lamports":21474700400,"owner":"11111111111111111111111111111111","rentEpoch":178}},"id":2},{"jsonrpc":"2.0","error":{"code":-32005,"message":"Node is behind by 42 slots"},"id":3},{"jsonrpc":"2.0","result":{"context":{"slot":73914708},"value":6999995000},"id":1}]`,
+				F: func(url string) (any, error) {
+					c := NewClient(url)
+					b := c.NewBatch()
+					balance := b.GetBalance("RNfp4xTbBb4C3kcv2KqtAj8mu4YhMHxqm1Skg9uchZ7")
+					accountInfo := b.GetAccountInfo("F5RYi7FMPefkc7okJNh21Hcsch7RUaLVr8Rzc8SQqxUb")
+					failed := b.GetAccountInfo("11111111111111111111111111111111")
+					if err := b.Send(context.Background()); err != nil {
+						return nil, err
+					}
+
+					var r results
+					var err error
+					if r.Balance, err = balance.Result(); err != nil {
+						return nil, err
+					}
+					if r.AccountInfo, err = accountInfo.Result(); err != nil {
+						return nil, err
+					}
+					_, r.AccountErr = failed.Result()
+					return r, nil
+				},
+				ExpectedValue: results{
+					Balance: 6999995000,
+					AccountInfo: AccountInfo{
+						Lamports:   21474700400,
+						Owner:      common.SystemProgramID,
+						Executable: false,
+						RentEpoch:  178,
+						Data:       []byte{1, 2, 3},
+					},
+					AccountErr: &rpc.JsonRpcError{
+						Code:    -32005,
+						Message: "Node is behind by 42 slots",
+					},
+				},
+				ExpectedError: nil,
+			},
+		},
+	)
+}
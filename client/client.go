@@ -26,7 +26,7 @@ func NewClient(endpoint string) *Client {
 
 type QuickSendTransactionParam struct {
 	Instructions []types.Instruction
-	Signers      []types.Account
+	Signers      []types.Signer
 	FeePayer     common.PublicKey
 }
 
@@ -37,7 +37,7 @@ func (c *Client) QuickSendTransaction(ctx context.Context, param QuickSendTransa
 	if err != nil {
 		return "", fmt.Errorf("failed to get recent blockhash, err: %v", err)
 	}
-	tx, err := types.NewTransaction(types.NewTransactionParam{
+	tx, err := types.NewTransactionWithContext(ctx, types.NewTransactionParam{
 		Message: types.NewMessage(types.NewMessageParam{
 			Instructions:    param.Instructions,
 			FeePayer:        param.FeePayer,
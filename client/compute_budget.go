@@ -0,0 +1,210 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/program/compute_budget"
+	"github.com/labyla/solana-go-sdk/rpc"
+	"github.com/labyla/solana-go-sdk/types"
+)
+
+// PriorityFeeStrategy picks the compute unit price from recent prioritization fees
+type PriorityFeeStrategy interface {
+	// Pick returns a price in micro-lamports. fees are sorted ascending and never empty.
+	Pick(fees []uint64) uint64
+}
+
+type percentilePriorityFee struct {
+	percentile float64
+}
+
+// PercentilePriorityFee picks the p-th percentile, in [0, 100], of recent fees
+func PercentilePriorityFee(p float64) PriorityFeeStrategy {
+	return percentilePriorityFee{percentile: math.Max(0, math.Min(100, p))}
+}
+
+func (s percentilePriorityFee) Pick(fees []uint64) uint64 {
+	i := int(math.Ceil(s.percentile/100*float64(len(fees)))) - 1
+	if i < 0 {
+		i = 0
+	}
+	return fees[i]
+}
+
+// getRecentPrioritizationFees accepts up to 128 accounts
+const maxPrioritizationFeeAccounts = 128
+
+type TuneComputeBudgetParam struct {
+	FeePayer                   common.PublicKey
+	Instructions               []types.Instruction
+	AddressLookupTableAccounts []types.AddressLookupTableAccount
+	// UnitsMargin is the fraction added to the simulated units. default: 0.1
+	UnitsMargin float64
+	// PriorityFeeStrategy default: PercentilePriorityFee(75)
+	PriorityFeeStrategy PriorityFeeStrategy
+	// MaxComputeUnitPrice caps the price in micro-lamports. zero means no cap.
+	MaxComputeUnitPrice uint64
+	Commitment          rpc.Commitment
+}
+
+type TuneComputeBudgetResult struct {
+	// Instructions are the instructions of the param with the compute budget
+	// instructions inserted or replaced
+	Instructions     []types.Instruction
+	UnitsConsumed    uint64
+	ComputeUnitLimit uint32
+	ComputeUnitPrice uint64
+}
+
+// TuneComputeBudget simulates the instructions to set the compute unit limit and picks
+// the compute unit price from the recent prioritization fees of the writable accounts.
+// existing SetComputeUnitLimit and SetComputeUnitPrice instructions are replaced.
+func (c *Client) TuneComputeBudget(ctx context.Context, param TuneComputeBudgetParam) (TuneComputeBudgetResult, error) {
+	if param.UnitsMargin <= 0 {
+		param.UnitsMargin = 0.1
+	}
+	if param.PriorityFeeStrategy == nil {
+		param.PriorityFeeStrategy = PercentilePriorityFee(75)
+	}
+
+	instructions := make([]types.Instruction, 0, len(param.Instructions))
+	for _, instruction := range param.Instructions {
+		if !isComputeBudgetInstruction(instruction, compute_budget.InstructionSetComputeUnitLimit) &&
+			!isComputeBudgetInstruction(instruction, compute_budget.InstructionSetComputeUnitPrice) {
+			instructions = append(instructions, instruction)
+		}
+	}
+
+	// simulate with the max limit, the default one is 200k per instruction
+	unitsConsumed, err := c.simulateUnitsConsumed(ctx, param, append([]types.Instruction{
+		compute_budget.SetComputeUnitLimit(compute_budget.SetComputeUnitLimitParam{
+			Units: compute_budget.MaxComputeUnitLimit,
+		}),
+	}, instructions...))
+	if err != nil {
+		return TuneComputeBudgetResult{}, err
+	}
+
+	limit := compute_budget.MaxComputeUnitLimit
+	// round off the float error first, e.g. 450 * 1.1 = 495.00000000000006
+	units := math.Ceil(math.Round(float64(unitsConsumed)*(1+param.UnitsMargin)*1000) / 1000)
+	if units < float64(limit) {
+		limit = uint32(units)
+	}
+
+	price, err := c.recentComputeUnitPrice(ctx, param.FeePayer, instructions, param.PriorityFeeStrategy)
+	if err != nil {
+		return TuneComputeBudgetResult{}, err
+	}
+	if param.MaxComputeUnitPrice > 0 && price > param.MaxComputeUnitPrice {
+		price = param.MaxComputeUnitPrice
+	}
+
+	return TuneComputeBudgetResult{
+		Instructions: append([]types.Instruction{
+			compute_budget.SetComputeUnitLimit(compute_budget.SetComputeUnitLimitParam{
+				Units: limit,
+			}),
+			compute_budget.SetComputeUnitPrice(compute_budget.SetComputeUnitPriceParam{
+				MicroLamports: price,
+			}),
+		}, instructions...),
+		UnitsConsumed:    unitsConsumed,
+		ComputeUnitLimit: limit,
+		ComputeUnitPrice: price,
+	}, nil
+}
+
+func isComputeBudgetInstruction(instruction types.Instruction, kind compute_budget.Instruction) bool {
+	return instruction.ProgramID == common.ComputeBudgetProgramID &&
+		len(instruction.Data) > 0 &&
+		compute_budget.Instruction(instruction.Data[0]) == kind
+}
+
+func (c *Client) simulateUnitsConsumed(ctx context.Context, param TuneComputeBudgetParam, instructions []types.Instruction) (uint64, error) {
+	message := types.NewMessage(types.NewMessageParam{
+		FeePayer:     param.FeePayer,
+		Instructions: instructions,
+		// replaced by the node
+		RecentBlockhash:            common.PublicKey{}.ToBase58(),
+		AddressLookupTableAccounts: param.AddressLookupTableAccounts,
+	})
+	tx, err := types.NewTransaction(types.NewTransactionParam{Message: message})
+	if err != nil {
+		return 0, fmt.Errorf("failed to create tx, err: %v", err)
+	}
+	rawTx, err := tx.Serialize()
+	if err != nil {
+		return 0, fmt.Errorf("failed to serialize tx, err: %v", err)
+	}
+
+	simulation, err := process(
+		func() (rpc.JsonRpcResponse[rpc.ValueWithContext[rpc.SimulateTransactionValue]], error) {
+			return c.RpcClient.SimulateTransactionWithConfig(
+				ctx,
+				base64.StdEncoding.EncodeToString(rawTx),
+				SimulateTransactionConfig{
+					Commitment:             param.Commitment,
+					ReplaceRecentBlockhash: true,
+				}.toRpc(),
+			)
+		},
+		convertSimulateTransactionOf(tx),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to simulate tx, err: %w", err)
+	}
+	if simulation.Err != nil {
+		if simulation.InstructionFailure != nil {
+			return 0, fmt.Errorf("simulation failed, err: %w", simulation.InstructionFailure)
+		}
+		return 0, fmt.Errorf("simulation failed, err: %w", simulation.Err)
+	}
+	if simulation.UnitConsumed == nil {
+		return 0, errors.New("simulation returned no units consumed")
+	}
+	return *simulation.UnitConsumed, nil
+}
+
+func (c *Client) recentComputeUnitPrice(ctx context.Context, feePayer common.PublicKey, instructions []types.Instruction, strategy PriorityFeeStrategy) (uint64, error) {
+	accounts := writableAccounts(feePayer, instructions)
+	if len(accounts) > maxPrioritizationFeeAccounts {
+		accounts = accounts[:maxPrioritizationFeeAccounts]
+	}
+
+	recentFees, err := c.GetRecentPrioritizationFees(ctx, accounts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get recent prioritization fees, err: %w", err)
+	}
+	if len(recentFees) == 0 {
+		return 0, nil
+	}
+
+	fees := make([]uint64, 0, len(recentFees))
+	for _, f := range recentFees {
+		fees = append(fees, f.PrioritizationFee)
+	}
+	sort.Slice(fees, func(i, j int) bool { return fees[i] < fees[j] })
+	return strategy.Pick(fees), nil
+}
+
+// writableAccounts returns the writable accounts in order of appearance, the fee payer first
+func writableAccounts(feePayer common.PublicKey, instructions []types.Instruction) []common.PublicKey {
+	seen := map[common.PublicKey]bool{feePayer: true}
+	accounts := []common.PublicKey{feePayer}
+	for _, instruction := range instructions {
+		for _, account := range instruction.Accounts {
+			if account.IsWritable && !seen[account.PubKey] {
+				seen[account.PubKey] = true
+				accounts = append(accounts, account.PubKey)
+			}
+		}
+	}
+	return accounts
+}
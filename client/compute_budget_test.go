@@ -0,0 +1,134 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/program/compute_budget"
+	"github.com/labyla/solana-go-sdk/program/system"
+	"github.com/labyla/solana-go-sdk/rpc"
+	"github.com/labyla/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newComputeBudgetServer(t *testing.T, simulation string, fees string, addresses *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		assert.Nil(t, err)
+		var r struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		assert.Nil(t, json.Unmarshal(body, &r))
+
+		switch r.Method {
+		case "simulateTransaction":
+			var rawTx string
+			var cfg rpc.SimulateTransactionConfig
+			assert.Nil(t, json.Unmarshal(r.Params[0], &rawTx))
+			assert.Nil(t, json.Unmarshal(r.Params[1], &cfg))
+			assert.True(t, cfg.ReplaceRecentBlockhash)
+
+			b, err := base64.StdEncoding.DecodeString(rawTx)
+			assert.Nil(t, err)
+			tx, err := types.TransactionDeserialize(b)
+			assert.Nil(t, err)
+			// simulated with the max limit and without the price
+			instructions := tx.Message.DecompileInstructions()
+			assert.Equal(t, compute_budget.SetComputeUnitLimit(compute_budget.SetComputeUnitLimitParam{
+				Units: compute_budget.MaxComputeUnitLimit,
+			}).Data, instructions[0].Data)
+			for _, instruction := range instructions[1:] {
+				assert.NotEqual(t, common.ComputeBudgetProgramID, instruction.ProgramID)
+			}
+
+			fmt.Fprintf(rw, `{"jsonrpc":"2.0","result":{"context":{"slot":1},"value":%v},"id":1}`, simulation)
+		case "getRecentPrioritizationFees":
+			assert.Nil(t, json.Unmarshal(r.Params[0], addresses))
+			fmt.Fprintf(rw, `{"jsonrpc":"2.0","result":%v,"id":1}`, fees)
+		default:
+			t.Errorf("unexpected method %v", r.Method)
+		}
+	}))
+}
+
+func newTuneComputeBudgetParam() TuneComputeBudgetParam {
+	feePayer := common.PublicKeyFromString("RNfp4xTbBb4C3kcv2KqtAj8mu4YhMHxqm1Skg9uchZ7")
+	return TuneComputeBudgetParam{
+		FeePayer: feePayer,
+		Instructions: []types.Instruction{
+			compute_budget.SetComputeUnitPrice(compute_budget.SetComputeUnitPriceParam{MicroLamports: 1}),
+			system.Transfer(system.TransferParam{
+				From:   feePayer,
+				To:     common.PublicKeyFromString("A4iUVr5KjmsLymUcv4eSKPedUtoaBceiPeGipKMYc69b"),
+				Amount: 1,
+			}),
+		},
+	}
+}
+
+func TestClient_TuneComputeBudget(t *testing.T) {
+	var addresses []string
+	server := newComputeBudgetServer(t,
+		`{"accounts":null,"err":null,"logs":[],"returnData":null,"unitsConsumed":450}`,
+		`[{"slot":1,"prioritizationFee":0},{"slot":2,"prioritizationFee":5000},{"slot":3,"prioritizationFee":100},{"slot":4,"prioritizationFee":2000}]`,
+		&addresses,
+	)
+	defer server.Close()
+
+	param := newTuneComputeBudgetParam()
+	res, err := NewClient(server.URL).TuneComputeBudget(context.Background(), param)
+	require.Nil(t, err)
+
+	assert.Equal(t, []string{
+		"RNfp4xTbBb4C3kcv2KqtAj8mu4YhMHxqm1Skg9uchZ7",
+		"A4iUVr5KjmsLymUcv4eSKPedUtoaBceiPeGipKMYc69b",
+	}, addresses)
+	assert.Equal(t, uint64(450), res.UnitsConsumed)
+	assert.Equal(t, uint32(495), res.ComputeUnitLimit)
+	assert.Equal(t, uint64(2000), res.ComputeUnitPrice)
+	assert.Equal(t, []types.Instruction{
+		compute_budget.SetComputeUnitLimit(compute_budget.SetComputeUnitLimitParam{Units: 495}),
+		compute_budget.SetComputeUnitPrice(compute_budget.SetComputeUnitPriceParam{MicroLamports: 2000}),
+		param.Instructions[1],
+	}, res.Instructions)
+
+	// cap and strategy
+	param.MaxComputeUnitPrice = 3000
+	param.PriorityFeeStrategy = PercentilePriorityFee(100)
+	param.UnitsMargin = 0.5
+	res, err = NewClient(server.URL).TuneComputeBudget(context.Background(), param)
+	require.Nil(t, err)
+	assert.Equal(t, uint32(675), res.ComputeUnitLimit)
+	assert.Equal(t, uint64(3000), res.ComputeUnitPrice)
+}
+
+func TestClient_TuneComputeBudget_SimulationFailed(t *testing.T) {
+	var addresses []string
+	server := newComputeBudgetServer(t,
+		`{"accounts":null,"err":{"InstructionError":[1,{"Custom":1}]},"logs":[],"returnData":null,"unitsConsumed":150}`,
+		`[]`,
+		&addresses,
+	)
+	defer server.Close()
+
+	_, err := NewClient(server.URL).TuneComputeBudget(context.Background(), newTuneComputeBudgetParam())
+	assert.ErrorIs(t, err, system.ErrResultWithNegativeLamports)
+}
+
+func TestPercentilePriorityFee(t *testing.T) {
+	fees := []uint64{0, 0, 10, 20, 30, 40, 50, 60, 70, 80}
+	assert.Equal(t, uint64(0), PercentilePriorityFee(0).Pick(fees))
+	assert.Equal(t, uint64(30), PercentilePriorityFee(50).Pick(fees))
+	assert.Equal(t, uint64(60), PercentilePriorityFee(75).Pick(fees))
+	assert.Equal(t, uint64(80), PercentilePriorityFee(100).Pick(fees))
+	assert.Equal(t, uint64(80), PercentilePriorityFee(150).Pick(fees))
+}
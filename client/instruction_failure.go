@@ -0,0 +1,56 @@
+package client
+
+import (
+	"fmt"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/program"
+	"github.com/labyla/solana-go-sdk/rpc"
+	"github.com/labyla/solana-go-sdk/types"
+)
+
+// InstructionFailure describes the instruction which failed a transaction
+type InstructionFailure struct {
+	// Index is the index of the failed instruction in the message
+	Index     uint8
+	ProgramID common.PublicKey
+	// Err is a *program.CustomError for a custom program error, otherwise the
+	// *rpc.InstructionError itself
+	Err error
+}
+
+func (f *InstructionFailure) Error() string {
+	return fmt.Sprintf("instruction #%v of program %v failed: %v", f.Index, f.ProgramID, f.Err)
+}
+
+func (f *InstructionFailure) Unwrap() error {
+	return f.Err
+}
+
+// newInstructionFailure resolves the failed program of an instruction error. accountKeys
+// are the static keys followed by the loaded addresses. it returns nil if the transaction
+// didn't fail on an instruction.
+func newInstructionFailure(msg types.Message, accountKeys []common.PublicKey, txErr *rpc.TransactionError) *InstructionFailure {
+	if txErr == nil || txErr.InstructionError == nil {
+		return nil
+	}
+	ie := txErr.InstructionError
+	if int(ie.Index) >= len(msg.Instructions) {
+		return nil
+	}
+	programIDIndex := msg.Instructions[ie.Index].ProgramIDIndex
+	if programIDIndex < 0 || programIDIndex >= len(accountKeys) {
+		return nil
+	}
+	programID := accountKeys[programIDIndex]
+
+	var err error = ie
+	if ie.Custom != nil {
+		err = program.DecodeError(programID, *ie.Custom)
+	}
+	return &InstructionFailure{
+		Index:     ie.Index,
+		ProgramID: programID,
+		Err:       err,
+	}
+}
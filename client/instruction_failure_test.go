@@ -0,0 +1,58 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/pkg/pointer"
+	"github.com/labyla/solana-go-sdk/program/system"
+	"github.com/labyla/solana-go-sdk/rpc"
+	"github.com/labyla/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_newInstructionFailure(t *testing.T) {
+	feePayer := common.PublicKeyFromString("RNfp4xTbBb4C3kcv2KqtAj8mu4YhMHxqm1Skg9uchZ7")
+	msg := types.NewMessage(types.NewMessageParam{
+		FeePayer: feePayer,
+		Instructions: []types.Instruction{
+			system.Transfer(system.TransferParam{
+				From:   feePayer,
+				To:     common.PublicKeyFromString("A4iUVr5KjmsLymUcv4eSKPedUtoaBceiPeGipKMYc69b"),
+				Amount: 1,
+			}),
+		},
+		RecentBlockhash: "9rAtxuhtKn8qagc3UtZFyhLrw5zgh6vx16qhHzzaJDK4",
+	})
+
+	failure := newInstructionFailure(msg, msg.Accounts, &rpc.TransactionError{
+		Kind: rpc.TransactionErrorInstructionError,
+		InstructionError: &rpc.InstructionError{
+			Index:  0,
+			Kind:   rpc.InstructionErrorCustom,
+			Custom: pointer.Get[uint32](1),
+		},
+	})
+	assert.Equal(t, uint8(0), failure.Index)
+	assert.Equal(t, common.SystemProgramID, failure.ProgramID)
+	assert.ErrorIs(t, failure, system.ErrResultWithNegativeLamports)
+
+	// builtin instruction error
+	ie := &rpc.InstructionError{Index: 0, Kind: "InvalidAccountData"}
+	failure = newInstructionFailure(msg, msg.Accounts, &rpc.TransactionError{
+		Kind:             rpc.TransactionErrorInstructionError,
+		InstructionError: ie,
+	})
+	assert.Equal(t, common.SystemProgramID, failure.ProgramID)
+	assert.Equal(t, ie, failure.Err)
+
+	// not an instruction error
+	assert.Nil(t, newInstructionFailure(msg, msg.Accounts, &rpc.TransactionError{Kind: rpc.TransactionErrorBlockhashNotFound}))
+	assert.Nil(t, newInstructionFailure(msg, msg.Accounts, nil))
+
+	// index out of range
+	assert.Nil(t, newInstructionFailure(msg, msg.Accounts, &rpc.TransactionError{
+		Kind:             rpc.TransactionErrorInstructionError,
+		InstructionError: &rpc.InstructionError{Index: 3, Kind: "InvalidAccountData"},
+	}))
+}
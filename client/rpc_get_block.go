@@ -47,6 +47,12 @@ type BlockTransaction struct {
 	AccountKeys []common.PublicKey
 }
 
+// DecompileInstructions returns the instructions of the transaction. the addresses of
+// a v0 message are resolved with the loaded addresses in the meta.
+func (t BlockTransaction) DecompileInstructions() ([]types.Instruction, error) {
+	return decompileInstructions(t.Transaction.Message, t.Meta)
+}
+
 func (c *Client) GetBlock(ctx context.Context, slot uint64) (*Block, error) {
 	return process(
 		func() (rpc.JsonRpcResponse[*rpc.GetBlock], error) {
@@ -89,6 +95,9 @@ func convertBlock(v *rpc.GetBlock) (*Block, error) {
 			if err != nil {
 				return nil, fmt.Errorf("failed to parse tx, err: %v", err)
 			}
+			if transactionMeta != nil {
+				transactionMeta.InstructionFailure = newInstructionFailure(tx.Message, accountKeys, transactionMeta.Err)
+			}
 
 			txs = append(txs, BlockTransaction{
 				Meta:        transactionMeta,
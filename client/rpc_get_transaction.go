@@ -39,8 +39,14 @@ func (t Transaction) Version() types.MessageVersion {
 	return t.Transaction.Message.Version
 }
 
+// DecompileInstructions returns the instructions of the transaction. the addresses of
+// a v0 message are resolved with the loaded addresses in the meta.
+func (t Transaction) DecompileInstructions() ([]types.Instruction, error) {
+	return decompileInstructions(t.Transaction.Message, t.Meta)
+}
+
 type TransactionMeta struct {
-	Err                  any
+	Err                  *rpc.TransactionError
 	Fee                  uint64
 	PreBalances          []int64
 	PostBalances         []int64
@@ -51,6 +57,8 @@ type TransactionMeta struct {
 	LoadedAddresses      rpc.TransactionLoadedAddresses
 	ReturnData           *ReturnData
 	ComputeUnitsConsumed *uint64
+	// InstructionFailure resolves the failed program when Err is an instruction error
+	InstructionFailure *InstructionFailure
 }
 
 type InnerInstruction struct {
@@ -93,6 +101,9 @@ func convertTransaction(v *rpc.GetTransaction) (*Transaction, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse tx, err: %v", err)
 	}
+	if transactionMeta != nil {
+		transactionMeta.InstructionFailure = newInstructionFailure(tx.Message, accountKeys, transactionMeta.Err)
+	}
 
 	return &Transaction{
 		Slot:        v.Slot,
@@ -172,6 +183,19 @@ func convertTransactionMeta(meta *rpc.TransactionMeta) (*TransactionMeta, error)
 	}, nil
 }
 
+func decompileInstructions(msg types.Message, meta *TransactionMeta) ([]types.Instruction, error) {
+	loaded := types.LoadedAddresses{}
+	if meta != nil {
+		for _, s := range meta.LoadedAddresses.Writable {
+			loaded.Writable = append(loaded.Writable, common.PublicKeyFromString(s))
+		}
+		for _, s := range meta.LoadedAddresses.Readonly {
+			loaded.Readonly = append(loaded.Readonly, common.PublicKeyFromString(s))
+		}
+	}
+	return msg.DecompileInstructionsWithLoadedAddresses(loaded)
+}
+
 func parseBase64Tx(raw any, transactionMeta *TransactionMeta) (types.Transaction, []common.PublicKey, error) {
 	// transaction
 	data, ok := raw.([]any)
@@ -10,6 +10,7 @@ import (
 	"github.com/labyla/solana-go-sdk/rpc"
 	"github.com/labyla/solana-go-sdk/types"
 	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestClient_GetTransaction(t *testing.T) {
@@ -456,3 +457,84 @@ func mustBase58Decode(t *testing.T, s string) []byte {
 	}
 	return b
 }
+
+func TestTransaction_DecompileInstructions(t *testing.T) {
+	tx := Transaction{
+		Meta: &TransactionMeta{
+			LoadedAddresses: rpc.TransactionLoadedAddresses{
+				Writable: []string{"3Yvq7e9UXLoFK4PKyxrpEA3y3TKmFK2Wb1f5tVFUgwPu", "5McxjaxNKYLHtv9DqbMfoi6GNs7ZEMHGkJDrouPib4sW", "GAXzq8BWdAWaS1kWFiL5tzV2h3AbRBtYGP5psNTWrM9g"},
+				Readonly: []string{"F1rcBbZB6tQZUTR2z8jKQxaAwUUkxnghSh941Q62hMi8", "5jHeQFBSNxFqqkMF9YCYwtJbkzGarSGwGsmi2ZuPG6yw"},
+			},
+		},
+		Transaction: types.Transaction{
+			Message: types.Message{
+				Version: types.MessageVersionV0,
+				Header: types.MessageHeader{
+					NumRequireSignatures:        1,
+					NumReadonlySignedAccounts:   0,
+					NumReadonlyUnsignedAccounts: 1,
+				},
+				Accounts: []common.PublicKey{
+					common.PublicKeyFromString("RNfp4xTbBb4C3kcv2KqtAj8mu4YhMHxqm1Skg9uchZ7"),
+					common.PublicKeyFromString("HqXcr9ja8jTZAfWN4YSSL8PPWFN3BFJsoxrCvSLaqww1"),
+					common.PublicKeyFromString("TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA"),
+				},
+				RecentBlockHash: "5dQEKfLJt77vfrw2UxWrPrDFwFmxRui6Rk6FBjGnuZBg",
+				Instructions: []types.CompiledInstruction{
+					{
+						ProgramIDIndex: 2,
+						Accounts:       []int{3, 6, 5, 0},
+						Data:           []byte{12, 1, 0, 0, 0, 0, 0, 0, 0, 0},
+					},
+					{
+						ProgramIDIndex: 2,
+						Accounts:       []int{4, 7, 1, 0},
+						Data:           []byte{12, 1, 0, 0, 0, 0, 0, 0, 0, 0},
+					},
+				},
+				AddressLookupTables: []types.CompiledAddressLookupTable{
+					{
+						AccountKey:      common.PublicKeyFromString("77hNYFDx74WFBD1jfM1gHFYk3naH8CxLzLG4KRJAHcRv"),
+						ReadonlyIndexes: []uint8{0},
+						WritableIndexes: []uint8{1, 2},
+					},
+					{
+						AccountKey:      common.PublicKeyFromString("ByNnrePVpmJTXGiU3Nm9UxTN36tsbaahQcvUNFWmX2Do"),
+						ReadonlyIndexes: []uint8{0},
+						WritableIndexes: []uint8{2},
+					},
+				},
+			},
+		},
+	}
+
+	instructions, err := tx.DecompileInstructions()
+	assert.Nil(t, err)
+	assert.Equal(t, []types.Instruction{
+		{
+			ProgramID: common.TokenProgramID,
+			Accounts: []types.AccountMeta{
+				{PubKey: common.PublicKeyFromString("3Yvq7e9UXLoFK4PKyxrpEA3y3TKmFK2Wb1f5tVFUgwPu"), IsSigner: false, IsWritable: true},
+				{PubKey: common.PublicKeyFromString("F1rcBbZB6tQZUTR2z8jKQxaAwUUkxnghSh941Q62hMi8"), IsSigner: false, IsWritable: false},
+				{PubKey: common.PublicKeyFromString("GAXzq8BWdAWaS1kWFiL5tzV2h3AbRBtYGP5psNTWrM9g"), IsSigner: false, IsWritable: true},
+				{PubKey: common.PublicKeyFromString("RNfp4xTbBb4C3kcv2KqtAj8mu4YhMHxqm1Skg9uchZ7"), IsSigner: true, IsWritable: true},
+			},
+			Data: []byte{12, 1, 0, 0, 0, 0, 0, 0, 0, 0},
+		},
+		{
+			ProgramID: common.TokenProgramID,
+			Accounts: []types.AccountMeta{
+				{PubKey: common.PublicKeyFromString("5McxjaxNKYLHtv9DqbMfoi6GNs7ZEMHGkJDrouPib4sW"), IsSigner: false, IsWritable: true},
+				{PubKey: common.PublicKeyFromString("5jHeQFBSNxFqqkMF9YCYwtJbkzGarSGwGsmi2ZuPG6yw"), IsSigner: false, IsWritable: false},
+				{PubKey: common.PublicKeyFromString("HqXcr9ja8jTZAfWN4YSSL8PPWFN3BFJsoxrCvSLaqww1"), IsSigner: false, IsWritable: true},
+				{PubKey: common.PublicKeyFromString("RNfp4xTbBb4C3kcv2KqtAj8mu4YhMHxqm1Skg9uchZ7"), IsSigner: true, IsWritable: true},
+			},
+			Data: []byte{12, 1, 0, 0, 0, 0, 0, 0, 0, 0},
+		},
+	}, instructions)
+
+	// without meta the loaded addresses are unknown
+	tx.Meta = nil
+	_, err = tx.DecompileInstructions()
+	assert.ErrorIs(t, err, types.ErrMessageLoadedAddressesMismatch)
+}
@@ -10,11 +10,13 @@ import (
 )
 
 type SimulateTransaction struct {
-	Err          any
+	Err          *rpc.TransactionError
 	Logs         []string
 	Accounts     []*AccountInfo
 	ReturnData   *ReturnData
 	UnitConsumed *uint64
+	// InstructionFailure resolves the failed program when Err is an instruction error
+	InstructionFailure *InstructionFailure
 }
 
 type SimulateTransactionConfig struct {
@@ -54,7 +56,7 @@ func (c *Client) SimulateTransaction(ctx context.Context, tx types.Transaction)
 				SimulateTransactionConfig{}.toRpc(),
 			)
 		},
-		convertSimulateTransaction,
+		convertSimulateTransactionOf(tx),
 	)
 }
 
@@ -71,7 +73,7 @@ func (c *Client) SimulateTransactionWithConfig(ctx context.Context, tx types.Tra
 				cfg.toRpc(),
 			)
 		},
-		convertSimulateTransaction,
+		convertSimulateTransactionOf(tx),
 	)
 }
 
@@ -88,7 +90,7 @@ func (c *Client) SimulateTransactionAndContext(ctx context.Context, tx types.Tra
 				SimulateTransactionConfig{}.toRpc(),
 			)
 		},
-		convertSimulateTransactionAndContext,
+		convertSimulateTransactionAndContextOf(tx),
 	)
 }
 
@@ -105,7 +107,7 @@ func (c *Client) SimulateTransactionAndContextWithConfig(ctx context.Context, tx
 				cfg.toRpc(),
 			)
 		},
-		convertSimulateTransactionAndContext,
+		convertSimulateTransactionAndContextOf(tx),
 	)
 }
 
@@ -135,8 +137,13 @@ func convertSimulateTransaction(v rpc.ValueWithContext[rpc.SimulateTransactionVa
 		returnData = &d
 	}
 
+	txErr, err := rpc.ParseTransactionError(v.Value.Err)
+	if err != nil {
+		return SimulateTransaction{}, fmt.Errorf("failed to parse transaction error, err: %v", err)
+	}
+
 	return SimulateTransaction{
-		Err:          v.Value.Err,
+		Err:          txErr,
 		Logs:         v.Value.Logs,
 		Accounts:     accountInfos,
 		ReturnData:   returnData,
@@ -154,3 +161,26 @@ func convertSimulateTransactionAndContext(v rpc.ValueWithContext[rpc.SimulateTra
 		Value:   simulateTrasaction,
 	}, nil
 }
+
+// convertSimulateTransactionOf resolves the failed program from the simulated tx
+func convertSimulateTransactionOf(tx types.Transaction) func(rpc.ValueWithContext[rpc.SimulateTransactionValue]) (SimulateTransaction, error) {
+	return func(v rpc.ValueWithContext[rpc.SimulateTransactionValue]) (SimulateTransaction, error) {
+		output, err := convertSimulateTransaction(v)
+		if err != nil {
+			return SimulateTransaction{}, err
+		}
+		output.InstructionFailure = newInstructionFailure(tx.Message, tx.Message.Accounts, output.Err)
+		return output, nil
+	}
+}
+
+func convertSimulateTransactionAndContextOf(tx types.Transaction) func(rpc.ValueWithContext[rpc.SimulateTransactionValue]) (rpc.ValueWithContext[SimulateTransaction], error) {
+	return func(v rpc.ValueWithContext[rpc.SimulateTransactionValue]) (rpc.ValueWithContext[SimulateTransaction], error) {
+		output, err := convertSimulateTransactionAndContext(v)
+		if err != nil {
+			return rpc.ValueWithContext[SimulateTransaction]{}, err
+		}
+		output.Value.InstructionFailure = newInstructionFailure(tx.Message, tx.Message.Accounts, output.Value.Err)
+		return output, nil
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/labyla/solana-go-sdk/common"
 	"github.com/labyla/solana-go-sdk/internal/client_test"
 	"github.com/labyla/solana-go-sdk/pkg/pointer"
+	"github.com/labyla/solana-go-sdk/program"
 	"github.com/labyla/solana-go-sdk/rpc"
 )
 
@@ -41,6 +42,43 @@ func TestClient_SimulateTransaction(t *testing.T) {
 				},
 				ExpectedError: nil,
 			},
+			{
+				RequestBody:  `{"jsonrpc":"2.0","id":1,"method":"simulateTransaction","params":["Ab/yMEK7qNgGxaPMg2XaVnwwLMqnY8FTeJrA9qJ1nOBFX08BHycnp3/9WOxOY53+eZnbkT2/+6Mx7w+DsuVN8ggBAAECBj5w2ZFXmNyj7tuRN89kxw/6+2LN04KBBSUL12sdbN4e0EmQh0otX6HS7HumAryrMtxCzacgpjtG6MY9cJWYYEsGZsdWhvaw9ENEPFBEi4eBna4CphPQWWcgU4yARSnVAQEAAA==", {"encoding": "base64"}]}`,
+				ResponseBody: `{"jsonrpc":"2.0","result":{"context":{"apiVersion":"1.14.5","slot":159776096},"value":{"accounts":null,"err":{"InstructionError":[0,{"Custom":1}]},"logs":["Program 35HSbe2xiLfid5QJeETGnUsGhkAiJWRKPrEGdQQ5xXrP invoke [1]","Program 35HSbe2xiLfid5QJeETGnUsGhkAiJWRKPrEGdQQ5xXrP consumed 185 of 200000 compute units","Program 35HSbe2xiLfid5QJeETGnUsGhkAiJWRKPrEGdQQ5xXrP failed: custom program error: 0x1"],"returnData":null,"unitsConsumed":185}},"id":1}`,
+				F: func(url string) (any, error) {
+					c := NewClient(url)
+					return c.SimulateTransaction(
+						context.Background(),
+						tx,
+					)
+				},
+				ExpectedValue: SimulateTransaction{
+					Err: &rpc.TransactionError{
+						Kind: rpc.TransactionErrorInstructionError,
+						InstructionError: &rpc.InstructionError{
+							Index:  0,
+							Kind:   rpc.InstructionErrorCustom,
+							Custom: pointer.Get[uint32](1),
+						},
+					},
+					Accounts: nil,
+					Logs: []string{
+						"Program 35HSbe2xiLfid5QJeETGnUsGhkAiJWRKPrEGdQQ5xXrP invoke [1]",
+						"Program 35HSbe2xiLfid5QJeETGnUsGhkAiJWRKPrEGdQQ5xXrP consumed 185 of 200000 compute units",
+						"Program 35HSbe2xiLfid5QJeETGnUsGhkAiJWRKPrEGdQQ5xXrP failed: custom program error: 0x1",
+					},
+					UnitConsumed: pointer.Get[uint64](185),
+					InstructionFailure: &InstructionFailure{
+						Index:     0,
+						ProgramID: common.PublicKeyFromString("35HSbe2xiLfid5QJeETGnUsGhkAiJWRKPrEGdQQ5xXrP"),
+						Err: &program.CustomError{
+							ProgramID: common.PublicKeyFromString("35HSbe2xiLfid5QJeETGnUsGhkAiJWRKPrEGdQQ5xXrP"),
+							Code:      1,
+						},
+					},
+				},
+				ExpectedError: nil,
+			},
 		},
 	)
 }
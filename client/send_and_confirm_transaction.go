@@ -0,0 +1,212 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/program/system"
+	"github.com/labyla/solana-go-sdk/rpc"
+	"github.com/labyla/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+)
+
+// SendAndConfirmTransactionStatus is the outcome of SendAndConfirmTransaction
+type SendAndConfirmTransactionStatus int
+
+const (
+	// SendAndConfirmTransactionStatusConfirmed means the transaction landed and
+	// reached the target commitment. it may still have failed on execution.
+	SendAndConfirmTransactionStatusConfirmed SendAndConfirmTransactionStatus = iota
+	// SendAndConfirmTransactionStatusExpired means the block height passed the last
+	// valid block height of the blockhash, the transaction can never land.
+	SendAndConfirmTransactionStatusExpired
+	// SendAndConfirmTransactionStatusFailed means the node rejected the transaction,
+	// e.g. the preflight simulation failed.
+	SendAndConfirmTransactionStatusFailed
+)
+
+func (s SendAndConfirmTransactionStatus) String() string {
+	switch s {
+	case SendAndConfirmTransactionStatusConfirmed:
+		return "confirmed"
+	case SendAndConfirmTransactionStatusExpired:
+		return "expired"
+	case SendAndConfirmTransactionStatusFailed:
+		return "failed"
+	}
+	return fmt.Sprintf("SendAndConfirmTransactionStatus(%d)", int(s))
+}
+
+type SendAndConfirmTransactionConfig struct {
+	SkipPreflight       bool
+	PreflightCommitment rpc.Commitment
+	// Commitment is the commitment to wait for. default: confirmed
+	Commitment rpc.Commitment
+	// LastValidBlockHeight is returned by GetLatestBlockhash with the blockhash of the
+	// tx. if it is zero, the blockhash of the tx is checked with isBlockhashValid instead,
+	// so the node must already know it. it is ignored for a durable nonce tx.
+	LastValidBlockHeight uint64
+	// PollInterval is how often the status is checked. default: 500ms
+	PollInterval time.Duration
+	// RebroadcastInterval is how often the tx is sent again until it lands. default: 2s
+	RebroadcastInterval time.Duration
+}
+
+type SendAndConfirmTransactionResult struct {
+	Status    SendAndConfirmTransactionStatus
+	Signature string
+	// Slot is the slot the transaction landed in, set when confirmed
+	Slot uint64
+	// Err is the execution error of a confirmed transaction, as a *rpc.TransactionError,
+	// or why the node rejected a failed one, usually a *rpc.JsonRpcError
+	Err error
+}
+
+var commitmentLevel = map[rpc.Commitment]int{
+	rpc.CommitmentProcessed: 0,
+	rpc.CommitmentConfirmed: 1,
+	rpc.CommitmentFinalized: 2,
+}
+
+// reached reports whether the status is at least at the commitment
+func reached(status *rpc.SignatureStatus, commitment rpc.Commitment) bool {
+	if status.ConfirmationStatus == nil {
+		// old nodes only report confirmations, which is nil once rooted
+		return status.Confirmations == nil || commitment != rpc.CommitmentFinalized
+	}
+	return commitmentLevel[*status.ConfirmationStatus] >= commitmentLevel[commitment]
+}
+
+// SendAndConfirmTransaction sends the tx and waits until it reaches the commitment or its
+// blockhash expires. the raw tx is rebroadcast until it lands. the error is only returned
+// when the outcome is unknown, e.g. ctx is done. a durable nonce tx, whose first instruction
+// advances a nonce account, never expires, so it is only bounded by ctx.
+func (c *Client) SendAndConfirmTransaction(ctx context.Context, tx types.Transaction, cfg SendAndConfirmTransactionConfig) (SendAndConfirmTransactionResult, error) {
+	if len(tx.Signatures) == 0 {
+		return SendAndConfirmTransactionResult{}, errors.New("tx has no signature")
+	}
+	if cfg.Commitment == "" {
+		cfg.Commitment = rpc.CommitmentConfirmed
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = 500 * time.Millisecond
+	}
+	if cfg.RebroadcastInterval <= 0 {
+		cfg.RebroadcastInterval = 2 * time.Second
+	}
+
+	rawTx, err := tx.Serialize()
+	if err != nil {
+		return SendAndConfirmTransactionResult{}, fmt.Errorf("failed to serialize tx, err: %v", err)
+	}
+	encodedTx := base64.StdEncoding.EncodeToString(rawTx)
+	result := SendAndConfirmTransactionResult{
+		Signature: base58.Encode(tx.Signatures[0]),
+	}
+
+	durableNonce := usesDurableNonce(tx.Message)
+	expired := func() (bool, error) {
+		if durableNonce {
+			return false, nil
+		}
+		if cfg.LastValidBlockHeight == 0 {
+			// processed knows the most blockhashes, a blockhash is never reported
+			// invalid just because it isn't confirmed yet
+			valid, err := c.IsBlockhashValidWithConfig(ctx, tx.Message.RecentBlockHash, IsBlockhashValidConfig{Commitment: rpc.CommitmentProcessed})
+			return !valid, err
+		}
+		blockHeight, err := c.GetBlockHeightWithConfig(ctx, GetBlockHeightConfig{Commitment: cfg.Commitment})
+		return blockHeight > cfg.LastValidBlockHeight, err
+	}
+
+	send := func(skipPreflight bool) error {
+		res, err := c.RpcClient.SendTransactionWithConfig(ctx, encodedTx, rpc.SendTransactionConfig{
+			Encoding:            rpc.SendTransactionConfigEncodingBase64,
+			SkipPreflight:       skipPreflight,
+			PreflightCommitment: cfg.PreflightCommitment,
+			MaxRetries:          0,
+		})
+		if err != nil {
+			return err
+		}
+		if res.Error != nil {
+			return res.Error
+		}
+		return nil
+	}
+
+	if err := send(cfg.SkipPreflight); err != nil {
+		var rpcErr *rpc.JsonRpcError
+		if errors.As(err, &rpcErr) {
+			result.Status = SendAndConfirmTransactionStatusFailed
+			result.Err = rpcErr
+			return result, nil
+		}
+		return result, fmt.Errorf("failed to send tx, err: %v", err)
+	}
+	lastBroadcast := time.Now()
+
+	ticker := time.NewTicker(cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return result, ctx.Err()
+		case <-ticker.C:
+		}
+
+		// the expiry is checked before the status, so a nil status after the
+		// blockhash expired means the tx can't land anymore
+		isExpired, expiredErr := expired()
+		status, err := c.GetSignatureStatus(ctx, result.Signature)
+		if err != nil {
+			// transient, the next poll will tell
+			continue
+		}
+
+		if status != nil {
+			if !reached(status, cfg.Commitment) {
+				continue
+			}
+			txErr, err := rpc.ParseTransactionError(status.Err)
+			if err != nil {
+				return result, fmt.Errorf("failed to parse transaction error, err: %v", err)
+			}
+			result.Status = SendAndConfirmTransactionStatusConfirmed
+			result.Slot = status.Slot
+			if txErr != nil {
+				result.Err = txErr
+			}
+			return result, nil
+		}
+
+		if expiredErr == nil && isExpired {
+			result.Status = SendAndConfirmTransactionStatusExpired
+			return result, nil
+		}
+
+		if time.Since(lastBroadcast) >= cfg.RebroadcastInterval {
+			// the tx passed preflight already, a rebroadcast may only fail because
+			// it is already processed
+			_ = send(true)
+			lastBroadcast = time.Now()
+		}
+	}
+}
+
+// usesDurableNonce reports whether the first instruction of the message advances a nonce account
+func usesDurableNonce(message types.Message) bool {
+	if len(message.Instructions) == 0 {
+		return false
+	}
+	instruction := message.Instructions[0]
+	return instruction.ProgramIDIndex >= 0 && instruction.ProgramIDIndex < len(message.Accounts) &&
+		message.Accounts[instruction.ProgramIDIndex] == common.SystemProgramID &&
+		len(instruction.Data) == 4 &&
+		system.Instruction(binary.LittleEndian.Uint32(instruction.Data)) == system.InstructionAdvanceNonceAccount
+}
@@ -0,0 +1,223 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/program/system"
+	"github.com/labyla/solana-go-sdk/rpc"
+	"github.com/labyla/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCluster replies `sendTransaction`, `getBlockHeight`, `getSignatureStatuses` and
+// `isBlockhashValid` from its fields
+type fakeCluster struct {
+	mu          sync.Mutex
+	sendError   string
+	sends       int
+	blockHeight uint64
+	// the blockhash is valid for the first validBlockhashChecks checks
+	validBlockhashChecks int
+	blockhashChecks      int
+	// statuses are returned in order, the last one is repeated
+	statuses []string
+}
+
+func (f *fakeCluster) serve(t *testing.T) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		assert.Nil(t, err)
+		var r rpc.JsonRpcRequest
+		assert.Nil(t, json.Unmarshal(body, &r))
+
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		switch r.Method {
+		case "sendTransaction":
+			f.sends++
+			if f.sendError != "" {
+				fmt.Fprintf(rw, `{"jsonrpc":"2.0","error":%v,"id":1}`, f.sendError)
+				return
+			}
+			fmt.Fprint(rw, `{"jsonrpc":"2.0","result":"sig","id":1}`)
+		case "getBlockHeight":
+			f.blockHeight++
+			fmt.Fprintf(rw, `{"jsonrpc":"2.0","result":%d,"id":1}`, f.blockHeight)
+		case "isBlockhashValid":
+			f.blockhashChecks++
+			fmt.Fprintf(rw, `{"jsonrpc":"2.0","result":{"context":{"slot":1},"value":%v},"id":1}`, f.blockhashChecks <= f.validBlockhashChecks)
+		case "getSignatureStatuses":
+			status := f.statuses[0]
+			if len(f.statuses) > 1 {
+				f.statuses = f.statuses[1:]
+			}
+			fmt.Fprintf(rw, `{"jsonrpc":"2.0","result":{"context":{"slot":1},"value":[%v]},"id":1}`, status)
+		default:
+			t.Errorf("unexpected method %v", r.Method)
+		}
+	}))
+}
+
+func newTestTransferTx(t *testing.T, instructions ...types.Instruction) types.Transaction {
+	feePayer := types.NewAccount()
+	tx, err := types.NewTransaction(types.NewTransactionParam{
+		Message: types.NewMessage(types.NewMessageParam{
+			FeePayer: feePayer.PublicKey,
+			Instructions: append(instructions, system.Transfer(system.TransferParam{
+				From:   feePayer.PublicKey,
+				To:     common.PublicKeyFromString("A4iUVr5KjmsLymUcv4eSKPedUtoaBceiPeGipKMYc69b"),
+				Amount: 1,
+			})),
+			RecentBlockhash: "9rAtxuhtKn8qagc3UtZFyhLrw5zgh6vx16qhHzzaJDK4",
+		}),
+		Signers: []types.Signer{feePayer},
+	})
+	require.Nil(t, err)
+	return tx
+}
+
+func testSendAndConfirmConfig() SendAndConfirmTransactionConfig {
+	return SendAndConfirmTransactionConfig{
+		LastValidBlockHeight: 100,
+		PollInterval:         time.Millisecond,
+		RebroadcastInterval:  time.Nanosecond,
+	}
+}
+
+func TestClient_SendAndConfirmTransaction_Confirmed(t *testing.T) {
+	cluster := &fakeCluster{
+		statuses: []string{
+			`null`,
+			`null`,
+			`{"slot":72,"confirmations":1,"err":null,"confirmationStatus":"processed"}`,
+			`{"slot":72,"confirmations":5,"err":null,"confirmationStatus":"confirmed"}`,
+		},
+	}
+	server := cluster.serve(t)
+	defer server.Close()
+
+	tx := newTestTransferTx(t)
+	res, err := NewClient(server.URL).SendAndConfirmTransaction(context.Background(), tx, testSendAndConfirmConfig())
+	require.Nil(t, err)
+	assert.Equal(t, SendAndConfirmTransactionResult{
+		Status:    SendAndConfirmTransactionStatusConfirmed,
+		Signature: base58.Encode(tx.Signatures[0]),
+		Slot:      72,
+	}, res)
+	// rebroadcast while the status is unknown
+	assert.Equal(t, 3, cluster.sends)
+}
+
+func TestClient_SendAndConfirmTransaction_ConfirmedWithError(t *testing.T) {
+	cluster := &fakeCluster{
+		statuses: []string{
+			`{"slot":72,"confirmations":null,"err":{"InstructionError":[0,{"Custom":1}]},"confirmationStatus":"finalized"}`,
+		},
+	}
+	server := cluster.serve(t)
+	defer server.Close()
+
+	res, err := NewClient(server.URL).SendAndConfirmTransaction(context.Background(), newTestTransferTx(t), testSendAndConfirmConfig())
+	require.Nil(t, err)
+	assert.Equal(t, SendAndConfirmTransactionStatusConfirmed, res.Status)
+	assert.Equal(t, uint64(72), res.Slot)
+	assert.ErrorIs(t, res.Err, rpc.ErrTransactionInstructionError)
+}
+
+func TestClient_SendAndConfirmTransaction_Expired(t *testing.T) {
+	cluster := &fakeCluster{statuses: []string{`null`}}
+	server := cluster.serve(t)
+	defer server.Close()
+
+	cfg := testSendAndConfirmConfig()
+	cfg.LastValidBlockHeight = 3
+	res, err := NewClient(server.URL).SendAndConfirmTransaction(context.Background(), newTestTransferTx(t), cfg)
+	require.Nil(t, err)
+	assert.Equal(t, SendAndConfirmTransactionStatusExpired, res.Status)
+	assert.Nil(t, res.Err)
+	assert.Equal(t, uint64(4), cluster.blockHeight)
+	assert.Equal(t, 0, cluster.blockhashChecks)
+}
+
+func TestClient_SendAndConfirmTransaction_ExpiredBlockhash(t *testing.T) {
+	cluster := &fakeCluster{statuses: []string{`null`}, validBlockhashChecks: 2}
+	server := cluster.serve(t)
+	defer server.Close()
+
+	// without the last valid block height the blockhash of the tx is checked
+	cfg := testSendAndConfirmConfig()
+	cfg.LastValidBlockHeight = 0
+	res, err := NewClient(server.URL).SendAndConfirmTransaction(context.Background(), newTestTransferTx(t), cfg)
+	require.Nil(t, err)
+	assert.Equal(t, SendAndConfirmTransactionStatusExpired, res.Status)
+	assert.Equal(t, 3, cluster.blockhashChecks)
+	assert.Equal(t, uint64(0), cluster.blockHeight)
+}
+
+func TestClient_SendAndConfirmTransaction_DurableNonce(t *testing.T) {
+	cluster := &fakeCluster{
+		statuses: []string{
+			`null`,
+			`null`,
+			`null`,
+			`{"slot":72,"confirmations":5,"err":null,"confirmationStatus":"confirmed"}`,
+		},
+	}
+	server := cluster.serve(t)
+	defer server.Close()
+
+	// the blockhash is a nonce, so the tx doesn't expire with it
+	tx := newTestTransferTx(t, system.AdvanceNonceAccount(system.AdvanceNonceAccountParam{
+		Nonce: common.PublicKeyFromString("5ZVJgwWxMsqXxRMYHXqMwH2hd4myX5Ef4Au2iUsuNQ7V"),
+		Auth:  common.PublicKeyFromString("A4iUVr5KjmsLymUcv4eSKPedUtoaBceiPeGipKMYc69b"),
+	}))
+	cfg := testSendAndConfirmConfig()
+	cfg.LastValidBlockHeight = 1
+	res, err := NewClient(server.URL).SendAndConfirmTransaction(context.Background(), tx, cfg)
+	require.Nil(t, err)
+	assert.Equal(t, SendAndConfirmTransactionStatusConfirmed, res.Status)
+	assert.Equal(t, uint64(72), res.Slot)
+	assert.Equal(t, uint64(0), cluster.blockHeight)
+	assert.Equal(t, 0, cluster.blockhashChecks)
+}
+
+func TestClient_SendAndConfirmTransaction_Failed(t *testing.T) {
+	cluster := &fakeCluster{
+		sendError: `{"code":-32002,"message":"Transaction simulation failed: Blockhash not found","data":{"accounts":null,"err":"BlockhashNotFound","logs":[],"unitsConsumed":0}}`,
+	}
+	server := cluster.serve(t)
+	defer server.Close()
+
+	res, err := NewClient(server.URL).SendAndConfirmTransaction(context.Background(), newTestTransferTx(t), testSendAndConfirmConfig())
+	require.Nil(t, err)
+	assert.Equal(t, SendAndConfirmTransactionStatusFailed, res.Status)
+	assert.ErrorIs(t, res.Err, rpc.ErrSendTransactionPreflightFailure)
+	assert.ErrorIs(t, res.Err, rpc.ErrTransactionBlockhashNotFound)
+	assert.Equal(t, 1, cluster.sends)
+}
+
+func TestClient_SendAndConfirmTransaction_ContextDone(t *testing.T) {
+	cluster := &fakeCluster{statuses: []string{`null`}}
+	server := cluster.serve(t)
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	cfg := testSendAndConfirmConfig()
+	cfg.LastValidBlockHeight = 1 << 40
+	res, err := NewClient(server.URL).SendAndConfirmTransaction(ctx, newTestTransferTx(t), cfg)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	assert.NotEmpty(t, res.Signature)
+}
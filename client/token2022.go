@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/program/token2022"
+	"github.com/labyla/solana-go-sdk/types"
+)
+
+// AddTransferHookAccounts returns a token-2022 TransferChecked or TransferCheckedWithFee with the accounts
+// the transfer hook of the mint needs. the instruction is returned as is if the mint has no transfer hook.
+func (c *Client) AddTransferHookAccounts(ctx context.Context, instruction types.Instruction) (types.Instruction, error) {
+	if instruction.ProgramID != common.Token2022ProgramID || len(instruction.Accounts) < 4 {
+		return types.Instruction{}, fmt.Errorf("%w: not a checked transfer", token2022.ErrInvalidInstruction)
+	}
+
+	mint, err := c.GetAccountInfo(ctx, instruction.Accounts[1].PubKey.ToBase58())
+	if err != nil {
+		return types.Instruction{}, err
+	}
+	extensions, err := token2022.ParseMintExtensions(mint.Data)
+	if err != nil {
+		return types.Instruction{}, err
+	}
+	if extensions == nil || extensions.TransferHook == nil || extensions.TransferHook.ProgramId == (common.PublicKey{}) {
+		return instruction, nil
+	}
+
+	return token2022.AddTransferHookAccounts(ctx, instruction, extensions.TransferHook.ProgramId,
+		func(ctx context.Context, pubkey common.PublicKey) ([]byte, error) {
+			accountInfo, err := c.GetAccountInfo(ctx, pubkey.ToBase58())
+			if err != nil {
+				return nil, err
+			}
+			return accountInfo.Data, nil
+		},
+	)
+}
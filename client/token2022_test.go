@@ -0,0 +1,106 @@
+package client
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/program/token2022"
+	"github.com/labyla/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newAccountServer(t *testing.T, accounts map[common.PublicKey][]byte) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		assert.Nil(t, err)
+		var r struct {
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		assert.Nil(t, json.Unmarshal(body, &r))
+		assert.Equal(t, "getAccountInfo", r.Method)
+
+		var address string
+		assert.Nil(t, json.Unmarshal(r.Params[0], &address))
+		data, ok := accounts[common.PublicKeyFromString(address)]
+		if !ok {
+			fmt.Fprint(rw, `{"jsonrpc":"2.0","result":{"context":{"slot":1},"value":null},"id":1}`)
+			return
+		}
+		fmt.Fprintf(rw,
+			`{"jsonrpc":"2.0","result":{"context":{"slot":1},"value":{"data":["%v","base64"],"executable":false,"lamports":1461600,"owner":"TokenzQdBNbLqP5VEhdkAS6EPFLC1PHnBqCXEpPxuEb","rentEpoch":0}},"id":1}`,
+			base64.StdEncoding.EncodeToString(data),
+		)
+	}))
+}
+
+func TestClient_AddTransferHookAccounts(t *testing.T) {
+	mint := common.PublicKeyFromString("FtvD2ymcAFh59DGGmJkANyJzEpLDR1GLgqDrUxfe2dPm")
+	hookProgramID := common.PublicKeyFromString("8B1ytD3WMxoYQFJDk5MFn5fBgR3S7RBbVmKvfbSCuLYf")
+	extra := common.PublicKeyFromString("9qeP9DmjXAmKQc4wy133XZrQ3Fo4ejsYteA7X4YFJ3an")
+	instruction := token2022.TransferChecked(token2022.TransferCheckedParam{
+		From:     common.PublicKeyFromString("EvN4kgKmCmYzdbd5kL8Q8YgkUW5RoqMTpBczrfLExtx7"),
+		To:       common.PublicKeyFromString("5vftMkHL72JaJG6ExQfGAsT2uGVHpRR7oTNUPMs68Y2N"),
+		Mint:     mint,
+		Auth:     common.PublicKeyFromString("BkXBQ9ThbQffhmG39c2TbXW94pEmVGJAvxWk6hfxRvUJ"),
+		Amount:   1,
+		Decimals: 6,
+	})
+
+	baseMint := make([]byte, token2022.MintSize)
+	baseMint[45] = 1
+	hookMint, err := token2022.SerializeMintWithExtensions(baseMint, token2022.MintExtensions{
+		TransferHook: &token2022.TransferHook{ProgramId: hookProgramID},
+	})
+	require.Nil(t, err)
+
+	extraAccountMetas, _, err := token2022.FindExtraAccountMetasAddress(mint, hookProgramID)
+	require.Nil(t, err)
+	extraAccountMetaList := append([]byte{105, 37, 101, 197, 75, 251, 102, 26, 39, 0, 0, 0, 1, 0, 0, 0, 0}, extra.Bytes()...)
+	extraAccountMetaList = append(extraAccountMetaList, 0, 1)
+
+	t.Run("transfer hook", func(t *testing.T) {
+		server := newAccountServer(t, map[common.PublicKey][]byte{
+			mint:              hookMint,
+			extraAccountMetas: extraAccountMetaList,
+		})
+		defer server.Close()
+
+		got, err := NewClient(server.URL).AddTransferHookAccounts(context.Background(), instruction)
+		require.Nil(t, err)
+		assert.Equal(t, types.Instruction{
+			ProgramID: common.Token2022ProgramID,
+			Accounts: append(instruction.Accounts[:4:4],
+				types.AccountMeta{PubKey: extra, IsSigner: false, IsWritable: true},
+				types.AccountMeta{PubKey: hookProgramID, IsSigner: false, IsWritable: false},
+				types.AccountMeta{PubKey: extraAccountMetas, IsSigner: false, IsWritable: false},
+			),
+			Data: instruction.Data,
+		}, got)
+	})
+
+	t.Run("no transfer hook", func(t *testing.T) {
+		server := newAccountServer(t, map[common.PublicKey][]byte{mint: baseMint})
+		defer server.Close()
+
+		got, err := NewClient(server.URL).AddTransferHookAccounts(context.Background(), instruction)
+		require.Nil(t, err)
+		assert.Equal(t, instruction, got)
+	})
+
+	t.Run("no extra account metas", func(t *testing.T) {
+		server := newAccountServer(t, map[common.PublicKey][]byte{mint: hookMint})
+		defer server.Close()
+
+		_, err := NewClient(server.URL).AddTransferHookAccounts(context.Background(), instruction)
+		assert.ErrorIs(t, err, token2022.ErrExtraAccountMetasNotFound)
+	})
+}
@@ -2,9 +2,13 @@ package common
 
 import (
 	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"filippo.io/edwards25519"
 	"github.com/mr-tron/base58"
@@ -16,12 +20,30 @@ const (
 	MaxSeed         = 16
 )
 
+var ErrInvalidPublicKey = errors.New("invalid public key")
+
 type PublicKey [PublicKeyLength]byte
 
 func (p PublicKey) String() string {
 	return p.ToBase58()
 }
 
+// ParsePublicKey decodes a base58 address, it must be exactly 32 bytes
+func ParsePublicKey(s string) (PublicKey, error) {
+	b, err := base58.Decode(s)
+	if err != nil {
+		return PublicKey{}, fmt.Errorf("%w %q: %v", ErrInvalidPublicKey, s, err)
+	}
+	if len(b) != PublicKeyLength {
+		return PublicKey{}, fmt.Errorf("%w %q: expected %v bytes, got: %v", ErrInvalidPublicKey, s, PublicKeyLength, len(b))
+	}
+	var pubkey PublicKey
+	copy(pubkey[:], b)
+	return pubkey, nil
+}
+
+// PublicKeyFromString decodes a base58 address without any check, an invalid input gives a wrong key
+// instead of an error. use ParsePublicKey for anything which isn't a constant.
 func PublicKeyFromString(s string) PublicKey {
 	d, _ := base58.Decode(s)
 	return PublicKeyFromBytes(d)
@@ -86,11 +108,93 @@ func (p *PublicKey) UnmarshalJSON(data []byte) error {
 		return fmt.Errorf("a valid pubkey should be a 32-byte array. got: %v", len(b))
 	}
 
-	*p = PublicKeyFromString(s)
+	copy(p[:], b)
 
 	return nil
 }
 
+func (p PublicKey) MarshalText() ([]byte, error) {
+	return []byte(p.ToBase58()), nil
+}
+
+func (p *PublicKey) UnmarshalText(text []byte) error {
+	pubkey, err := ParsePublicKey(string(text))
+	if err != nil {
+		return err
+	}
+	*p = pubkey
+	return nil
+}
+
+// Value stores the key as its base58 string
+func (p PublicKey) Value() (driver.Value, error) {
+	return p.ToBase58(), nil
+}
+
+// Scan reads a base58 string or 32 raw bytes, NULL is the zero key
+func (p *PublicKey) Scan(src any) error {
+	switch v := src.(type) {
+	case nil:
+		*p = PublicKey{}
+		return nil
+	case string:
+		return p.UnmarshalText([]byte(v))
+	case []byte:
+		if pubkey, err := ParsePublicKey(string(v)); err == nil {
+			*p = pubkey
+			return nil
+		}
+		if len(v) == PublicKeyLength {
+			copy(p[:], v)
+			return nil
+		}
+		return fmt.Errorf("%w: expected a base58 string or %v bytes, got: %v bytes", ErrInvalidPublicKey, PublicKeyLength, len(v))
+	}
+	return fmt.Errorf("%w: unsupported type %T", ErrInvalidPublicKey, src)
+}
+
+// Format implements fmt.Formatter.
+//
+//	%s, %v  base58
+//	%.4s    the first and last 4 characters, e.g. EvN4…xtx7
+//	%q      quoted base58
+//	%x, %X  hex
+//	%#v     common.PublicKeyFromString("...")
+func (p PublicKey) Format(f fmt.State, verb rune) {
+	var s string
+	switch verb {
+	case 's', 'v':
+		s = p.ToBase58()
+		if verb == 'v' && f.Flag('#') {
+			s = "common.PublicKeyFromString(" + strconv.Quote(s) + ")"
+		} else if n, ok := f.Precision(); ok && 2*n < len(s) {
+			s = s[:n] + "…" + s[len(s)-n:]
+		}
+	case 'q':
+		s = strconv.Quote(p.ToBase58())
+	case 'x':
+		s = hex.EncodeToString(p[:])
+	case 'X':
+		s = strings.ToUpper(hex.EncodeToString(p[:]))
+	default:
+		s = "%!" + string(verb) + "(common.PublicKey=" + p.ToBase58() + ")"
+	}
+
+	if w, ok := f.Width(); ok && w > len([]rune(s)) {
+		pad := strings.Repeat(" ", w-len([]rune(s)))
+		if f.Flag('-') {
+			s += pad
+		} else {
+			s = pad + s
+		}
+	}
+	fmt.Fprint(f, s)
+}
+
+func (p PublicKey) IsZero() bool {
+	return p == PublicKey{}
+}
+
 func IsOnCurve(p PublicKey) bool {
 	_, err := new(edwards25519.Point).SetBytes(p.Bytes())
 	return err == nil
@@ -3,6 +3,7 @@ package common
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"reflect"
 	"testing"
 
@@ -268,3 +269,113 @@ func TestPublicKey_JSON(t *testing.T) {
 	err = json.Unmarshal([]byte(`{"pubkey":"EvN4kgKmCmYzdbd5kL8Q8YgkUW5RoqMTpBczrfLExtx123"}`), &a4)
 	assert.Equal(t, err, errors.New("a valid pubkey should be a 32-byte array. got: 34"))
 }
+
+func TestParsePublicKey(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  PublicKey
+		err   bool
+	}{
+		{
+			name:  "ok",
+			input: "EvN4kgKmCmYzdbd5kL8Q8YgkUW5RoqMTpBczrfLExtx7",
+			want:  PublicKeyFromString("EvN4kgKmCmYzdbd5kL8Q8YgkUW5RoqMTpBczrfLExtx7"),
+		},
+		{
+			name:  "system program",
+			input: "11111111111111111111111111111111",
+			want:  PublicKey{},
+		},
+		{name: "empty", input: "", err: true},
+		{name: "invalid digit", input: "EvN4kgKmCmYzdbd5kL8Q8YgkUW5RoqMTpBczrfLExtx0", err: true},
+		{name: "too short", input: "EvN4kgKmCmYzdbd5kL8Q8YgkUW5RoqMTpBczrfLExt", err: true},
+		{name: "too long", input: "EvN4kgKmCmYzdbd5kL8Q8YgkUW5RoqMTpBczrfLExtx123", err: true},
+		{name: "whitespace", input: " EvN4kgKmCmYzdbd5kL8Q8YgkUW5RoqMTpBczrfLExtx7", err: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePublicKey(tt.input)
+			if tt.err {
+				assert.ErrorIs(t, err, ErrInvalidPublicKey)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestPublicKey_Text(t *testing.T) {
+	pubkey := PublicKeyFromString("EvN4kgKmCmYzdbd5kL8Q8YgkUW5RoqMTpBczrfLExtx7")
+
+	// usable as a json map key
+	b, err := json.Marshal(map[PublicKey]uint64{pubkey: 1})
+	assert.Nil(t, err)
+	assert.Equal(t, `{"EvN4kgKmCmYzdbd5kL8Q8YgkUW5RoqMTpBczrfLExtx7":1}`, string(b))
+	var m map[PublicKey]uint64
+	assert.Nil(t, json.Unmarshal(b, &m))
+	assert.Equal(t, uint64(1), m[pubkey])
+
+	var p PublicKey
+	assert.ErrorIs(t, p.UnmarshalText([]byte("EvN4kgKmCmYzdbd5kL8Q8YgkUW5RoqMTpBczrfLExt")), ErrInvalidPublicKey)
+}
+
+func TestPublicKey_SQL(t *testing.T) {
+	pubkey := PublicKeyFromString("EvN4kgKmCmYzdbd5kL8Q8YgkUW5RoqMTpBczrfLExtx7")
+
+	v, err := pubkey.Value()
+	assert.Nil(t, err)
+	assert.Equal(t, "EvN4kgKmCmYzdbd5kL8Q8YgkUW5RoqMTpBczrfLExtx7", v)
+
+	for _, src := range []any{
+		"EvN4kgKmCmYzdbd5kL8Q8YgkUW5RoqMTpBczrfLExtx7",
+		[]byte("EvN4kgKmCmYzdbd5kL8Q8YgkUW5RoqMTpBczrfLExtx7"),
+		pubkey.Bytes(),
+	} {
+		var p PublicKey
+		assert.Nil(t, p.Scan(src))
+		assert.Equal(t, pubkey, p)
+	}
+
+	p := pubkey
+	assert.Nil(t, p.Scan(nil))
+	assert.True(t, p.IsZero())
+
+	assert.ErrorIs(t, p.Scan("abc"), ErrInvalidPublicKey)
+	assert.ErrorIs(t, p.Scan([]byte{1, 2, 3}), ErrInvalidPublicKey)
+	assert.ErrorIs(t, p.Scan(int64(1)), ErrInvalidPublicKey)
+}
+
+func TestPublicKey_Format(t *testing.T) {
+	pubkey := PublicKeyFromString("EvN4kgKmCmYzdbd5kL8Q8YgkUW5RoqMTpBczrfLExtx7")
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{format: "%v", want: "EvN4kgKmCmYzdbd5kL8Q8YgkUW5RoqMTpBczrfLExtx7"},
+		{format: "%s", want: "EvN4kgKmCmYzdbd5kL8Q8YgkUW5RoqMTpBczrfLExtx7"},
+		{format: "%.4s", want: "EvN4…xtx7"},
+		{format: "%.4v", want: "EvN4…xtx7"},
+		{format: "%12.4s|", want: "   EvN4…xtx7|"},
+		{format: "%-12.4s|", want: "EvN4…xtx7   |"},
+		{format: "%.30s", want: "EvN4kgKmCmYzdbd5kL8Q8YgkUW5RoqMTpBczrfLExtx7"},
+		{format: "%q", want: `"EvN4kgKmCmYzdbd5kL8Q8YgkUW5RoqMTpBczrfLExtx7"`},
+		{format: "%x", want: "ced387e6c36f57fe93ef8f516e9f318c6d89e0c51831df3d7b084e6d6e88e4f0"},
+		{format: "%#v", want: `common.PublicKeyFromString("EvN4kgKmCmYzdbd5kL8Q8YgkUW5RoqMTpBczrfLExtx7")`},
+		{format: "%d", want: "%!d(common.PublicKey=EvN4kgKmCmYzdbd5kL8Q8YgkUW5RoqMTpBczrfLExtx7)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			assert.Equal(t, tt.want, fmt.Sprintf(tt.format, pubkey))
+		})
+	}
+	// a slice is still printed key by key
+	assert.Equal(t, "[1111…1111 EvN4…xtx7]", fmt.Sprintf("%.4v", []PublicKey{{}, pubkey}))
+}
+
+func TestPublicKey_IsZero(t *testing.T) {
+	assert.True(t, PublicKey{}.IsZero())
+	assert.False(t, TokenProgramID.IsZero())
+}
@@ -2,4 +2,7 @@ package common
 
 const (
 	MaxTransactionSize = 1232
+	// MaxTransactionAccounts is the max number of accounts a transaction can reference,
+	// static and loaded, since they are indexed by a u8
+	MaxTransactionAccounts = 256
 )
@@ -32,7 +32,7 @@ func main() {
 
 	// create a tx
 	tx, err := types.NewTransaction(types.NewTransactionParam{
-		Signers: []types.Account{feePayer, alice},
+		Signers: []types.Signer{feePayer, alice},
 		Message: types.NewMessage(types.NewMessageParam{
 			FeePayer:        feePayer.PublicKey,
 			RecentBlockhash: recentBlockhashResponse.Blockhash,
@@ -39,7 +39,7 @@ func main() {
 
 	// create a tx
 	tx, err := types.NewTransaction(types.NewTransactionParam{
-		Signers: []types.Account{feePayer, nonceAccount},
+		Signers: []types.Signer{feePayer, nonceAccount},
 		Message: types.NewMessage(types.NewMessageParam{
 			FeePayer:        feePayer.PublicKey,
 			RecentBlockhash: recentBlockhashResponse.Blockhash,
@@ -27,7 +27,7 @@ func main() {
 	}
 
 	tx, err := types.NewTransaction(types.NewTransactionParam{
-		Signers: []types.Account{feePayer},
+		Signers: []types.Signer{feePayer},
 		Message: types.NewMessage(types.NewMessageParam{
 			FeePayer:        feePayer.PublicKey,
 			RecentBlockhash: recentBlockhashResponse.Blockhash,
@@ -22,7 +22,7 @@ func main() {
 		Instructions: []types.Instruction{
 			// your instruction here
 		},
-		Signers:  []types.Account{feePayer},
+		Signers:  []types.Signer{feePayer},
 		FeePayer: feePayer.PublicKey,
 	})
 	if err != nil {
@@ -47,7 +47,7 @@ func main() {
 				}),
 			},
 		}),
-		Signers: []types.Account{feePayer},
+		Signers: []types.Signer{feePayer},
 	})
 	if err != nil {
 		log.Fatalf("failed to build raw tx, err: %v", err)
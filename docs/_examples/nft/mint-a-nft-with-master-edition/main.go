@@ -54,7 +54,7 @@ func main() {
 	}
 
 	tx, err := types.NewTransaction(types.NewTransactionParam{
-		Signers: []types.Account{mint, feePayer},
+		Signers: []types.Signer{mint, feePayer},
 		Message: types.NewMessage(types.NewMessageParam{
 			FeePayer:        feePayer.PublicKey,
 			RecentBlockhash: recentBlockhashResponse.Blockhash,
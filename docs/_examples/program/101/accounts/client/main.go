@@ -41,7 +41,7 @@ func main() {
 	fmt.Printf("second account: %v\n", secondAccount.PublicKey)
 
 	tx, err := types.NewTransaction(types.NewTransactionParam{
-		Signers: []types.Account{feePayer, firstAccount},
+		Signers: []types.Signer{feePayer, firstAccount},
 		Message: types.NewMessage(types.NewMessageParam{
 			FeePayer:        feePayer.PublicKey,
 			RecentBlockhash: res.Blockhash,
@@ -31,7 +31,7 @@ func main() {
 	// (our example prgoram will parse the first byte as the selector then print remaining data.)
 	{
 		tx, err := types.NewTransaction(types.NewTransactionParam{
-			Signers: []types.Account{feePayer},
+			Signers: []types.Signer{feePayer},
 			Message: types.NewMessage(types.NewMessageParam{
 				FeePayer:        feePayer.PublicKey,
 				RecentBlockhash: res.Blockhash,
@@ -59,7 +59,7 @@ func main() {
 
 	{
 		tx, err := types.NewTransaction(types.NewTransactionParam{
-			Signers: []types.Account{feePayer},
+			Signers: []types.Signer{feePayer},
 			Message: types.NewMessage(types.NewMessageParam{
 				FeePayer:        feePayer.PublicKey,
 				RecentBlockhash: res.Blockhash,
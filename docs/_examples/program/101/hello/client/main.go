@@ -36,7 +36,7 @@ func main() {
 
 	// our first program won't use any accounts and parse any data. we leave them empty atm.
 	tx, err := types.NewTransaction(types.NewTransactionParam{
-		Signers: []types.Account{feePayer},
+		Signers: []types.Signer{feePayer},
 		Message: types.NewMessage(types.NewMessageParam{
 			FeePayer:        feePayer.PublicKey,
 			RecentBlockhash: res.Blockhash,
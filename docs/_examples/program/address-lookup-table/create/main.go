@@ -33,7 +33,7 @@ func main() {
 	log.Printf("account lookup address: %v\n", lookupTablePubkey)
 
 	tx, err := types.NewTransaction(types.NewTransactionParam{
-		Signers: []types.Account{feePayer},
+		Signers: []types.Signer{feePayer},
 		Message: types.NewMessage(types.NewMessageParam{
 			FeePayer:        feePayer.PublicKey,
 			RecentBlockhash: recentBlockhashResponse.Blockhash,
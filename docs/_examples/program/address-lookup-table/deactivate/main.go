@@ -25,7 +25,7 @@ func main() {
 	lookupTablePubkey := common.PublicKeyFromString("D6vcHD84vc3G9bieBSedSjYobACscF8NTwaLU5Y28sGj")
 
 	tx, err := types.NewTransaction(types.NewTransactionParam{
-		Signers: []types.Account{feePayer},
+		Signers: []types.Signer{feePayer},
 		Message: types.NewMessage(types.NewMessageParam{
 			FeePayer:        feePayer.PublicKey,
 			RecentBlockhash: recentBlockhashResponse.Blockhash,
@@ -49,7 +49,7 @@ func main() {
 	fmt.Println("dest ata:", destATA.ToBase58())
 
 	tx, err := types.NewTransaction(types.NewTransactionParam{
-		Signers: []types.Account{feePayer, alice},
+		Signers: []types.Signer{feePayer, alice},
 		Message: types.NewMessage(types.NewMessageParam{
 			FeePayer:        feePayer.PublicKey,
 			RecentBlockhash: recentBlockhashResponse.Blockhash,
@@ -37,7 +37,7 @@ func main() {
 				}),
 			},
 		}),
-		Signers: []types.Account{feePayer, alice},
+		Signers: []types.Signer{feePayer, alice},
 	})
 	if err != nil {
 		log.Fatalf("generate tx error, err: %v\n", err)
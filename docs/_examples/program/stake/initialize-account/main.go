@@ -64,7 +64,7 @@ func main() {
 				}),
 			},
 		}),
-		Signers: []types.Account{feePayer, stakeAccount},
+		Signers: []types.Signer{feePayer, stakeAccount},
 	})
 	if err != nil {
 		log.Fatalf("generate tx error, err: %v\n", err)
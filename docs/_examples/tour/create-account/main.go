@@ -3,10 +3,10 @@ package main
 import (
 	"fmt"
 
+	"github.com/labyla/solana-go-sdk/pkg/bip39"
 	"github.com/labyla/solana-go-sdk/pkg/hdwallet"
 	"github.com/labyla/solana-go-sdk/types"
 	"github.com/mr-tron/base58"
-	"github.com/tyler-smith/go-bip39"
 )
 
 func main() {
@@ -47,7 +47,7 @@ func main() {
 				}),
 			},
 		}),
-		Signers: []types.Account{feePayer},
+		Signers: []types.Signer{feePayer},
 	})
 	if err != nil {
 		log.Fatalf("generate tx error, err: %v\n", err)
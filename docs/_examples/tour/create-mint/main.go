@@ -60,7 +60,7 @@ func main() {
 				}),
 			},
 		}),
-		Signers: []types.Account{feePayer, mint},
+		Signers: []types.Signer{feePayer, mint},
 	})
 	if err != nil {
 		log.Fatalf("generate tx error, err: %v\n", err)
@@ -56,7 +56,7 @@ func main() {
 				}),
 			},
 		}),
-		Signers: []types.Account{feePayer, aliceRandomTokenAccount},
+		Signers: []types.Signer{feePayer, aliceRandomTokenAccount},
 	})
 	if err != nil {
 		log.Fatalf("generate tx error, err: %v\n", err)
@@ -47,7 +47,7 @@ func main() {
 				}),
 			},
 		}),
-		Signers: []types.Account{feePayer, alice},
+		Signers: []types.Signer{feePayer, alice},
 	})
 	if err != nil {
 		log.Fatalf("failed to new tx, err: %v", err)
@@ -0,0 +1,229 @@
+// Package bip39 generates and validates BIP39 mnemonics and turns them into seeds.
+// the English wordlist is embedded, other ones can be loaded with NewWordlist.
+package bip39
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+var (
+	ErrInvalidEntropySize = errors.New("entropy size must be a multiple of 32 bits between 128 and 256")
+	ErrInvalidWordlist    = errors.New("invalid wordlist")
+	ErrInvalidMnemonic    = errors.New("invalid mnemonic")
+	ErrInvalidChecksum    = errors.New("invalid mnemonic checksum")
+)
+
+const (
+	// EntropySize128 gives a 12 word mnemonic
+	EntropySize128 = 128
+	// EntropySize160 gives a 15 word mnemonic
+	EntropySize160 = 160
+	// EntropySize192 gives a 18 word mnemonic
+	EntropySize192 = 192
+	// EntropySize224 gives a 21 word mnemonic
+	EntropySize224 = 224
+	// EntropySize256 gives a 24 word mnemonic
+	EntropySize256 = 256
+)
+
+const (
+	wordlistSize = 2048
+	bitsPerWord  = 11
+	seedSize     = 64
+	seedRounds   = 2048
+)
+
+//go:embed wordlists/english.txt
+var english string
+
+// English is the wordlist of the BIP39 specification
+var English = mustNewWordlist(strings.Fields(english))
+
+// Wordlist maps 11 bits to a word and back
+type Wordlist struct {
+	words []string
+	index map[string]int
+}
+
+// NewWordlist creates a wordlist of 2048 distinct words, in the order of the specification.
+// the words are NFKD normalized, like the mnemonics they are matched with.
+func NewWordlist(words []string) (*Wordlist, error) {
+	if len(words) != wordlistSize {
+		return nil, fmt.Errorf("%w: %v words, expect %v", ErrInvalidWordlist, len(words), wordlistSize)
+	}
+	w := &Wordlist{
+		words: make([]string, 0, wordlistSize),
+		index: make(map[string]int, wordlistSize),
+	}
+	for i, word := range words {
+		word = norm.NFKD.String(word)
+		if word == "" || strings.ContainsAny(word, " \t\r\n") {
+			return nil, fmt.Errorf("%w: invalid word %q", ErrInvalidWordlist, word)
+		}
+		if _, ok := w.index[word]; ok {
+			return nil, fmt.Errorf("%w: duplicate word %q", ErrInvalidWordlist, word)
+		}
+		w.words = append(w.words, word)
+		w.index[word] = i
+	}
+	return w, nil
+}
+
+func mustNewWordlist(words []string) *Wordlist {
+	w, err := NewWordlist(words)
+	if err != nil {
+		panic(err)
+	}
+	return w
+}
+
+// Words returns a copy of the words
+func (w *Wordlist) Words() []string {
+	return append([]string{}, w.words...)
+}
+
+// NewEntropy returns random entropy of bitSize bits, one of the EntropySize constants
+func NewEntropy(bitSize int) ([]byte, error) {
+	if err := checkEntropySize(bitSize); err != nil {
+		return nil, err
+	}
+	entropy := make([]byte, bitSize/8)
+	if _, err := rand.Read(entropy); err != nil {
+		return nil, fmt.Errorf("failed to read random bytes, err: %v", err)
+	}
+	return entropy, nil
+}
+
+func checkEntropySize(bitSize int) error {
+	if bitSize%32 != 0 || bitSize < EntropySize128 || bitSize > EntropySize256 {
+		return fmt.Errorf("%w, got %v", ErrInvalidEntropySize, bitSize)
+	}
+	return nil
+}
+
+// NewMnemonic encodes the entropy as a mnemonic of the wordlist
+func (w *Wordlist) NewMnemonic(entropy []byte) (string, error) {
+	bitSize := len(entropy) * 8
+	if err := checkEntropySize(bitSize); err != nil {
+		return "", err
+	}
+
+	// the checksum is the first bitSize/32 bits of the sha256 of the entropy
+	checksum := sha256.Sum256(entropy)
+	data := append(append([]byte{}, entropy...), checksum[0])
+
+	numWords := (bitSize + bitSize/32) / bitsPerWord
+	words := make([]string, 0, numWords)
+	for i := 0; i < numWords; i++ {
+		words = append(words, w.words[readBits(data, i*bitsPerWord)])
+	}
+	return strings.Join(words, " "), nil
+}
+
+// GenerateMnemonic returns a mnemonic of new random entropy of bitSize bits
+func (w *Wordlist) GenerateMnemonic(bitSize int) (string, error) {
+	entropy, err := NewEntropy(bitSize)
+	if err != nil {
+		return "", err
+	}
+	return w.NewMnemonic(entropy)
+}
+
+// EntropyFromMnemonic decodes a mnemonic and checks its checksum
+func (w *Wordlist) EntropyFromMnemonic(mnemonic string) ([]byte, error) {
+	// NFKD also turns the ideographic space of Japanese mnemonics into a space
+	words := strings.Fields(norm.NFKD.String(mnemonic))
+	numWords := len(words)
+	if numWords%3 != 0 || numWords < 12 || numWords > 24 {
+		return nil, fmt.Errorf("%w: %v words", ErrInvalidMnemonic, numWords)
+	}
+
+	bitSize := numWords * bitsPerWord * 32 / 33
+	data := make([]byte, bitSize/8+1)
+	for i, word := range words {
+		idx, ok := w.index[word]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown word #%v %q", ErrInvalidMnemonic, i+1, word)
+		}
+		writeBits(data, i*bitsPerWord, idx)
+	}
+
+	entropy := data[:bitSize/8]
+	checksumBits := bitSize / 32
+	checksum := sha256.Sum256(entropy)
+	if checksum[0]>>(8-checksumBits) != data[bitSize/8]>>(8-checksumBits) {
+		return nil, ErrInvalidChecksum
+	}
+	return entropy, nil
+}
+
+// IsMnemonicValid reports whether the words are in the wordlist and the checksum matches
+func (w *Wordlist) IsMnemonicValid(mnemonic string) bool {
+	_, err := w.EntropyFromMnemonic(mnemonic)
+	return err == nil
+}
+
+// NewSeed validates the mnemonic and derives its seed
+func (w *Wordlist) NewSeed(mnemonic, passphrase string) ([]byte, error) {
+	if _, err := w.EntropyFromMnemonic(mnemonic); err != nil {
+		return nil, err
+	}
+	return NewSeed(mnemonic, passphrase), nil
+}
+
+// NewSeed derives the 64 byte seed of a mnemonic by PBKDF2-HMAC-SHA512 without validating
+// it. the mnemonic and the passphrase are NFKD normalized first, as the specification requires.
+func NewSeed(mnemonic, passphrase string) []byte {
+	mnemonic = strings.Join(strings.Fields(norm.NFKD.String(mnemonic)), " ")
+	salt := "mnemonic" + norm.NFKD.String(passphrase)
+	return pbkdf2.Key([]byte(mnemonic), []byte(salt), seedRounds, seedSize, sha512.New)
+}
+
+// NewMnemonic encodes the entropy as an English mnemonic
+func NewMnemonic(entropy []byte) (string, error) {
+	return English.NewMnemonic(entropy)
+}
+
+// GenerateMnemonic returns an English mnemonic of new random entropy of bitSize bits
+func GenerateMnemonic(bitSize int) (string, error) {
+	return English.GenerateMnemonic(bitSize)
+}
+
+// EntropyFromMnemonic decodes an English mnemonic and checks its checksum
+func EntropyFromMnemonic(mnemonic string) ([]byte, error) {
+	return English.EntropyFromMnemonic(mnemonic)
+}
+
+// IsMnemonicValid reports whether an English mnemonic is valid
+func IsMnemonicValid(mnemonic string) bool {
+	return English.IsMnemonicValid(mnemonic)
+}
+
+// readBits reads 11 bits big endian from the bit offset
+func readBits(data []byte, offset int) int {
+	v := 0
+	for i := 0; i < bitsPerWord; i++ {
+		bit := offset + i
+		v = v<<1 | int(data[bit/8]>>(7-bit%8)&1)
+	}
+	return v
+}
+
+// writeBits writes 11 bits big endian at the bit offset
+func writeBits(data []byte, offset int, v int) {
+	for i := 0; i < bitsPerWord; i++ {
+		bit := offset + i
+		if v>>(bitsPerWord-1-i)&1 == 1 {
+			data[bit/8] |= 1 << (7 - bit%8)
+		}
+	}
+}
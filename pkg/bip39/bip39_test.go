@@ -0,0 +1,208 @@
+package bip39
+
+import (
+	"encoding/hex"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/unicode/norm"
+)
+
+func mustDecodeHex(s string) []byte {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	return b
+}
+
+// test vectors of the specification, with the passphrase TREZOR
+var vectors = []struct {
+	entropy  string
+	mnemonic string
+	seed     string
+}{
+	{
+		entropy:  "00000000000000000000000000000000",
+		mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		seed:     "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+	},
+	{
+		entropy:  "7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+		mnemonic: "legal winner thank year wave sausage worth useful legal winner thank yellow",
+		seed:     "2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+	},
+	{
+		entropy:  "80808080808080808080808080808080",
+		mnemonic: "letter advice cage absurd amount doctor acoustic avoid letter advice cage above",
+		seed:     "d71de856f81a8acc65e6fc851a38d4d7ec216fd0796d0a6827a3ad6ed5511a30fa280f12eb2e47ed2ac03b5c462a0358d18d69fe4f985ec81778c1b370b652a8",
+	},
+	{
+		entropy:  "ffffffffffffffffffffffffffffffff",
+		mnemonic: "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+		seed:     "ac27495480225222079d7be181583751e86f571027b0497b5b5d11218e0a8a13332572917f0f8e5a589620c6f15b11c61dee327651a14c34e18231052e48c069",
+	},
+	{
+		entropy:  "0000000000000000000000000000000000000000000000000000000000000000",
+		mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+		seed:     "bda85446c68413707090a52022edd26a1c9462295029f2e60cd7c4f2bbd3097170af7a4d73245cafa9c3cca8d561a7c3de6f5d4a10be8ed2a5e608d68f92fcc8",
+	},
+}
+
+func TestVectors(t *testing.T) {
+	for _, v := range vectors {
+		t.Run(v.entropy, func(t *testing.T) {
+			mnemonic, err := NewMnemonic(mustDecodeHex(v.entropy))
+			require.Nil(t, err)
+			assert.Equal(t, v.mnemonic, mnemonic)
+
+			entropy, err := EntropyFromMnemonic(v.mnemonic)
+			require.Nil(t, err)
+			assert.Equal(t, v.entropy, hex.EncodeToString(entropy))
+
+			seed, err := English.NewSeed(v.mnemonic, "TREZOR")
+			require.Nil(t, err)
+			assert.Equal(t, v.seed, hex.EncodeToString(seed))
+		})
+	}
+}
+
+func TestVectors_Japanese(t *testing.T) {
+	// testdata/japanese.txt is the japanese wordlist of the specification
+	data, err := os.ReadFile("testdata/japanese.txt")
+	require.Nil(t, err)
+	japanese, err := NewWordlist(strings.Fields(string(data)))
+	require.Nil(t, err)
+
+	// the first vector of the japanese test vectors linked from the specification, the mnemonic
+	// is NFC with ideographic spaces and the passphrase needs NFKD too
+	const (
+		entropy    = "00000000000000000000000000000000"
+		mnemonic   = "あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あいこくしん　あおぞら"
+		passphrase = "㍍ガバヴァぱばぐゞちぢ十人十色"
+		seed       = "a262d6fb6122ecf45be09c50492b31f92e9beb7d9a845987a02cefda57a15f9c467a17872029a9e92299b5cbdf306e3a0ee620245cbd508959b6cb7ca637bd55"
+	)
+
+	got, err := japanese.NewMnemonic(mustDecodeHex(entropy))
+	require.Nil(t, err)
+	assert.Equal(t, norm.NFKD.String(mnemonic), got)
+
+	decoded, err := japanese.EntropyFromMnemonic(mnemonic)
+	require.Nil(t, err)
+	assert.Equal(t, entropy, hex.EncodeToString(decoded))
+
+	s, err := japanese.NewSeed(mnemonic, passphrase)
+	require.Nil(t, err)
+	assert.Equal(t, seed, hex.EncodeToString(s))
+
+	// the same seed whatever the normalization of the input
+	assert.Equal(t, seed, hex.EncodeToString(NewSeed(norm.NFC.String(got), norm.NFKC.String(passphrase))))
+
+	// the words are matched after normalization, whatever form the wordlist is in
+	nfc := japanese.Words()
+	for i := range nfc {
+		nfc[i] = norm.NFC.String(nfc[i])
+	}
+	composed, err := NewWordlist(nfc)
+	require.Nil(t, err)
+	assert.True(t, composed.IsMnemonicValid(norm.NFKD.String(mnemonic)))
+}
+
+func TestGenerateMnemonic(t *testing.T) {
+	for bitSize, numWords := range map[int]int{
+		EntropySize128: 12,
+		EntropySize160: 15,
+		EntropySize192: 18,
+		EntropySize224: 21,
+		EntropySize256: 24,
+	} {
+		mnemonic, err := GenerateMnemonic(bitSize)
+		require.Nil(t, err)
+		assert.Len(t, strings.Fields(mnemonic), numWords)
+		assert.True(t, IsMnemonicValid(mnemonic))
+	}
+
+	for _, bitSize := range []int{0, 96, 129, 288} {
+		_, err := GenerateMnemonic(bitSize)
+		assert.ErrorIs(t, err, ErrInvalidEntropySize)
+	}
+	_, err := NewMnemonic(make([]byte, 15))
+	assert.ErrorIs(t, err, ErrInvalidEntropySize)
+}
+
+func TestEntropyFromMnemonic_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		mnemonic string
+		err      error
+	}{
+		{
+			name:     "checksum",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+			err:      ErrInvalidChecksum,
+		},
+		{
+			name:     "unknown word",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abou",
+			err:      ErrInvalidMnemonic,
+		},
+		{
+			name:     "word count",
+			mnemonic: "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			err:      ErrInvalidMnemonic,
+		},
+		{
+			name:     "empty",
+			mnemonic: "",
+			err:      ErrInvalidMnemonic,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := EntropyFromMnemonic(tt.mnemonic)
+			assert.ErrorIs(t, err, tt.err)
+			assert.False(t, IsMnemonicValid(tt.mnemonic))
+			_, err = English.NewSeed(tt.mnemonic, "")
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	// extra spaces are fine
+	seed, err := English.NewSeed("  zoo zoo zoo zoo zoo zoo\tzoo zoo zoo zoo zoo  wrong ", "TREZOR")
+	require.Nil(t, err)
+	assert.Equal(t, vectors[3].seed, hex.EncodeToString(seed))
+}
+
+func TestNewWordlist(t *testing.T) {
+	words := English.Words()
+	assert.Len(t, words, 2048)
+	assert.Equal(t, "abandon", words[0])
+	assert.Equal(t, "zoo", words[2047])
+
+	// a custom wordlist
+	custom := make([]string, 0, len(words))
+	for _, word := range words {
+		custom = append(custom, strings.ToUpper(word))
+	}
+	w, err := NewWordlist(custom)
+	require.Nil(t, err)
+	mnemonic, err := w.NewMnemonic(mustDecodeHex(vectors[1].entropy))
+	require.Nil(t, err)
+	assert.Equal(t, strings.ToUpper(vectors[1].mnemonic), mnemonic)
+	assert.True(t, w.IsMnemonicValid(mnemonic))
+	assert.False(t, IsMnemonicValid(mnemonic))
+
+	_, err = NewWordlist(words[:2047])
+	assert.ErrorIs(t, err, ErrInvalidWordlist)
+	duplicate := English.Words()
+	duplicate[1] = duplicate[0]
+	_, err = NewWordlist(duplicate)
+	assert.ErrorIs(t, err, ErrInvalidWordlist)
+	spaced := English.Words()
+	spaced[1] = "a b"
+	_, err = NewWordlist(spaced)
+	assert.ErrorIs(t, err, ErrInvalidWordlist)
+}
@@ -2,41 +2,75 @@ package bytes_decoder
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 )
 
-func GetUint64(curr *int, data []byte) (uint64, error) {
+var (
+	ErrNilIndex         = errors.New("index is nil")
+	ErrNilData          = errors.New("data is nil")
+	ErrInsufficientData = errors.New("insufficient data length")
+)
+
+// take returns the next n bytes and moves the index, it never reads out of data
+func take(curr *int, data []byte, n int) ([]byte, error) {
 	if curr == nil {
-		return 0, fmt.Errorf("index is nil")
+		return nil, ErrNilIndex
 	}
 	if data == nil {
-		return 0, fmt.Errorf("data is nil")
+		return nil, ErrNilData
 	}
-	if len(data[*curr:]) < 8 {
-		return 0, fmt.Errorf("insufficient data length")
+	if *curr < 0 || *curr > len(data) || n < 0 || len(data)-*curr < n {
+		return nil, fmt.Errorf("%w: need %v bytes at %v, data length %v", ErrInsufficientData, n, *curr, len(data))
 	}
-
-	v := binary.LittleEndian.Uint64(data[*curr : *curr+8])
-	*curr += 8
-
-	return v, nil
+	b := data[*curr : *curr+n]
+	*curr += n
+	return b, nil
 }
 
-func GetBytes32(curr *int, data []byte) ([32]byte, error) {
-	var v [32]byte
-
-	if curr == nil {
-		return v, fmt.Errorf("index is nil")
+func GetUint8(curr *int, data []byte) (uint8, error) {
+	b, err := take(curr, data, 1)
+	if err != nil {
+		return 0, err
 	}
-	if data == nil {
-		return v, fmt.Errorf("data is nil")
+	return b[0], nil
+}
+
+func GetUint16(curr *int, data []byte) (uint16, error) {
+	b, err := take(curr, data, 2)
+	if err != nil {
+		return 0, err
 	}
-	if len(data[*curr:]) < 32 {
-		return v, fmt.Errorf("insufficient data length")
+	return binary.LittleEndian.Uint16(b), nil
+}
+
+func GetUint32(curr *int, data []byte) (uint32, error) {
+	b, err := take(curr, data, 4)
+	if err != nil {
+		return 0, err
 	}
+	return binary.LittleEndian.Uint32(b), nil
+}
 
-	copy(v[:], data[*curr:*curr+32])
-	*curr += 32
+func GetUint64(curr *int, data []byte) (uint64, error) {
+	b, err := take(curr, data, 8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(b), nil
+}
 
+func GetBytes32(curr *int, data []byte) ([32]byte, error) {
+	var v [32]byte
+	b, err := take(curr, data, 32)
+	if err != nil {
+		return v, err
+	}
+	copy(v[:], b)
 	return v, nil
 }
+
+// GetBytes returns the next n bytes, they share memory with data
+func GetBytes(curr *int, data []byte, n int) ([]byte, error) {
+	return take(curr, data, n)
+}
@@ -0,0 +1,54 @@
+package bytes_decoder
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecoder(t *testing.T) {
+	data := []byte{1, 2, 0, 3, 0, 0, 0, 4, 0, 0, 0, 0, 0, 0, 0}
+	curr := 0
+
+	u8, err := GetUint8(&curr, data)
+	assert.Nil(t, err)
+	assert.Equal(t, uint8(1), u8)
+	u16, err := GetUint16(&curr, data)
+	assert.Nil(t, err)
+	assert.Equal(t, uint16(2), u16)
+	u32, err := GetUint32(&curr, data)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(3), u32)
+	u64, err := GetUint64(&curr, data)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(4), u64)
+	assert.Equal(t, len(data), curr)
+
+	_, err = GetUint8(&curr, data)
+	assert.ErrorIs(t, err, ErrInsufficientData)
+	// the index doesn't move on error
+	assert.Equal(t, len(data), curr)
+}
+
+func TestDecoder_Error(t *testing.T) {
+	data := make([]byte, 40)
+	for _, curr := range []int{-1, 9, 41, 1 << 40} {
+		c := curr
+		_, err := GetBytes32(&c, data)
+		assert.ErrorIs(t, err, ErrInsufficientData, "curr %v", curr)
+		_, err = GetUint64(&c, data)
+		if curr == 9 {
+			assert.Nil(t, err)
+		} else {
+			assert.ErrorIs(t, err, ErrInsufficientData, "curr %v", curr)
+		}
+	}
+
+	curr := 0
+	_, err := GetUint64(nil, data)
+	assert.ErrorIs(t, err, ErrNilIndex)
+	_, err = GetUint64(&curr, nil)
+	assert.ErrorIs(t, err, ErrNilData)
+	_, err = GetBytes(&curr, data, -1)
+	assert.ErrorIs(t, err, ErrInsufficientData)
+}
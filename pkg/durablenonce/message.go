@@ -0,0 +1,99 @@
+// Package durablenonce builds transactions which use a durable nonce instead of a recent
+// blockhash, and manages a pool of nonce accounts to build them from
+package durablenonce
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/program/system"
+	"github.com/labyla/solana-go-sdk/types"
+)
+
+var (
+	ErrNonceAccountNotInitialized = errors.New("nonce account is not initialized")
+	ErrNonceAuthorityNotSigner    = errors.New("nonce authority is not a signer")
+)
+
+type MessageParam struct {
+	FeePayer common.PublicKey
+	// NonceAccountPubkey is the address of the nonce account
+	NonceAccountPubkey common.PublicKey
+	// NonceAccount is the state of the nonce account, e.g. from client.GetNonceAccount.
+	// its nonce becomes the recent blockhash and its authority signs the advance.
+	NonceAccount               system.NonceAccount
+	Instructions               []types.Instruction
+	AddressLookupTableAccounts []types.AddressLookupTableAccount
+}
+
+// NewMessage creates a message which advances the nonce account in its first instruction
+// and uses the stored nonce as the recent blockhash. an AdvanceNonceAccount of the same
+// nonce account in the instructions is dropped.
+func NewMessage(param MessageParam) (types.Message, error) {
+	if param.NonceAccount.State != system.NonceAccountStateInitialized {
+		return types.Message{}, fmt.Errorf("%w: %v", ErrNonceAccountNotInitialized, param.NonceAccountPubkey)
+	}
+	if param.NonceAccount.Nonce == (common.PublicKey{}) {
+		return types.Message{}, fmt.Errorf("%w: %v has an empty nonce", ErrNonceAccountNotInitialized, param.NonceAccountPubkey)
+	}
+
+	instructions := make([]types.Instruction, 0, len(param.Instructions)+1)
+	instructions = append(instructions, system.AdvanceNonceAccount(system.AdvanceNonceAccountParam{
+		Nonce: param.NonceAccountPubkey,
+		Auth:  param.NonceAccount.AuthorizedPubkey,
+	}))
+	for _, instruction := range param.Instructions {
+		if !isAdvanceNonceAccount(instruction, param.NonceAccountPubkey) {
+			instructions = append(instructions, instruction)
+		}
+	}
+
+	return types.NewMessage(types.NewMessageParam{
+		FeePayer:                   param.FeePayer,
+		Instructions:               instructions,
+		RecentBlockhash:            param.NonceAccount.Nonce.ToBase58(),
+		AddressLookupTableAccounts: param.AddressLookupTableAccounts,
+	}), nil
+}
+
+type TransactionParam struct {
+	MessageParam
+	// Signers must include the nonce authority
+	Signers []types.Signer
+}
+
+// NewTransaction creates a message by NewMessage and signs it. it fails early if the nonce
+// authority is not one of the signers, which is easy to miss when the fee payer is another key.
+func NewTransaction(ctx context.Context, param TransactionParam) (types.Transaction, error) {
+	authority := param.NonceAccount.AuthorizedPubkey
+	found := false
+	for _, signer := range param.Signers {
+		if signer.Public() == authority {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return types.Transaction{}, fmt.Errorf("%w: %v", ErrNonceAuthorityNotSigner, authority)
+	}
+
+	message, err := NewMessage(param.MessageParam)
+	if err != nil {
+		return types.Transaction{}, err
+	}
+	return types.NewTransactionWithContext(ctx, types.NewTransactionParam{
+		Message: message,
+		Signers: param.Signers,
+	})
+}
+
+func isAdvanceNonceAccount(instruction types.Instruction, nonceAccountPubkey common.PublicKey) bool {
+	return instruction.ProgramID == common.SystemProgramID &&
+		len(instruction.Data) == 4 &&
+		system.Instruction(binary.LittleEndian.Uint32(instruction.Data)) == system.InstructionAdvanceNonceAccount &&
+		len(instruction.Accounts) > 0 &&
+		instruction.Accounts[0].PubKey == nonceAccountPubkey
+}
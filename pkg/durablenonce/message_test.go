@@ -0,0 +1,81 @@
+package durablenonce
+
+import (
+	"context"
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/program/system"
+	"github.com/labyla/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestNonceAccount(authority common.PublicKey) system.NonceAccount {
+	return system.NonceAccount{
+		Version:          1,
+		State:            system.NonceAccountStateInitialized,
+		AuthorizedPubkey: authority,
+		Nonce:            types.NewAccount().PublicKey,
+		FeeCalculator:    system.FeeCalculator{LamportsPerSignature: 5000},
+	}
+}
+
+func TestNewMessage(t *testing.T) {
+	feePayer := types.NewAccount()
+	authority := types.NewAccount()
+	nonceAccountPubkey := types.NewAccount().PublicKey
+	nonceAccount := newTestNonceAccount(authority.PublicKey)
+	transfer := system.Transfer(system.TransferParam{
+		From:   feePayer.PublicKey,
+		To:     common.PublicKeyFromString("A4iUVr5KjmsLymUcv4eSKPedUtoaBceiPeGipKMYc69b"),
+		Amount: 1,
+	})
+	advance := system.AdvanceNonceAccount(system.AdvanceNonceAccountParam{
+		Nonce: nonceAccountPubkey,
+		Auth:  authority.PublicKey,
+	})
+
+	// an advance in the instructions is moved to the front
+	message, err := NewMessage(MessageParam{
+		FeePayer:           feePayer.PublicKey,
+		NonceAccountPubkey: nonceAccountPubkey,
+		NonceAccount:       nonceAccount,
+		Instructions:       []types.Instruction{transfer, advance},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, nonceAccount.Nonce.ToBase58(), message.RecentBlockHash)
+	assert.Equal(t, []types.Instruction{advance, transfer}, message.DecompileInstructions())
+	assert.Equal(t, uint8(2), message.Header.NumRequireSignatures)
+
+	tx, err := NewTransaction(context.Background(), TransactionParam{
+		MessageParam: MessageParam{
+			FeePayer:           feePayer.PublicKey,
+			NonceAccountPubkey: nonceAccountPubkey,
+			NonceAccount:       nonceAccount,
+			Instructions:       []types.Instruction{transfer},
+		},
+		Signers: []types.Signer{feePayer, authority},
+	})
+	require.Nil(t, err)
+	assert.Nil(t, tx.Verify())
+	assert.Equal(t, message, tx.Message)
+
+	_, err = NewTransaction(context.Background(), TransactionParam{
+		MessageParam: MessageParam{
+			FeePayer:           feePayer.PublicKey,
+			NonceAccountPubkey: nonceAccountPubkey,
+			NonceAccount:       nonceAccount,
+			Instructions:       []types.Instruction{transfer},
+		},
+		Signers: []types.Signer{feePayer},
+	})
+	assert.ErrorIs(t, err, ErrNonceAuthorityNotSigner)
+
+	_, err = NewMessage(MessageParam{
+		FeePayer:           feePayer.PublicKey,
+		NonceAccountPubkey: nonceAccountPubkey,
+		NonceAccount:       system.NonceAccount{},
+	})
+	assert.ErrorIs(t, err, ErrNonceAccountNotInitialized)
+}
@@ -0,0 +1,311 @@
+package durablenonce
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/labyla/solana-go-sdk/client"
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/program/system"
+	"github.com/labyla/solana-go-sdk/rpc"
+	"github.com/labyla/solana-go-sdk/types"
+)
+
+var (
+	ErrPoolEmpty         = errors.New("nonce pool has no account")
+	ErrNotLeased         = errors.New("nonce account is not leased")
+	ErrAuthorityMismatch = errors.New("nonce authority mismatch")
+	ErrCreateFailed      = errors.New("failed to create nonce account")
+)
+
+// Client is the part of *client.Client the pool uses
+type Client interface {
+	GetAccountInfoWithConfig(ctx context.Context, base58Addr string, cfg client.GetAccountInfoConfig) (client.AccountInfo, error)
+	GetMinimumBalanceForRentExemption(ctx context.Context, dataLen uint64) (uint64, error)
+	GetLatestBlockhash(ctx context.Context) (rpc.GetLatestBlockhashValue, error)
+	SendAndConfirmTransaction(ctx context.Context, tx types.Transaction, cfg client.SendAndConfirmTransactionConfig) (client.SendAndConfirmTransactionResult, error)
+}
+
+var _ Client = (*client.Client)(nil)
+
+// commitment is what the pool waits for and reads at. a nonce account read at a lower
+// commitment than its transaction was confirmed at may not exist yet or have the old nonce.
+const commitment = rpc.CommitmentConfirmed
+
+type PoolParam struct {
+	Client Client
+	// Payer pays the fees and the rent of the created nonce accounts
+	Payer types.Signer
+	// Authority is the nonce authority of every account in the pool
+	Authority common.PublicKey
+}
+
+// Lease is the exclusive use of a nonce account until it is released
+type Lease struct {
+	Pubkey  common.PublicKey
+	Account system.NonceAccount
+}
+
+// MessageParam returns the nonce part of a MessageParam
+func (l Lease) MessageParam(feePayer common.PublicKey, instructions []types.Instruction) MessageParam {
+	return MessageParam{
+		FeePayer:           feePayer,
+		NonceAccountPubkey: l.Pubkey,
+		NonceAccount:       l.Account,
+		Instructions:       instructions,
+	}
+}
+
+type poolEntry struct {
+	account system.NonceAccount
+	leased  bool
+	// stale means the nonce may have been advanced since it was fetched
+	stale bool
+}
+
+// Pool hands out nonce accounts for exclusive use, so two transactions never use the same
+// nonce. a nonce is only valid until it is advanced, release a lease once the transaction
+// has landed or can't land anymore, the pool fetches the new nonce then.
+type Pool struct {
+	client    Client
+	payer     types.Signer
+	authority common.PublicKey
+
+	mu      sync.Mutex
+	entries map[common.PublicKey]*poolEntry
+	free    []common.PublicKey
+	// released is closed and replaced whenever an account is put back
+	released chan struct{}
+}
+
+func NewPool(param PoolParam) *Pool {
+	return &Pool{
+		client:    param.Client,
+		payer:     param.Payer,
+		authority: param.Authority,
+		entries:   map[common.PublicKey]*poolEntry{},
+		released:  make(chan struct{}),
+	}
+}
+
+// Create creates and initializes n nonce accounts, one transaction each, and adds them to
+// the pool. the accounts created before an error are returned with it, including one which
+// was created but couldn't be added, so it can be added again later.
+func (p *Pool) Create(ctx context.Context, n int) ([]common.PublicKey, error) {
+	rent, err := p.client.GetMinimumBalanceForRentExemption(ctx, system.NonceAccountSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get rent, err: %v", err)
+	}
+
+	created := make([]common.PublicKey, 0, n)
+	for i := 0; i < n; i++ {
+		nonceAccount := types.NewAccount()
+		if err := p.create(ctx, nonceAccount, rent); err != nil {
+			return created, err
+		}
+		created = append(created, nonceAccount.PublicKey)
+		if err := p.Add(ctx, nonceAccount.PublicKey); err != nil {
+			return created, err
+		}
+	}
+	return created, nil
+}
+
+func (p *Pool) create(ctx context.Context, nonceAccount types.Account, rent uint64) error {
+	latestBlockhash, err := p.client.GetLatestBlockhash(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get latest blockhash, err: %v", err)
+	}
+	tx, err := types.NewTransactionWithContext(ctx, types.NewTransactionParam{
+		Message: types.NewMessage(types.NewMessageParam{
+			FeePayer: p.payer.Public(),
+			Instructions: []types.Instruction{
+				system.CreateAccount(system.CreateAccountParam{
+					From:     p.payer.Public(),
+					New:      nonceAccount.PublicKey,
+					Owner:    common.SystemProgramID,
+					Lamports: rent,
+					Space:    system.NonceAccountSize,
+				}),
+				system.InitializeNonceAccount(system.InitializeNonceAccountParam{
+					Nonce: nonceAccount.PublicKey,
+					Auth:  p.authority,
+				}),
+			},
+			RecentBlockhash: latestBlockhash.Blockhash,
+		}),
+		Signers: []types.Signer{p.payer, nonceAccount},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create tx, err: %v", err)
+	}
+
+	res, err := p.client.SendAndConfirmTransaction(ctx, tx, client.SendAndConfirmTransactionConfig{
+		Commitment:           commitment,
+		LastValidBlockHeight: latestBlockhash.LatestValidBlockHeight,
+	})
+	if err != nil {
+		return fmt.Errorf("%w %v, err: %v", ErrCreateFailed, nonceAccount.PublicKey, err)
+	}
+	if res.Status != client.SendAndConfirmTransactionStatusConfirmed || res.Err != nil {
+		return fmt.Errorf("%w %v, tx %v is %v, err: %v", ErrCreateFailed, nonceAccount.PublicKey, res.Signature, res.Status, res.Err)
+	}
+	return nil
+}
+
+// Add fetches existing nonce accounts and adds them to the pool, e.g. after a restart.
+// accounts already in the pool are refreshed if they are not leased.
+func (p *Pool) Add(ctx context.Context, pubkeys ...common.PublicKey) error {
+	for _, pubkey := range pubkeys {
+		account, err := p.fetch(ctx, pubkey)
+		if err != nil {
+			return err
+		}
+
+		p.mu.Lock()
+		entry, ok := p.entries[pubkey]
+		switch {
+		case !ok:
+			p.entries[pubkey] = &poolEntry{account: account}
+			p.putBack(pubkey)
+		case !entry.leased:
+			entry.account, entry.stale = account, false
+		}
+		p.mu.Unlock()
+	}
+	return nil
+}
+
+// Len returns the number of accounts in the pool and how many of them are free
+func (p *Pool) Len() (total int, free int) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return len(p.entries), len(p.free)
+}
+
+// Acquire leases a free nonce account, waiting for one if they are all leased
+func (p *Pool) Acquire(ctx context.Context) (Lease, error) {
+	for {
+		p.mu.Lock()
+		if len(p.entries) == 0 {
+			p.mu.Unlock()
+			return Lease{}, ErrPoolEmpty
+		}
+		if len(p.free) == 0 {
+			released := p.released
+			p.mu.Unlock()
+			select {
+			case <-ctx.Done():
+				return Lease{}, ctx.Err()
+			case <-released:
+				continue
+			}
+		}
+
+		pubkey := p.free[0]
+		p.free = p.free[1:]
+		entry := p.entries[pubkey]
+		entry.leased = true
+		account, stale := entry.account, entry.stale
+		p.mu.Unlock()
+
+		if stale {
+			var err error
+			account, err = p.fetch(ctx, pubkey)
+			p.mu.Lock()
+			if err != nil {
+				// it stays stale and is fetched again on its next lease
+				entry.leased = false
+				if p.entries[pubkey] == entry {
+					p.putBack(pubkey)
+				}
+				p.mu.Unlock()
+				return Lease{}, err
+			}
+			entry.account, entry.stale = account, false
+			p.mu.Unlock()
+		}
+		return Lease{Pubkey: pubkey, Account: account}, nil
+	}
+}
+
+// Release puts the account back after the transaction using it has landed or failed. the
+// nonce is fetched again, if that fails or the nonce hasn't changed yet the account is kept
+// and fetched on its next lease, so the pool recovers from a lost transaction or an rpc
+// failure by itself.
+func (p *Pool) Release(ctx context.Context, lease Lease) error {
+	p.mu.Lock()
+	entry, ok := p.entries[lease.Pubkey]
+	if !ok || !entry.leased {
+		p.mu.Unlock()
+		return fmt.Errorf("%w: %v", ErrNotLeased, lease.Pubkey)
+	}
+	entry.leased = false
+	p.mu.Unlock()
+
+	account, err := p.fetch(ctx, lease.Pubkey)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch {
+	case err != nil:
+		entry.stale = true
+	case account.Nonce == lease.Account.Nonce:
+		// the node may not see the transaction which advanced it yet
+		entry.account, entry.stale = account, true
+	default:
+		entry.account, entry.stale = account, false
+	}
+	// it may be removed in the meantime
+	if p.entries[lease.Pubkey] == entry {
+		p.putBack(lease.Pubkey)
+	}
+	return err
+}
+
+// Remove drops a nonce account from the pool, e.g. before withdrawing it. a leased one can
+// be removed as well, releasing it fails then.
+func (p *Pool) Remove(pubkey common.PublicKey) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.entries[pubkey]; !ok {
+		return
+	}
+	delete(p.entries, pubkey)
+	for i, free := range p.free {
+		if free == pubkey {
+			p.free = append(p.free[:i], p.free[i+1:]...)
+			break
+		}
+	}
+}
+
+// putBack must be called with the lock held
+func (p *Pool) putBack(pubkey common.PublicKey) {
+	p.free = append(p.free, pubkey)
+	close(p.released)
+	p.released = make(chan struct{})
+}
+
+func (p *Pool) fetch(ctx context.Context, pubkey common.PublicKey) (system.NonceAccount, error) {
+	accountInfo, err := p.client.GetAccountInfoWithConfig(ctx, pubkey.ToBase58(), client.GetAccountInfoConfig{Commitment: commitment})
+	if err != nil {
+		return system.NonceAccount{}, fmt.Errorf("failed to get nonce account %v, err: %w", pubkey, err)
+	}
+	if accountInfo.Owner != common.SystemProgramID {
+		return system.NonceAccount{}, fmt.Errorf("%w: %v is owned by %v", ErrNonceAccountNotInitialized, pubkey, accountInfo.Owner)
+	}
+	account, err := system.NonceAccountDeserialize(accountInfo.Data)
+	if err != nil {
+		return system.NonceAccount{}, fmt.Errorf("%w: %v, err: %v", ErrNonceAccountNotInitialized, pubkey, err)
+	}
+	if account.State != system.NonceAccountStateInitialized {
+		return system.NonceAccount{}, fmt.Errorf("%w: %v", ErrNonceAccountNotInitialized, pubkey)
+	}
+	if account.AuthorizedPubkey != p.authority {
+		return system.NonceAccount{}, fmt.Errorf("%w: %v is authorized by %v", ErrAuthorityMismatch, pubkey, account.AuthorizedPubkey)
+	}
+	return account, nil
+}
@@ -0,0 +1,283 @@
+package durablenonce
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labyla/solana-go-sdk/client"
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/program/system"
+	"github.com/labyla/solana-go-sdk/rpc"
+	"github.com/labyla/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var errUnavailable = errors.New("unavailable")
+
+// fakeClient keeps the nonce accounts created by its transactions. its transactions are
+// confirmed but never finalized, so the accounts only exist at confirmed commitment.
+type fakeClient struct {
+	mu       sync.Mutex
+	accounts map[string]system.NonceAccount
+	// behind are the accounts before they were advanced, returned by the next read
+	behind map[string]system.NonceAccount
+	// lag makes advance keep the old account for the next read
+	lag bool
+	// down fails GetAccountInfoWithConfig
+	down bool
+	// failSend fails the transaction on chain
+	failSend bool
+}
+
+func newFakeClient() *fakeClient {
+	return &fakeClient{
+		accounts: map[string]system.NonceAccount{},
+		behind:   map[string]system.NonceAccount{},
+	}
+}
+
+func (c *fakeClient) GetAccountInfoWithConfig(_ context.Context, base58Addr string, cfg client.GetAccountInfoConfig) (client.AccountInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.down {
+		return client.AccountInfo{}, errUnavailable
+	}
+	account, ok := c.behind[base58Addr]
+	if ok {
+		delete(c.behind, base58Addr)
+	} else {
+		account, ok = c.accounts[base58Addr]
+	}
+	if !ok || cfg.Commitment != rpc.CommitmentConfirmed {
+		return client.AccountInfo{}, nil
+	}
+	return client.AccountInfo{
+		Lamports: 1447680,
+		Owner:    common.SystemProgramID,
+		Data:     serializeNonceAccount(account),
+	}, nil
+}
+
+func serializeNonceAccount(account system.NonceAccount) []byte {
+	data := make([]byte, 0, system.NonceAccountSize)
+	data = binary.LittleEndian.AppendUint32(data, account.Version)
+	data = binary.LittleEndian.AppendUint32(data, account.State)
+	data = append(data, account.AuthorizedPubkey.Bytes()...)
+	data = append(data, account.Nonce.Bytes()...)
+	return binary.LittleEndian.AppendUint64(data, account.FeeCalculator.LamportsPerSignature)
+}
+
+func (c *fakeClient) account(pubkey common.PublicKey) system.NonceAccount {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.accounts[pubkey.ToBase58()]
+}
+
+func (c *fakeClient) GetMinimumBalanceForRentExemption(_ context.Context, dataLen uint64) (uint64, error) {
+	return 1447680, nil
+}
+
+func (c *fakeClient) GetLatestBlockhash(_ context.Context) (rpc.GetLatestBlockhashValue, error) {
+	return rpc.GetLatestBlockhashValue{
+		Blockhash:              "9rAtxuhtKn8qagc3UtZFyhLrw5zgh6vx16qhHzzaJDK4",
+		LatestValidBlockHeight: 100,
+	}, nil
+}
+
+func (c *fakeClient) SendAndConfirmTransaction(_ context.Context, tx types.Transaction, _ client.SendAndConfirmTransactionConfig) (client.SendAndConfirmTransactionResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	res := client.SendAndConfirmTransactionResult{
+		Status:    client.SendAndConfirmTransactionStatusConfirmed,
+		Signature: base58.Encode(tx.Signatures[0]),
+	}
+	if err := tx.Verify(); err != nil {
+		return res, err
+	}
+	if c.failSend {
+		res.Status = client.SendAndConfirmTransactionStatusFailed
+		res.Err = errUnavailable
+		return res, nil
+	}
+	instructions := tx.Message.DecompileInstructions()
+	if len(instructions) == 2 && instructions[1].Accounts[0].PubKey == instructions[0].Accounts[1].PubKey {
+		c.accounts[instructions[1].Accounts[0].PubKey.ToBase58()] = system.NonceAccount{
+			State:            system.NonceAccountStateInitialized,
+			AuthorizedPubkey: common.PublicKeyFromBytes(instructions[1].Data[4:]),
+			Nonce:            types.NewAccount().PublicKey,
+		}
+	}
+	return res, nil
+}
+
+// advance acts like a landed durable nonce transaction
+func (c *fakeClient) advance(pubkey common.PublicKey) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	account := c.accounts[pubkey.ToBase58()]
+	if c.lag {
+		c.behind[pubkey.ToBase58()] = account
+	}
+	account.Nonce = types.NewAccount().PublicKey
+	c.accounts[pubkey.ToBase58()] = account
+}
+
+func (c *fakeClient) setDown(down bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.down = down
+}
+
+func TestPool(t *testing.T) {
+	ctx := context.Background()
+	c := newFakeClient()
+	authority := types.NewAccount().PublicKey
+	pool := NewPool(PoolParam{Client: c, Payer: types.NewAccount(), Authority: authority})
+
+	_, err := pool.Acquire(ctx)
+	assert.ErrorIs(t, err, ErrPoolEmpty)
+
+	created, err := pool.Create(ctx, 2)
+	require.Nil(t, err)
+	require.Len(t, created, 2)
+	total, free := pool.Len()
+	assert.Equal(t, 2, total)
+	assert.Equal(t, 2, free)
+
+	// every lease is exclusive
+	first, err := pool.Acquire(ctx)
+	require.Nil(t, err)
+	second, err := pool.Acquire(ctx)
+	require.Nil(t, err)
+	assert.ElementsMatch(t, created, []common.PublicKey{first.Pubkey, second.Pubkey})
+	assert.Equal(t, authority, first.Account.AuthorizedPubkey)
+
+	timeout, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	_, err = pool.Acquire(timeout)
+	cancel()
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// a waiting acquire gets the released account with its new nonce
+	acquired := make(chan Lease)
+	go func() {
+		lease, err := pool.Acquire(ctx)
+		assert.Nil(t, err)
+		acquired <- lease
+	}()
+	oldNonce := first.Account.Nonce
+	c.advance(first.Pubkey)
+	require.Nil(t, pool.Release(ctx, first))
+	lease := <-acquired
+	assert.Equal(t, first.Pubkey, lease.Pubkey)
+	assert.NotEqual(t, oldNonce, lease.Account.Nonce)
+
+	require.Nil(t, pool.Release(ctx, lease))
+	assert.ErrorIs(t, pool.Release(ctx, lease), ErrNotLeased)
+
+	// the lease builds a durable nonce message
+	message, err := NewMessage(second.MessageParam(authority, nil))
+	require.Nil(t, err)
+	assert.Equal(t, second.Account.Nonce.ToBase58(), message.RecentBlockHash)
+}
+
+func TestPool_Recover(t *testing.T) {
+	ctx := context.Background()
+	c := newFakeClient()
+	authority := types.NewAccount().PublicKey
+	pool := NewPool(PoolParam{Client: c, Payer: types.NewAccount(), Authority: authority})
+	created, err := pool.Create(ctx, 1)
+	require.Nil(t, err)
+
+	lease, err := pool.Acquire(ctx)
+	require.Nil(t, err)
+
+	// the tx landed but the rpc is down, the account is put back as stale
+	c.advance(lease.Pubkey)
+	c.setDown(true)
+	assert.ErrorIs(t, pool.Release(ctx, lease), errUnavailable)
+	_, free := pool.Len()
+	assert.Equal(t, 1, free)
+
+	// the next lease fetches the nonce again
+	_, err = pool.Acquire(ctx)
+	assert.ErrorIs(t, err, errUnavailable)
+	c.setDown(false)
+	recovered, err := pool.Acquire(ctx)
+	require.Nil(t, err)
+	account := c.account(created[0])
+	assert.Equal(t, account, recovered.Account)
+
+	// a restarted pool picks the accounts up again
+	restarted := NewPool(PoolParam{Client: c, Authority: authority})
+	require.Nil(t, restarted.Add(ctx, created...))
+	lease, err = restarted.Acquire(ctx)
+	require.Nil(t, err)
+	assert.Equal(t, account, lease.Account)
+
+	// accounts of another authority are refused
+	other := NewPool(PoolParam{Client: c, Authority: types.NewAccount().PublicKey})
+	assert.ErrorIs(t, other.Add(ctx, created...), ErrAuthorityMismatch)
+
+	// a removed account is not handed out anymore
+	pool.Remove(recovered.Pubkey)
+	assert.ErrorIs(t, pool.Release(ctx, recovered), ErrNotLeased)
+	_, err = pool.Acquire(ctx)
+	assert.ErrorIs(t, err, ErrPoolEmpty)
+}
+
+func TestPool_CreateFailed(t *testing.T) {
+	c := newFakeClient()
+	c.failSend = true
+	pool := NewPool(PoolParam{Client: c, Payer: types.NewAccount(), Authority: types.NewAccount().PublicKey})
+	created, err := pool.Create(context.Background(), 1)
+	assert.ErrorIs(t, err, ErrCreateFailed)
+	assert.Empty(t, created)
+}
+
+func TestPool_CreateAddFailed(t *testing.T) {
+	c := newFakeClient()
+	authority := types.NewAccount().PublicKey
+	pool := NewPool(PoolParam{Client: c, Payer: types.NewAccount(), Authority: authority})
+	ctx := context.Background()
+
+	// the account is created on chain but can't be fetched
+	c.setDown(true)
+	created, err := pool.Create(ctx, 2)
+	assert.ErrorIs(t, err, errUnavailable)
+	require.Len(t, created, 1)
+	assert.Len(t, c.accounts, 1)
+
+	c.setDown(false)
+	require.Nil(t, pool.Add(ctx, created...))
+	lease, err := pool.Acquire(ctx)
+	require.Nil(t, err)
+	assert.Equal(t, created[0], lease.Pubkey)
+}
+
+func TestPool_ReleaseBeforeNodeSeesAdvance(t *testing.T) {
+	ctx := context.Background()
+	c := newFakeClient()
+	pool := NewPool(PoolParam{Client: c, Payer: types.NewAccount(), Authority: types.NewAccount().PublicKey})
+	_, err := pool.Create(ctx, 1)
+	require.Nil(t, err)
+
+	lease, err := pool.Acquire(ctx)
+	require.Nil(t, err)
+
+	// the first read after the release still returns the used nonce
+	c.lag = true
+	c.advance(lease.Pubkey)
+	require.Nil(t, pool.Release(ctx, lease))
+
+	// so the next lease fetches it again
+	next, err := pool.Acquire(ctx)
+	require.Nil(t, err)
+	assert.NotEqual(t, lease.Account.Nonce, next.Account.Nonce)
+	assert.Equal(t, c.account(lease.Pubkey), next.Account)
+}
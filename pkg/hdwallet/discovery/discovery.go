@@ -0,0 +1,168 @@
+// Package discovery finds the accounts of a seed which were used on chain, by walking the
+// derivation paths wallets use until a gap of unused accounts
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/labyla/solana-go-sdk/client"
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/pkg/hdwallet"
+	"github.com/labyla/solana-go-sdk/rpc"
+	"github.com/labyla/solana-go-sdk/types"
+)
+
+// Scheme is a derivation scheme. Template is a path with a %d for the account index, a
+// template without one has a single account.
+type Scheme struct {
+	Name     string
+	Template string
+}
+
+var (
+	// SchemeRoot is the bare root, used by early Solflare versions
+	SchemeRoot = Scheme{Name: "root", Template: "m/44'/501'"}
+	// SchemeAccount is used by Ledger and Solflare
+	SchemeAccount = Scheme{Name: "account", Template: "m/44'/501'/%d'"}
+	// SchemeAccountChange is used by Phantom, Backpack and solana-keygen with a derivation path
+	SchemeAccountChange = Scheme{Name: "account-change", Template: "m/44'/501'/%d'/0'"}
+)
+
+// DefaultSchemes are the standard Solana schemes
+var DefaultSchemes = []Scheme{SchemeRoot, SchemeAccount, SchemeAccountChange}
+
+func (s Scheme) indexed() bool {
+	return strings.Contains(s.Template, "%d")
+}
+
+// Path returns the path of the i-th account
+func (s Scheme) Path(i uint32) string {
+	if !s.indexed() {
+		return s.Template
+	}
+	return fmt.Sprintf(s.Template, i)
+}
+
+// Client is the part of *client.Client discovery uses
+type Client interface {
+	GetBalance(ctx context.Context, base58Addr string) (uint64, error)
+	GetSignaturesForAddressWithConfig(ctx context.Context, addr string, cfg client.GetSignaturesForAddressConfig) (rpc.GetSignaturesForAddress, error)
+	GetTokenAccountsByOwnerByProgram(ctx context.Context, owner, programId string) ([]client.TokenAccount, error)
+}
+
+var _ Client = (*client.Client)(nil)
+
+type Config struct {
+	// Schemes to walk. default: DefaultSchemes
+	Schemes []Scheme
+	// GapLimit is how many unused accounts in a row end a scheme. default: 20
+	GapLimit int
+	// MaxIndex caps the index of every scheme. default: no cap
+	MaxIndex uint32
+}
+
+// Account is a used account
+type Account struct {
+	Scheme  Scheme
+	Index   uint32
+	Path    string
+	Account types.Account
+	// Balance is the balance in lamports
+	Balance uint64
+	// HasHistory is set if the address has a transaction signature
+	HasHistory bool
+	// HasTokenAccounts is set if it owns a token or token2022 account
+	HasTokenAccounts bool
+}
+
+func (a Account) used() bool {
+	return a.Balance > 0 || a.HasHistory || a.HasTokenAccounts
+}
+
+// Discover walks the schemes of cfg and returns the used accounts, scheme by scheme in the
+// order of their index. a seed is from bip39, e.g. bip39.NewSeed.
+func Discover(ctx context.Context, c Client, seed []byte, cfg Config) ([]Account, error) {
+	if cfg.Schemes == nil {
+		cfg.Schemes = DefaultSchemes
+	}
+	if cfg.GapLimit <= 0 {
+		cfg.GapLimit = 20
+	}
+
+	used := []Account{}
+	for _, scheme := range cfg.Schemes {
+		gap := 0
+		for i := uint32(0); ; i++ {
+			if cfg.MaxIndex > 0 && i > cfg.MaxIndex {
+				break
+			}
+			account, err := check(ctx, c, seed, scheme, i)
+			if err != nil {
+				return nil, err
+			}
+			if account.used() {
+				used = append(used, account)
+				gap = 0
+			} else {
+				gap++
+			}
+			if !scheme.indexed() || gap >= cfg.GapLimit {
+				break
+			}
+		}
+	}
+	return used, nil
+}
+
+// check looks for the signs of use from the cheapest to the most expensive and stops at the
+// first one found
+func check(ctx context.Context, c Client, seed []byte, scheme Scheme, index uint32) (Account, error) {
+	path := scheme.Path(index)
+	key, err := hdwallet.Derived(path, seed)
+	if err != nil {
+		return Account{}, fmt.Errorf("failed to derive %v, err: %v", path, err)
+	}
+	account, err := types.AccountFromSeed(key.PrivateKey)
+	if err != nil {
+		return Account{}, fmt.Errorf("failed to create account of %v, err: %v", path, err)
+	}
+
+	a := Account{
+		Scheme:  scheme,
+		Index:   index,
+		Path:    path,
+		Account: account,
+	}
+	address := account.PublicKey.ToBase58()
+
+	a.Balance, err = c.GetBalance(ctx, address)
+	if err != nil {
+		return Account{}, fmt.Errorf("failed to get balance of %v, err: %w", path, err)
+	}
+	if a.used() {
+		return a, nil
+	}
+
+	signatures, err := c.GetSignaturesForAddressWithConfig(ctx, address, client.GetSignaturesForAddressConfig{Limit: 1})
+	if err != nil {
+		return Account{}, fmt.Errorf("failed to get signatures of %v, err: %w", path, err)
+	}
+	a.HasHistory = len(signatures) > 0
+	if a.used() {
+		return a, nil
+	}
+
+	for _, programID := range []common.PublicKey{common.TokenProgramID, common.Token2022ProgramID} {
+		tokenAccounts, err := c.GetTokenAccountsByOwnerByProgram(ctx, address, programID.ToBase58())
+		if err != nil {
+			return Account{}, fmt.Errorf("failed to get token accounts of %v, err: %w", path, err)
+		}
+		if len(tokenAccounts) > 0 {
+			a.HasTokenAccounts = true
+			break
+		}
+	}
+	return a, nil
+}
@@ -0,0 +1,125 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/client"
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/pkg/bip39"
+	"github.com/labyla/solana-go-sdk/pkg/hdwallet"
+	"github.com/labyla/solana-go-sdk/rpc"
+	"github.com/labyla/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClient knows which addresses are used and records which ones were asked
+type fakeClient struct {
+	balances     map[string]uint64
+	history      map[string]bool
+	tokenOwners  map[string]common.PublicKey
+	checked      map[string]bool
+	balanceError error
+}
+
+func (c *fakeClient) GetBalance(_ context.Context, base58Addr string) (uint64, error) {
+	c.checked[base58Addr] = true
+	return c.balances[base58Addr], c.balanceError
+}
+
+func (c *fakeClient) GetSignaturesForAddressWithConfig(_ context.Context, addr string, cfg client.GetSignaturesForAddressConfig) (rpc.GetSignaturesForAddress, error) {
+	if cfg.Limit != 1 {
+		return nil, errors.New("only the latest signature is needed")
+	}
+	if c.history[addr] {
+		return rpc.GetSignaturesForAddress{{Signature: "sig"}}, nil
+	}
+	return rpc.GetSignaturesForAddress{}, nil
+}
+
+func (c *fakeClient) GetTokenAccountsByOwnerByProgram(_ context.Context, owner, programId string) ([]client.TokenAccount, error) {
+	if program, ok := c.tokenOwners[owner]; ok && program.ToBase58() == programId {
+		return []client.TokenAccount{{}}, nil
+	}
+	return nil, nil
+}
+
+func mustAddress(t *testing.T, seed []byte, path string) string {
+	key, err := hdwallet.Derived(path, seed)
+	require.Nil(t, err)
+	account, err := types.AccountFromSeed(key.PrivateKey)
+	require.Nil(t, err)
+	return account.PublicKey.ToBase58()
+}
+
+func TestDiscover(t *testing.T) {
+	seed := bip39.NewSeed("neither lonely flavor argue grass remind eye tag avocado spot unusual intact", "")
+	c := &fakeClient{
+		balances: map[string]uint64{
+			// the first phantom account
+			"5vftMkHL72JaJG6ExQfGAsT2uGVHpRR7oTNUPMs68Y2N": 1000,
+			mustAddress(t, seed, "m/44'/501'"):             1,
+		},
+		history: map[string]bool{
+			mustAddress(t, seed, "m/44'/501'/2'/0'"): true,
+		},
+		tokenOwners: map[string]common.PublicKey{
+			mustAddress(t, seed, "m/44'/501'/4'/0'"): common.Token2022ProgramID,
+			// beyond the gap
+			mustAddress(t, seed, "m/44'/501'/7'/0'"): common.TokenProgramID,
+		},
+		checked: map[string]bool{},
+	}
+
+	accounts, err := Discover(context.Background(), c, seed, Config{GapLimit: 2})
+	require.Nil(t, err)
+
+	var paths []string
+	for _, account := range accounts {
+		paths = append(paths, account.Path)
+		assert.Equal(t, account.Scheme.Path(account.Index), account.Path)
+	}
+	assert.Equal(t, []string{
+		"m/44'/501'",
+		"m/44'/501'/0'/0'",
+		"m/44'/501'/2'/0'",
+		"m/44'/501'/4'/0'",
+	}, paths)
+
+	assert.Equal(t, SchemeAccountChange, accounts[1].Scheme)
+	assert.Equal(t, uint32(0), accounts[1].Index)
+	assert.Equal(t, "5vftMkHL72JaJG6ExQfGAsT2uGVHpRR7oTNUPMs68Y2N", accounts[1].Account.PublicKey.ToBase58())
+	assert.Equal(t, uint64(1000), accounts[1].Balance)
+	assert.True(t, accounts[2].HasHistory)
+	assert.True(t, accounts[3].HasTokenAccounts)
+	assert.False(t, accounts[3].HasHistory)
+
+	// the account scheme stops after the gap, the other one after the last used account
+	assert.True(t, c.checked[mustAddress(t, seed, "m/44'/501'/1'")])
+	assert.False(t, c.checked[mustAddress(t, seed, "m/44'/501'/2'")])
+	assert.True(t, c.checked[mustAddress(t, seed, "m/44'/501'/6'/0'")])
+	assert.False(t, c.checked[mustAddress(t, seed, "m/44'/501'/7'/0'")])
+
+	// a cap on the index
+	accounts, err = Discover(context.Background(), c, seed, Config{
+		Schemes:  []Scheme{SchemeAccountChange},
+		GapLimit: 10,
+		MaxIndex: 3,
+	})
+	require.Nil(t, err)
+	assert.Len(t, accounts, 2)
+}
+
+func TestDiscover_Error(t *testing.T) {
+	errUnavailable := errors.New("unavailable")
+	c := &fakeClient{checked: map[string]bool{}, balanceError: errUnavailable}
+	_, err := Discover(context.Background(), c, bip39.NewSeed("", ""), Config{})
+	assert.ErrorIs(t, err, errUnavailable)
+
+	_, err = Discover(context.Background(), &fakeClient{checked: map[string]bool{}}, nil, Config{
+		Schemes: []Scheme{{Name: "invalid", Template: "m/44'/501/%d"}},
+	})
+	assert.Error(t, err)
+}
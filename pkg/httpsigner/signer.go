@@ -0,0 +1,127 @@
+// Package httpsigner is a types.Signer which asks a remote service to sign over http.
+//
+// the signer posts
+//
+//	{"publicKey": "<base58 public key>", "message": "<base64 message>"}
+//
+// and expects a 2xx response with
+//
+//	{"signature": "<base58 signature>"}
+package httpsigner
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/mr-tron/base58"
+)
+
+var ErrUnexpectedStatus = errors.New("unexpected http status")
+
+// HttpClient is the interface of *http.Client
+type HttpClient interface {
+	Do(*http.Request) (*http.Response, error)
+}
+
+type Signer struct {
+	endpoint   string
+	publicKey  common.PublicKey
+	httpClient HttpClient
+	header     http.Header
+}
+
+// Option is a configuration type for the Signer
+type Option func(*Signer)
+
+// WithHttpClient sets the http client. default: http.DefaultClient
+func WithHttpClient(client HttpClient) Option {
+	return func(s *Signer) {
+		s.httpClient = client
+	}
+}
+
+// WithHeader adds a header to every request, e.g. an authorization token
+func WithHeader(key, value string) Option {
+	return func(s *Signer) {
+		s.header.Add(key, value)
+	}
+}
+
+// New creates a signer of publicKey whose signatures are made by endpoint
+func New(endpoint string, publicKey common.PublicKey, opts ...Option) *Signer {
+	s := &Signer{
+		endpoint:   endpoint,
+		publicKey:  publicKey,
+		httpClient: http.DefaultClient,
+		header:     http.Header{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+type signRequest struct {
+	PublicKey string `json:"publicKey"`
+	Message   string `json:"message"`
+}
+
+type signResponse struct {
+	Signature string `json:"signature"`
+}
+
+func (s *Signer) Public() common.PublicKey {
+	return s.publicKey
+}
+
+// SignMessage sends the message to the endpoint. the signature is not verified here,
+// types.NewTransaction does it.
+func (s *Signer) SignMessage(ctx context.Context, message []byte) ([]byte, error) {
+	body, err := json.Marshal(signRequest{
+		PublicKey: s.publicKey.ToBase58(),
+		Message:   base64.StdEncoding.EncodeToString(message),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request, err: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request, err: %v", err)
+	}
+	for k, v := range s.header {
+		req.Header[k] = v
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to do request, err: %w", err)
+	}
+	defer res.Body.Close()
+
+	resBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body, err: %v", err)
+	}
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("%w: %v, body: %s", ErrUnexpectedStatus, res.StatusCode, resBody)
+	}
+
+	var output signResponse
+	if err := json.Unmarshal(resBody, &output); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response, err: %v", err)
+	}
+	sig, err := base58.Decode(output.Signature)
+	if err != nil {
+		return nil, fmt.Errorf("failed to base58 decode signature, err: %v", err)
+	}
+	return sig, nil
+}
@@ -0,0 +1,129 @@
+package httpsigner
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/program/system"
+	"github.com/labyla/solana-go-sdk/types"
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStandInServer signs with the accounts it holds, like a kms would
+func newStandInServer(t *testing.T, accounts ...types.Account) *httptest.Server {
+	keys := map[string]types.Account{}
+	for _, a := range accounts {
+		keys[a.PublicKey.ToBase58()] = a
+	}
+	return httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.Header.Get("Authorization") != "Bearer token" {
+			rw.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var r signRequest
+		assert.Nil(t, json.NewDecoder(req.Body).Decode(&r))
+		account, ok := keys[r.PublicKey]
+		if !ok {
+			rw.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(rw, "unknown key")
+			return
+		}
+		message, err := base64.StdEncoding.DecodeString(r.Message)
+		assert.Nil(t, err)
+		fmt.Fprintf(rw, `{"signature":"%v"}`, base58.Encode(account.Sign(message)))
+	}))
+}
+
+func newTestMessage(feePayer common.PublicKey, to common.PublicKey) types.Message {
+	return types.NewMessage(types.NewMessageParam{
+		FeePayer: feePayer,
+		Instructions: []types.Instruction{
+			system.Transfer(system.TransferParam{
+				From:   feePayer,
+				To:     to,
+				Amount: 1,
+			}),
+		},
+		RecentBlockhash: "9rAtxuhtKn8qagc3UtZFyhLrw5zgh6vx16qhHzzaJDK4",
+	})
+}
+
+func TestSigner(t *testing.T) {
+	account := types.NewAccount()
+	server := newStandInServer(t, account)
+	defer server.Close()
+
+	signer := New(server.URL, account.PublicKey, WithHeader("Authorization", "Bearer token"))
+	assert.Equal(t, account.PublicKey, signer.Public())
+
+	message := newTestMessage(account.PublicKey, types.NewAccount().PublicKey)
+	tx, err := types.NewTransaction(types.NewTransactionParam{
+		Message: message,
+		Signers: []types.Signer{signer},
+	})
+	require.Nil(t, err)
+
+	// same as signing locally
+	expected, err := types.NewTransaction(types.NewTransactionParam{
+		Message: message,
+		Signers: []types.Signer{account},
+	})
+	require.Nil(t, err)
+	assert.Equal(t, expected, tx)
+}
+
+func TestSigner_Error(t *testing.T) {
+	account := types.NewAccount()
+	other := types.NewAccount()
+	server := newStandInServer(t, account)
+	defer server.Close()
+
+	// unauthorized
+	_, err := New(server.URL, account.PublicKey).SignMessage(context.Background(), []byte("hi"))
+	assert.ErrorIs(t, err, ErrUnexpectedStatus)
+
+	// the server signs with another key, the signature doesn't verify
+	impostor := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var r signRequest
+		assert.Nil(t, json.NewDecoder(req.Body).Decode(&r))
+		message, err := base64.StdEncoding.DecodeString(r.Message)
+		assert.Nil(t, err)
+		fmt.Fprintf(rw, `{"signature":"%v"}`, base58.Encode(other.Sign(message)))
+	}))
+	defer impostor.Close()
+	_, err = types.NewTransaction(types.NewTransactionParam{
+		Message: newTestMessage(account.PublicKey, other.PublicKey),
+		Signers: []types.Signer{New(impostor.URL, account.PublicKey)},
+	})
+	assert.ErrorIs(t, err, types.ErrSignerInvalidSignature)
+
+	// not a 2xx
+	_, err = New(server.URL, other.PublicKey, WithHeader("Authorization", "Bearer token")).SignMessage(context.Background(), []byte("hi"))
+	assert.ErrorIs(t, err, ErrUnexpectedStatus)
+	assert.Contains(t, err.Error(), "unknown key")
+
+	// timeout
+	release := make(chan struct{})
+	slow := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		<-release
+	}))
+	defer slow.Close()
+	defer close(release)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = types.NewTransactionWithContext(ctx, types.NewTransactionParam{
+		Message: newTestMessage(account.PublicKey, other.PublicKey),
+		Signers: []types.Signer{New(slow.URL, account.PublicKey)},
+	})
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+}
@@ -0,0 +1,346 @@
+// Package keystore keeps private keys in a password encrypted json file. every key is sealed
+// on its own by AES-256-GCM, with a key derived from the password by scrypt or argon2id and a
+// random salt, so keys can be added, labeled and removed without the other passwords.
+package keystore
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/types"
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/scrypt"
+)
+
+var (
+	ErrKeyNotFound        = errors.New("key not found")
+	ErrDuplicateKey       = errors.New("key already exists")
+	ErrWrongPassword      = errors.New("wrong password or corrupted key")
+	ErrUnsupportedVersion = errors.New("unsupported keystore version")
+	ErrUnsupportedKDF     = errors.New("unsupported kdf")
+	ErrUnsupportedCipher  = errors.New("unsupported cipher")
+)
+
+const (
+	Version = 1
+
+	KDFScrypt   = "scrypt"
+	KDFArgon2id = "argon2id"
+
+	CipherAES256GCM = "aes-256-gcm"
+
+	keySize  = 32
+	saltSize = 16
+
+	// scrypt needs 128*n*r bytes and argon2id memory KiB, both are capped at 256 MiB
+	maxScryptN      = 1 << 18
+	maxScryptR      = 8
+	maxScryptP      = 16
+	maxArgon2Time   = 16
+	maxArgon2Memory = 256 * 1024
+)
+
+// KDF holds the parameters of the password key derivation
+type KDF struct {
+	Name string `json:"name"`
+	Salt []byte `json:"salt"`
+	// scrypt
+	N int `json:"n,omitempty"`
+	R int `json:"r,omitempty"`
+	P int `json:"p,omitempty"`
+	// argon2id
+	Time    uint32 `json:"time,omitempty"`
+	Memory  uint32 `json:"memory,omitempty"`
+	Threads uint8  `json:"threads,omitempty"`
+}
+
+// validate checks the parameters before deriving, a crafted file must not make us allocate gigabytes
+func (k KDF) validate() error {
+	switch k.Name {
+	case KDFScrypt:
+		if k.N > maxScryptN || k.R <= 0 || k.R > maxScryptR || k.P <= 0 || k.P > maxScryptP {
+			return fmt.Errorf("%w: scrypt parameters out of range", ErrUnsupportedKDF)
+		}
+		return nil
+	case KDFArgon2id:
+		if k.Time == 0 || k.Time > maxArgon2Time || k.Memory == 0 || k.Memory > maxArgon2Memory || k.Threads == 0 {
+			return fmt.Errorf("%w: invalid argon2id parameters", ErrUnsupportedKDF)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w: %v", ErrUnsupportedKDF, k.Name)
+}
+
+func (k KDF) deriveKey(password string) ([]byte, error) {
+	if err := k.validate(); err != nil {
+		return nil, err
+	}
+	if k.Name == KDFArgon2id {
+		return argon2.IDKey([]byte(password), k.Salt, k.Time, k.Memory, k.Threads, keySize), nil
+	}
+	key, err := scrypt.Key([]byte(password), k.Salt, k.N, k.R, k.P, keySize)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedKDF, err)
+	}
+	return key, nil
+}
+
+// Entry is a sealed private key
+type Entry struct {
+	Label     string           `json:"label"`
+	PublicKey common.PublicKey `json:"publicKey"`
+	KDF       KDF              `json:"kdf"`
+	Cipher    string           `json:"cipher"`
+	Nonce     []byte           `json:"nonce"`
+	// Ciphertext is the sealed 32 bytes ed25519 seed, the public key is the additional data
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// KeyInfo describes a key without its secret
+type KeyInfo struct {
+	Label     string
+	PublicKey common.PublicKey
+}
+
+type Keystore struct {
+	Version int      `json:"version"`
+	Keys    []*Entry `json:"keys"`
+}
+
+type options struct {
+	kdf KDF
+}
+
+type Option func(*options)
+
+// WithScrypt sets the scrypt cost, n must be a power of 2. default: n=2^15, r=8, p=1
+func WithScrypt(n, r, p int) Option {
+	return func(o *options) {
+		o.kdf = KDF{Name: KDFScrypt, N: n, R: r, P: p}
+	}
+}
+
+// WithArgon2id uses argon2id instead of scrypt, memory is in KiB, e.g. time=3, memory=64*1024, threads=4
+func WithArgon2id(time, memory uint32, threads uint8) Option {
+	return func(o *options) {
+		o.kdf = KDF{Name: KDFArgon2id, Time: time, Memory: memory, Threads: threads}
+	}
+}
+
+func New() *Keystore {
+	return &Keystore{Version: Version, Keys: []*Entry{}}
+}
+
+// Decode parses a keystore, the keys stay sealed
+func Decode(data []byte) (*Keystore, error) {
+	k := &Keystore{}
+	if err := json.Unmarshal(data, k); err != nil {
+		return nil, fmt.Errorf("failed to parse keystore, err: %v", err)
+	}
+	if k.Version != Version {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedVersion, k.Version)
+	}
+	if k.Keys == nil {
+		k.Keys = []*Entry{}
+	}
+	return k, nil
+}
+
+// Load reads a keystore file
+func Load(path string) (*Keystore, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Decode(data)
+}
+
+// Save writes the keystore file readable by the owner only. the file is replaced at once,
+// a crash never leaves a partial keystore.
+func (k *Keystore) Save(path string) error {
+	data, err := json.MarshalIndent(k, "", "  ")
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if err := f.Chmod(0600); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Add seals the private key of the account with the password
+func (k *Keystore) Add(account types.Account, label, password string, opts ...Option) error {
+	if len(account.PrivateKey) != ed25519.PrivateKeySize {
+		return fmt.Errorf("%w, expected: %v, got: %v", types.ErrAccountPrivateKeyLengthMismatch, ed25519.PrivateKeySize, len(account.PrivateKey))
+	}
+	if _, err := k.find(account.PublicKey); err == nil {
+		return fmt.Errorf("%w: %v", ErrDuplicateKey, account.PublicKey)
+	}
+
+	o := options{}
+	WithScrypt(1<<15, 8, 1)(&o)
+	for _, opt := range opts {
+		opt(&o)
+	}
+	o.kdf.Salt = make([]byte, saltSize)
+	if _, err := rand.Read(o.kdf.Salt); err != nil {
+		return err
+	}
+
+	key, err := o.kdf.deriveKey(password)
+	if err != nil {
+		return err
+	}
+	defer zeroize(key)
+	aead, err := newAEAD(CipherAES256GCM, key)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+
+	k.Keys = append(k.Keys, &Entry{
+		Label:      label,
+		PublicKey:  account.PublicKey,
+		KDF:        o.kdf,
+		Cipher:     CipherAES256GCM,
+		Nonce:      nonce,
+		Ciphertext: aead.Seal(nil, nonce, account.PrivateKey.Seed(), account.PublicKey.Bytes()),
+	})
+	return nil
+}
+
+// Account unseals the key of the public key. call Zeroize on the account once done with it.
+func (k *Keystore) Account(publicKey common.PublicKey, password string) (types.Account, error) {
+	entry, err := k.find(publicKey)
+	if err != nil {
+		return types.Account{}, err
+	}
+	return entry.open(password)
+}
+
+// AccountByLabel unseals the first key with the label
+func (k *Keystore) AccountByLabel(label, password string) (types.Account, error) {
+	for _, entry := range k.Keys {
+		if entry.Label == label {
+			return entry.open(password)
+		}
+	}
+	return types.Account{}, fmt.Errorf("%w: label %q", ErrKeyNotFound, label)
+}
+
+// List returns the keys in the order they were added
+func (k *Keystore) List() []KeyInfo {
+	infos := make([]KeyInfo, 0, len(k.Keys))
+	for _, entry := range k.Keys {
+		infos = append(infos, KeyInfo{Label: entry.Label, PublicKey: entry.PublicKey})
+	}
+	return infos
+}
+
+// SetLabel changes the label of a key, no password is needed
+func (k *Keystore) SetLabel(publicKey common.PublicKey, label string) error {
+	entry, err := k.find(publicKey)
+	if err != nil {
+		return err
+	}
+	entry.Label = label
+	return nil
+}
+
+// Remove deletes a key
+func (k *Keystore) Remove(publicKey common.PublicKey) error {
+	for i, entry := range k.Keys {
+		if entry.PublicKey == publicKey {
+			k.Keys = append(k.Keys[:i], k.Keys[i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %v", ErrKeyNotFound, publicKey)
+}
+
+func (k *Keystore) find(publicKey common.PublicKey) (*Entry, error) {
+	for _, entry := range k.Keys {
+		if entry.PublicKey == publicKey {
+			return entry, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %v", ErrKeyNotFound, publicKey)
+}
+
+func (e *Entry) open(password string) (types.Account, error) {
+	key, err := e.KDF.deriveKey(password)
+	if err != nil {
+		return types.Account{}, err
+	}
+	defer zeroize(key)
+	aead, err := newAEAD(e.Cipher, key)
+	if err != nil {
+		return types.Account{}, err
+	}
+	if len(e.Nonce) != aead.NonceSize() {
+		return types.Account{}, ErrWrongPassword
+	}
+	seed, err := aead.Open(nil, e.Nonce, e.Ciphertext, e.PublicKey.Bytes())
+	if err != nil {
+		return types.Account{}, ErrWrongPassword
+	}
+	defer zeroize(seed)
+	if len(seed) != ed25519.SeedSize {
+		return types.Account{}, ErrWrongPassword
+	}
+
+	account, err := types.AccountFromSeed(seed)
+	if err != nil {
+		return types.Account{}, err
+	}
+	if account.PublicKey != e.PublicKey {
+		account.Zeroize()
+		return types.Account{}, fmt.Errorf("%w: %v", types.ErrAccountPublicKeyMismatch, e.PublicKey)
+	}
+	return account, nil
+}
+
+func newAEAD(name string, key []byte) (cipher.AEAD, error) {
+	if name != CipherAES256GCM {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedCipher, name)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func zeroize(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
@@ -0,0 +1,171 @@
+package keystore
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cheap parameters, the defaults take a while
+var testOptions = []Option{WithScrypt(1<<10, 8, 1)}
+
+func TestKeystore(t *testing.T) {
+	// the fixture is in the format of solana-keygen
+	deployer, err := types.AccountFromKeypairFile("../../types/testdata/keypair.json")
+	require.Nil(t, err)
+	payer := types.NewAccount()
+
+	ks := New()
+	require.Nil(t, ks.Add(deployer, "deployer", "correct horse", testOptions...))
+	require.Nil(t, ks.Add(payer, "payer", "battery staple", WithArgon2id(1, 1024, 1)))
+	assert.ErrorIs(t, ks.Add(deployer, "again", "correct horse", testOptions...), ErrDuplicateKey)
+
+	path := filepath.Join(t.TempDir(), "keystore.json")
+	require.Nil(t, ks.Save(path))
+	info, err := os.Stat(path)
+	require.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	loaded, err := Load(path)
+	require.Nil(t, err)
+	assert.Equal(t, []KeyInfo{
+		{Label: "deployer", PublicKey: deployer.PublicKey},
+		{Label: "payer", PublicKey: payer.PublicKey},
+	}, loaded.List())
+	assert.Equal(t, KDFArgon2id, loaded.Keys[1].KDF.Name)
+
+	account, err := loaded.Account(deployer.PublicKey, "correct horse")
+	require.Nil(t, err)
+	assert.Equal(t, deployer, account)
+	account.Zeroize()
+
+	account, err = loaded.AccountByLabel("payer", "battery staple")
+	require.Nil(t, err)
+	assert.Equal(t, payer, account)
+
+	_, err = loaded.Account(deployer.PublicKey, "battery staple")
+	assert.ErrorIs(t, err, ErrWrongPassword)
+	_, err = loaded.AccountByLabel("nobody", "")
+	assert.ErrorIs(t, err, ErrKeyNotFound)
+
+	// labels and removal don't need the password
+	require.Nil(t, loaded.SetLabel(payer.PublicKey, "fee payer"))
+	require.Nil(t, loaded.Remove(deployer.PublicKey))
+	assert.ErrorIs(t, loaded.Remove(deployer.PublicKey), ErrKeyNotFound)
+	require.Nil(t, loaded.Save(path))
+
+	loaded, err = Load(path)
+	require.Nil(t, err)
+	assert.Equal(t, []KeyInfo{{Label: "fee payer", PublicKey: payer.PublicKey}}, loaded.List())
+	account, err = loaded.AccountByLabel("fee payer", "battery staple")
+	require.Nil(t, err)
+	assert.Equal(t, payer, account)
+
+	// no temp file is left behind
+	entries, err := os.ReadDir(filepath.Dir(path))
+	require.Nil(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestKeystore_Tampered(t *testing.T) {
+	account := types.NewAccount()
+	ks := New()
+	require.Nil(t, ks.Add(account, "", "password", testOptions...))
+	data, err := json.Marshal(ks)
+	require.Nil(t, err)
+
+	tests := []struct {
+		name   string
+		modify func(e *Entry)
+		err    error
+	}{
+		{
+			name:   "ciphertext",
+			modify: func(e *Entry) { e.Ciphertext[0] ^= 1 },
+			err:    ErrWrongPassword,
+		},
+		{
+			name:   "public key",
+			modify: func(e *Entry) { e.PublicKey = types.NewAccount().PublicKey },
+			err:    ErrWrongPassword,
+		},
+		{
+			name:   "nonce",
+			modify: func(e *Entry) { e.Nonce = e.Nonce[1:] },
+			err:    ErrWrongPassword,
+		},
+		{
+			name:   "huge scrypt cost",
+			modify: func(e *Entry) { e.KDF.N = 1 << 30 },
+			err:    ErrUnsupportedKDF,
+		},
+		{
+			name:   "scrypt n above the cap",
+			modify: func(e *Entry) { e.KDF.N = maxScryptN << 1 },
+			err:    ErrUnsupportedKDF,
+		},
+		{
+			name:   "scrypt r above the cap",
+			modify: func(e *Entry) { e.KDF.R = maxScryptR + 1 },
+			err:    ErrUnsupportedKDF,
+		},
+		{
+			name:   "scrypt p above the cap",
+			modify: func(e *Entry) { e.KDF.P = maxScryptP + 1 },
+			err:    ErrUnsupportedKDF,
+		},
+		{
+			name: "argon2id memory above the cap",
+			modify: func(e *Entry) {
+				e.KDF = KDF{Name: KDFArgon2id, Salt: e.KDF.Salt, Time: 1, Memory: maxArgon2Memory + 1, Threads: 1}
+			},
+			err: ErrUnsupportedKDF,
+		},
+		{
+			name: "argon2id time above the cap",
+			modify: func(e *Entry) {
+				e.KDF = KDF{Name: KDFArgon2id, Salt: e.KDF.Salt, Time: maxArgon2Time + 1, Memory: 64, Threads: 1}
+			},
+			err: ErrUnsupportedKDF,
+		},
+		{
+			name:   "unknown kdf",
+			modify: func(e *Entry) { e.KDF.Name = "pbkdf2" },
+			err:    ErrUnsupportedKDF,
+		},
+		{
+			name:   "unknown cipher",
+			modify: func(e *Entry) { e.Cipher = "rot13" },
+			err:    ErrUnsupportedCipher,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ks, err := Decode(data)
+			require.Nil(t, err)
+			tt.modify(ks.Keys[0])
+			_, err = ks.Keys[0].open("password")
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+
+	_, err = Decode([]byte(`{"version":2,"keys":[]}`))
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+}
+
+func TestKDF_validate(t *testing.T) {
+	// the most expensive parameters accepted use at most 256 MiB
+	assert.Nil(t, KDF{Name: KDFScrypt, N: maxScryptN, R: maxScryptR, P: maxScryptP}.validate())
+	assert.LessOrEqual(t, 128*maxScryptN*maxScryptR, 256<<20)
+	assert.Nil(t, KDF{Name: KDFArgon2id, Time: maxArgon2Time, Memory: maxArgon2Memory, Threads: 255}.validate())
+	assert.LessOrEqual(t, maxArgon2Memory*1024, 256<<20)
+
+	assert.ErrorIs(t, KDF{Name: KDFScrypt, N: maxScryptN * 2, R: 1, P: 1}.validate(), ErrUnsupportedKDF)
+	assert.ErrorIs(t, KDF{Name: KDFScrypt, N: 2, R: maxScryptR + 1, P: 1}.validate(), ErrUnsupportedKDF)
+	assert.ErrorIs(t, KDF{Name: KDFArgon2id, Time: 1, Memory: maxArgon2Memory + 1, Threads: 1}.validate(), ErrUnsupportedKDF)
+}
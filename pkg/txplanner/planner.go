@@ -0,0 +1,124 @@
+// Package txplanner packs instructions into as few transactions as possible
+package txplanner
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/program/compute_budget"
+	"github.com/labyla/solana-go-sdk/types"
+)
+
+var ErrGroupTooLarge = errors.New("group doesn't fit in a transaction")
+
+type PlanParam struct {
+	FeePayer common.PublicKey
+	// Groups are the instructions to send, in order. the instructions of a group always
+	// end up in the same transaction, use Singles if there is no atomic group.
+	Groups [][]types.Instruction
+	// AddressLookupTableAccounts are the candidate tables, the ones which shrink a message are used
+	AddressLookupTableAccounts []types.AddressLookupTableAccount
+	// RecentBlockhash of the messages. it can be left empty and set before signing.
+	RecentBlockhash string
+	// ReserveComputeBudget leaves room for a SetComputeUnitLimit and a SetComputeUnitPrice
+	// instruction in every transaction, e.g. for client.TuneComputeBudget
+	ReserveComputeBudget bool
+	// MaxSize is the max transaction size. default: common.MaxTransactionSize
+	MaxSize int
+}
+
+type Batch struct {
+	// Instructions are the instructions in the message, the reserved compute budget
+	// instructions are not included
+	Instructions []types.Instruction
+	Message      types.Message
+}
+
+// Singles puts each instruction in its own group
+func Singles(instructions []types.Instruction) [][]types.Instruction {
+	groups := make([][]types.Instruction, 0, len(instructions))
+	for _, instruction := range instructions {
+		groups = append(groups, []types.Instruction{instruction})
+	}
+	return groups
+}
+
+// Plan packs the groups greedily in order. a batch is closed once the next group doesn't fit,
+// counting the signatures of all signers, the lookup tables and the reserved compute budget.
+func Plan(param PlanParam) ([]Batch, error) {
+	if param.MaxSize <= 0 {
+		param.MaxSize = common.MaxTransactionSize
+	}
+
+	batches := []Batch{}
+	current := []types.Instruction{}
+	var currentMessage types.Message
+	for i, group := range param.Groups {
+		if len(group) == 0 {
+			continue
+		}
+		candidate := append(append(make([]types.Instruction, 0, len(current)+len(group)), current...), group...)
+		message, fits, err := compile(param, candidate)
+		if err != nil {
+			return nil, err
+		}
+		if fits {
+			current, currentMessage = candidate, message
+			continue
+		}
+		if len(current) == 0 {
+			return nil, fmt.Errorf("%w: group #%v", ErrGroupTooLarge, i)
+		}
+
+		batches = append(batches, Batch{Instructions: current, Message: currentMessage})
+		message, fits, err = compile(param, group)
+		if err != nil {
+			return nil, err
+		}
+		if !fits {
+			return nil, fmt.Errorf("%w: group #%v", ErrGroupTooLarge, i)
+		}
+		current, currentMessage = group, message
+	}
+	if len(current) > 0 {
+		batches = append(batches, Batch{Instructions: current, Message: currentMessage})
+	}
+	return batches, nil
+}
+
+// compile returns the message of the instructions and whether it fits
+func compile(param PlanParam, instructions []types.Instruction) (types.Message, bool, error) {
+	message, fits, err := build(param, instructions)
+	if err != nil || !fits || !param.ReserveComputeBudget {
+		return message, fits, err
+	}
+	// the compute budget instructions have the same size whatever their values
+	_, fits, err = build(param, append([]types.Instruction{
+		compute_budget.SetComputeUnitLimit(compute_budget.SetComputeUnitLimitParam{
+			Units: compute_budget.MaxComputeUnitLimit,
+		}),
+		compute_budget.SetComputeUnitPrice(compute_budget.SetComputeUnitPriceParam{}),
+	}, instructions...))
+	return message, fits, err
+}
+
+func build(param PlanParam, instructions []types.Instruction) (types.Message, bool, error) {
+	message, err := types.NewMessageWithAutoLookupTables(types.NewMessageParam{
+		FeePayer:                   param.FeePayer,
+		Instructions:               instructions,
+		RecentBlockhash:            param.RecentBlockhash,
+		AddressLookupTableAccounts: param.AddressLookupTableAccounts,
+	})
+	if errors.Is(err, types.ErrMessageTooLarge) || errors.Is(err, types.ErrMessageTooManyAccounts) {
+		return types.Message{}, false, nil
+	}
+	if err != nil {
+		return types.Message{}, false, err
+	}
+	size, err := message.TransactionSize()
+	if err != nil {
+		return types.Message{}, false, fmt.Errorf("failed to get transaction size, err: %v", err)
+	}
+	return message, size <= param.MaxSize, nil
+}
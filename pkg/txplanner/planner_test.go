@@ -0,0 +1,159 @@
+package txplanner
+
+import (
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/program/system"
+	"github.com/labyla/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testBlockhash = "9rAtxuhtKn8qagc3UtZFyhLrw5zkh6etv43TibaXuSKo"
+
+func newTransfers(from common.PublicKey, n int) ([]types.Instruction, []common.PublicKey) {
+	instructions := make([]types.Instruction, 0, n)
+	recipients := make([]common.PublicKey, 0, n)
+	for i := 0; i < n; i++ {
+		to := types.NewAccount().PublicKey
+		recipients = append(recipients, to)
+		instructions = append(instructions, system.Transfer(system.TransferParam{
+			From:   from,
+			To:     to,
+			Amount: uint64(i + 1),
+		}))
+	}
+	return instructions, recipients
+}
+
+// checkBatches signs every batch and checks it fits, and that the instructions are in order
+func checkBatches(t *testing.T, batches []Batch, instructions []types.Instruction, signers []types.Signer) {
+	got := []types.Instruction{}
+	for _, batch := range batches {
+		got = append(got, batch.Instructions...)
+
+		tx, err := types.NewTransaction(types.NewTransactionParam{
+			Message: batch.Message,
+			Signers: signers,
+		})
+		require.Nil(t, err)
+		b, err := tx.Serialize()
+		require.Nil(t, err)
+		assert.LessOrEqual(t, len(b), common.MaxTransactionSize)
+	}
+	assert.Equal(t, instructions, got)
+}
+
+func TestPlan(t *testing.T) {
+	feePayer := types.NewAccount()
+	instructions, _ := newTransfers(feePayer.PublicKey, 100)
+
+	batches, err := Plan(PlanParam{
+		FeePayer:        feePayer.PublicKey,
+		Groups:          Singles(instructions),
+		RecentBlockhash: testBlockhash,
+	})
+	require.Nil(t, err)
+	checkBatches(t, batches, instructions, []types.Signer{feePayer})
+	// 215 bytes for a transfer and 49 more for each other
+	assert.Len(t, batches, 5)
+	assert.Len(t, batches[0].Instructions, 21)
+	assert.Len(t, batches[4].Instructions, 16)
+
+	// 52 bytes for the compute budget instructions, one transfer less
+	batches, err = Plan(PlanParam{
+		FeePayer:             feePayer.PublicKey,
+		Groups:               Singles(instructions),
+		RecentBlockhash:      testBlockhash,
+		ReserveComputeBudget: true,
+	})
+	require.Nil(t, err)
+	checkBatches(t, batches, instructions, []types.Signer{feePayer})
+	assert.Len(t, batches[0].Instructions, 20)
+}
+
+func TestPlan_Groups(t *testing.T) {
+	feePayer := types.NewAccount()
+	instructions, _ := newTransfers(feePayer.PublicKey, 60)
+	groups := [][]types.Instruction{}
+	for i := 0; i < len(instructions); i += 4 {
+		groups = append(groups, instructions[i:i+4])
+	}
+	groups = append(groups, nil)
+
+	batches, err := Plan(PlanParam{
+		FeePayer:        feePayer.PublicKey,
+		Groups:          groups,
+		RecentBlockhash: testBlockhash,
+	})
+	require.Nil(t, err)
+	checkBatches(t, batches, instructions, []types.Signer{feePayer})
+	for _, batch := range batches {
+		assert.Equal(t, 0, len(batch.Instructions)%4)
+	}
+	assert.Len(t, batches[0].Instructions, 20)
+
+	// a group which can never fit
+	_, err = Plan(PlanParam{
+		FeePayer:        feePayer.PublicKey,
+		Groups:          [][]types.Instruction{instructions[:1], instructions[1:30]},
+		RecentBlockhash: testBlockhash,
+	})
+	assert.ErrorIs(t, err, ErrGroupTooLarge)
+}
+
+func TestPlan_Signers(t *testing.T) {
+	feePayer := types.NewAccount()
+	senders := []types.Account{types.NewAccount(), types.NewAccount(), types.NewAccount()}
+	instructions := []types.Instruction{}
+	signers := []types.Signer{feePayer}
+	for _, sender := range senders {
+		transfers, _ := newTransfers(sender.PublicKey, 10)
+		instructions = append(instructions, transfers...)
+		signers = append(signers, sender)
+	}
+
+	batches, err := Plan(PlanParam{
+		FeePayer:        feePayer.PublicKey,
+		Groups:          Singles(instructions),
+		RecentBlockhash: testBlockhash,
+	})
+	require.Nil(t, err)
+	assert.Len(t, batches, 2)
+
+	got := []types.Instruction{}
+	for _, batch := range batches {
+		got = append(got, batch.Instructions...)
+		batchSigners := []types.Signer{}
+		for _, signer := range signers {
+			for _, account := range batch.Message.Accounts[:batch.Message.Header.NumRequireSignatures] {
+				if account == signer.Public() {
+					batchSigners = append(batchSigners, signer)
+				}
+			}
+		}
+		checkBatches(t, []Batch{batch}, batch.Instructions, batchSigners)
+	}
+	assert.Equal(t, instructions, got)
+}
+
+func TestPlan_AddressLookupTables(t *testing.T) {
+	feePayer := types.NewAccount()
+	instructions, recipients := newTransfers(feePayer.PublicKey, 100)
+	tables := []types.AddressLookupTableAccount{
+		{Key: types.NewAccount().PublicKey, Addresses: recipients[:50]},
+		{Key: types.NewAccount().PublicKey, Addresses: recipients[50:]},
+	}
+
+	batches, err := Plan(PlanParam{
+		FeePayer:                   feePayer.PublicKey,
+		Groups:                     Singles(instructions),
+		AddressLookupTableAccounts: tables,
+		RecentBlockhash:            testBlockhash,
+	})
+	require.Nil(t, err)
+	checkBatches(t, batches, instructions, []types.Signer{feePayer})
+	assert.Less(t, len(batches), 5)
+	assert.Equal(t, types.MessageVersion(types.MessageVersionV0), batches[0].Message.Version)
+}
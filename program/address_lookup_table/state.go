@@ -72,6 +72,9 @@ func DeserializeLookupTable(data []byte, accountOwner common.PublicKey) (Address
 		addressLookupTable.LastExtendedSlotStartIndex = data[current]
 		current += 1
 
+		if data[current] > 1 {
+			return AddressLookupTable{}, ErrInvalidAccountData
+		}
 		some := bool(data[current] == 1)
 		current += 1
 		if some {
@@ -81,9 +84,17 @@ func DeserializeLookupTable(data []byte, accountOwner common.PublicKey) (Address
 		}
 
 		addressLookupTable.padding = binary.LittleEndian.Uint16(data[current : current+2])
-		current += 2
 
+		// the meta is serialized in a fixed size, the addresses start after it even if
+		// there is no authority
+		current = int(LOOKUP_TABLE_META_SIZE)
+		if (len(data)-current)%32 != 0 {
+			return AddressLookupTable{}, ErrInvalidAccountDataSize
+		}
 		l := (len(data) - current) / 32
+		if uint(l) > LOOKUP_TABLE_MAX_ADDRESSES {
+			return AddressLookupTable{}, ErrInvalidAccountDataSize
+		}
 		addresses := make([]common.PublicKey, 0, l)
 		for i := 0; i < l; i++ {
 			addresses = append(addresses, common.PublicKeyFromBytes(data[current:current+32]))
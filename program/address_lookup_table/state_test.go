@@ -105,3 +105,48 @@ func TestDeserializeLookupTable(t *testing.T) {
 		})
 	}
 }
+
+func newTestLookupTableData(authority *common.PublicKey, addresses ...common.PublicKey) []byte {
+	data := make([]byte, LOOKUP_TABLE_META_SIZE)
+	data[0] = 1
+	for i := 4; i < 12; i++ {
+		data[i] = 0xff
+	}
+	if authority != nil {
+		data[21] = 1
+		copy(data[22:54], authority.Bytes())
+	}
+	for _, address := range addresses {
+		data = append(data, address.Bytes()...)
+	}
+	return data
+}
+
+func TestDeserializeLookupTable_Adversarial(t *testing.T) {
+	address := common.PublicKeyFromString("9aE476sH92Vz7DMPyq5WLPkrKWivxeuTKEFKd2sZZcde")
+
+	// without authority the addresses still start after the meta
+	got, err := DeserializeLookupTable(newTestLookupTableData(nil, address), common.AddressLookupTableProgramID)
+	assert.Nil(t, err)
+	assert.Nil(t, got.Authority)
+	assert.Equal(t, []common.PublicKey{address}, got.Addresses)
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{name: "truncated state", data: []byte{1, 0}, err: ErrInvalidAccountDataSize},
+		{name: "truncated meta", data: newTestLookupTableData(nil)[:55], err: ErrInvalidAccountDataSize},
+		{name: "partial address", data: append(newTestLookupTableData(nil, address), 1), err: ErrInvalidAccountDataSize},
+		{name: "invalid option", data: func() []byte { d := newTestLookupTableData(nil); d[21] = 2; return d }(), err: ErrInvalidAccountData},
+		{name: "unknown state", data: func() []byte { d := newTestLookupTableData(nil); d[0] = 2; return d }(), err: ErrInvalidAccountData},
+		{name: "too many addresses", data: newTestLookupTableData(nil, make([]common.PublicKey, LOOKUP_TABLE_MAX_ADDRESSES+1)...), err: ErrInvalidAccountDataSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := DeserializeLookupTable(tt.data, common.AddressLookupTableProgramID)
+			assert.ErrorIs(t, err, tt.err)
+		})
+	}
+}
@@ -0,0 +1,13 @@
+package associated_token_account
+
+import "errors"
+
+// custom errors of the associated token account program
+var (
+	ErrInvalidOwner = errors.New("associated token account owner does not match address derivation")
+)
+
+// CustomErrors are the custom errors of the associated token account program, indexed by error code
+var CustomErrors = []error{
+	ErrInvalidOwner,
+}
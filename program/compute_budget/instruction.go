@@ -18,6 +18,9 @@ const (
 	InstructionSetLoadedAccountsDataSizeLimit
 )
 
+// MaxComputeUnitLimit is the max compute unit limit of a transaction
+const MaxComputeUnitLimit uint32 = 1_400_000
+
 type RequestUnitsParam struct {
 	Units         uint32
 	AdditionalFee uint32
@@ -91,9 +94,14 @@ func SetComputeUnitLimit(param SetComputeUnitLimitParam) types.Instruction {
 		panic(err)
 	}
 
+	accounts := param.Accounts
+	if accounts == nil {
+		accounts = []types.AccountMeta{}
+	}
+
 	return types.Instruction{
 		ProgramID: common.ComputeBudgetProgramID,
-		Accounts:  param.Accounts,
+		Accounts:  accounts,
 		Data:      data,
 	}
 }
@@ -141,9 +149,14 @@ func SetComputeUnitPrice(param SetComputeUnitPriceParam) types.Instruction {
 		panic(err)
 	}
 
+	accounts := param.Accounts
+	if accounts == nil {
+		accounts = []types.AccountMeta{}
+	}
+
 	return types.Instruction{
 		ProgramID: common.ComputeBudgetProgramID,
-		Accounts:  param.Accounts,
+		Accounts:  accounts,
 		Data:      data,
 	}
 }
@@ -0,0 +1,132 @@
+package token_metadata
+
+import "errors"
+
+// custom errors of the token metadata program. the list covers the first variants of
+// MetadataError, newer codes are left to the caller.
+var (
+	ErrInstructionUnpackError                                 = errors.New("failed to unpack instruction data")
+	ErrInstructionPackError                                   = errors.New("failed to pack instruction data")
+	ErrNotRentExempt                                          = errors.New("lamport balance below rent-exempt threshold")
+	ErrAlreadyInitialized                                     = errors.New("already initialized")
+	ErrUninitialized                                          = errors.New("uninitialized")
+	ErrInvalidMetadataKey                                     = errors.New("metadata's key must match seed of ['metadata', program id, mint] provided")
+	ErrInvalidEditionKey                                      = errors.New("edition's key must match seed of ['metadata', program id, name, 'edition'] provided")
+	ErrUpdateAuthorityIncorrect                               = errors.New("update authority given does not match")
+	ErrUpdateAuthorityIsNotSigner                             = errors.New("update authority needs to be signer to update metadata")
+	ErrNotMintAuthority                                       = errors.New("you must be the mint authority and signer on this transaction")
+	ErrInvalidMintAuthority                                   = errors.New("mint authority provided does not match the authority on the mint")
+	ErrNameTooLong                                            = errors.New("name too long")
+	ErrSymbolTooLong                                          = errors.New("symbol too long")
+	ErrUriTooLong                                             = errors.New("uri too long")
+	ErrUpdateAuthorityMustBeEqualToMetadataAuthorityAndSigner = errors.New("update authority must be equivalent to the metadata's authority and also signer of this transaction")
+	ErrMintMismatch                                           = errors.New("mint given does not match mint on metadata")
+	ErrEditionsMustHaveExactlyOneToken                        = errors.New("editions must have exactly one token")
+	ErrMaxEditionsMintedAlready                               = errors.New("maximum editions printed already")
+	ErrTokenMintToFailed                                      = errors.New("token mint to failed")
+	ErrMasterRecordMismatch                                   = errors.New("the master edition record passed must match the master record on the edition given")
+	ErrDestinationMintMismatch                                = errors.New("the destination account does not have the right mint")
+	ErrEditionAlreadyMinted                                   = errors.New("an edition can only mint one of its kind")
+	ErrPrintingMintDecimalsShouldBeZero                       = errors.New("printing mint decimals should be zero")
+	ErrOneTimePrintingAuthorizationMintDecimalsShouldBeZero   = errors.New("one time printing authorization mint decimals should be zero")
+	ErrEditionMintDecimalsShouldBeZero                        = errors.New("edition mint decimals should be zero")
+	ErrTokenBurnFailed                                        = errors.New("token burn failed")
+	ErrTokenAccountOneTimeAuthMintMismatch                    = errors.New("the one time authorization mint does not match that on the token account")
+	ErrDerivedKeyInvalid                                      = errors.New("derived key invalid")
+	ErrPrintingMintMismatch                                   = errors.New("the printing mint does not match that on the master edition")
+	ErrOneTimePrintingAuthMintMismatch                        = errors.New("the one time printing auth mint does not match that on the master edition")
+	ErrTokenAccountMintMismatch                               = errors.New("the mint of the token account does not match the printing mint")
+	ErrTokenAccountMintMismatchV2                             = errors.New("the mint of the token account does not match the master metadata mint")
+	ErrNotEnoughTokens                                        = errors.New("not enough tokens to mint a limited edition")
+	ErrPrintingMintAuthorizationAccountMismatch               = errors.New("the mint on your authorization token holding account does not match your printing mint")
+	ErrAuthorizationTokenAccountOwnerMismatch                 = errors.New("the authorization token account has a different owner than the update authority for the master edition")
+	ErrDisabled                                               = errors.New("this feature is currently disabled")
+	ErrCreatorsTooLong                                        = errors.New("creators list too long")
+	ErrCreatorsMustBeAtleastOne                               = errors.New("creators must be at least one if set")
+	ErrMustBeOneOfCreators                                    = errors.New("if using a creators array, you must be one of the creators listed")
+	ErrNoCreatorsPresentOnMetadata                            = errors.New("this metadata does not have creators")
+	ErrCreatorNotFound                                        = errors.New("this creator address was not found")
+	ErrInvalidBasisPoints                                     = errors.New("basis points cannot be more than 10000")
+	ErrPrimarySaleCanOnlyBeFlippedToTrue                      = errors.New("primary sale can only be flipped to true and is immutable")
+	ErrOwnerMismatch                                          = errors.New("owner does not match that on the account given")
+	ErrNoBalanceInAccountForAuthorization                     = errors.New("this account has no tokens to be used for authorization")
+	ErrShareTotalMustBe100                                    = errors.New("share total must equal 100 for creator array")
+	ErrReservationExists                                      = errors.New("this reservation list already exists")
+	ErrReservationDoesNotExist                                = errors.New("this reservation list does not exist")
+	ErrReservationNotSet                                      = errors.New("this reservation list exists but was never set with reservations")
+	ErrReservationAlreadyMade                                 = errors.New("this reservation list has already been set")
+	ErrBeyondMaxAddressSize                                   = errors.New("provided more addresses than max allowed in single reservation")
+	ErrNumericalOverflowError                                 = errors.New("numerical overflow error")
+	ErrReservationBreachesMaximumSupply                       = errors.New("this reservation would go beyond the maximum supply of the master edition")
+	ErrAddressNotInReservation                                = errors.New("address not in reservation")
+	ErrCannotVerifyAnotherCreator                             = errors.New("you cannot unilaterally verify another creator, they must sign")
+	ErrCannotUnverifyAnotherCreator                           = errors.New("you cannot unilaterally unverify another creator")
+	ErrSpotMismatch                                           = errors.New("in initial reservation setting, spots remaining should equal total spots")
+	ErrIncorrectOwner                                         = errors.New("incorrect account owner")
+	ErrPrintingWouldBreachMaximumSupply                       = errors.New("printing these tokens would breach the maximum supply limit of the master edition")
+	ErrDataIsImmutable                                        = errors.New("data is immutable")
+)
+
+// CustomErrors are the custom errors of the token metadata program, indexed by error code
+var CustomErrors = []error{
+	ErrInstructionUnpackError,
+	ErrInstructionPackError,
+	ErrNotRentExempt,
+	ErrAlreadyInitialized,
+	ErrUninitialized,
+	ErrInvalidMetadataKey,
+	ErrInvalidEditionKey,
+	ErrUpdateAuthorityIncorrect,
+	ErrUpdateAuthorityIsNotSigner,
+	ErrNotMintAuthority,
+	ErrInvalidMintAuthority,
+	ErrNameTooLong,
+	ErrSymbolTooLong,
+	ErrUriTooLong,
+	ErrUpdateAuthorityMustBeEqualToMetadataAuthorityAndSigner,
+	ErrMintMismatch,
+	ErrEditionsMustHaveExactlyOneToken,
+	ErrMaxEditionsMintedAlready,
+	ErrTokenMintToFailed,
+	ErrMasterRecordMismatch,
+	ErrDestinationMintMismatch,
+	ErrEditionAlreadyMinted,
+	ErrPrintingMintDecimalsShouldBeZero,
+	ErrOneTimePrintingAuthorizationMintDecimalsShouldBeZero,
+	ErrEditionMintDecimalsShouldBeZero,
+	ErrTokenBurnFailed,
+	ErrTokenAccountOneTimeAuthMintMismatch,
+	ErrDerivedKeyInvalid,
+	ErrPrintingMintMismatch,
+	ErrOneTimePrintingAuthMintMismatch,
+	ErrTokenAccountMintMismatch,
+	ErrTokenAccountMintMismatchV2,
+	ErrNotEnoughTokens,
+	ErrPrintingMintAuthorizationAccountMismatch,
+	ErrAuthorizationTokenAccountOwnerMismatch,
+	ErrDisabled,
+	ErrCreatorsTooLong,
+	ErrCreatorsMustBeAtleastOne,
+	ErrMustBeOneOfCreators,
+	ErrNoCreatorsPresentOnMetadata,
+	ErrCreatorNotFound,
+	ErrInvalidBasisPoints,
+	ErrPrimarySaleCanOnlyBeFlippedToTrue,
+	ErrOwnerMismatch,
+	ErrNoBalanceInAccountForAuthorization,
+	ErrShareTotalMustBe100,
+	ErrReservationExists,
+	ErrReservationDoesNotExist,
+	ErrReservationNotSet,
+	ErrReservationAlreadyMade,
+	ErrBeyondMaxAddressSize,
+	ErrNumericalOverflowError,
+	ErrReservationBreachesMaximumSupply,
+	ErrAddressNotInReservation,
+	ErrCannotVerifyAnotherCreator,
+	ErrCannotUnverifyAnotherCreator,
+	ErrSpotMismatch,
+	ErrIncorrectOwner,
+	ErrPrintingWouldBreachMaximumSupply,
+	ErrDataIsImmutable,
+}
@@ -0,0 +1,99 @@
+// Package program decodes the custom error codes returned by on-chain programs.
+//
+// the system, token, token-2022, associated token account, stake and metaplex token
+// metadata programs are registered by default. the address lookup table and compute
+// budget programs don't define custom errors, they fail with the builtin instruction
+// errors which are already named by rpc.InstructionError.
+package program
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/program/associated_token_account"
+	"github.com/labyla/solana-go-sdk/program/metaplex/token_metadata"
+	"github.com/labyla/solana-go-sdk/program/stake"
+	"github.com/labyla/solana-go-sdk/program/system"
+	"github.com/labyla/solana-go-sdk/program/token"
+	"github.com/labyla/solana-go-sdk/program/token2022"
+)
+
+var registry = struct {
+	sync.RWMutex
+	errors map[common.PublicKey]map[uint32]error
+}{
+	errors: map[common.PublicKey]map[uint32]error{},
+}
+
+func init() {
+	RegisterErrors(common.SystemProgramID, system.CustomErrors)
+	RegisterErrors(common.TokenProgramID, token.CustomErrors)
+	RegisterErrors(common.Token2022ProgramID, token2022.CustomErrors)
+	RegisterErrors(common.SPLAssociatedTokenAccountProgramID, associated_token_account.CustomErrors)
+	RegisterErrors(common.StakeProgramID, stake.CustomErrors)
+	RegisterErrors(common.MetaplexTokenMetaProgramID, token_metadata.CustomErrors)
+}
+
+// RegisterErrors registers the custom errors of a program, errs[i] is the error of code i
+// as a rust error enum is numbered. registering the same code twice replaces the previous error.
+func RegisterErrors(programID common.PublicKey, errs []error) {
+	registry.Lock()
+	defer registry.Unlock()
+	m := registry.errors[programID]
+	if m == nil {
+		m = make(map[uint32]error, len(errs))
+		registry.errors[programID] = m
+	}
+	for i, err := range errs {
+		m[uint32(i)] = err
+	}
+}
+
+// RegisterError registers a single custom error of a program. e.g. anchor programs start
+// their error codes at 6000.
+func RegisterError(programID common.PublicKey, code uint32, err error) {
+	registry.Lock()
+	defer registry.Unlock()
+	m := registry.errors[programID]
+	if m == nil {
+		m = map[uint32]error{}
+		registry.errors[programID] = m
+	}
+	m[code] = err
+}
+
+// LookupError returns the registered error of the code, or nil if it is unknown
+func LookupError(programID common.PublicKey, code uint32) error {
+	registry.RLock()
+	defer registry.RUnlock()
+	return registry.errors[programID][code]
+}
+
+// CustomError is a custom error code returned by a program
+type CustomError struct {
+	ProgramID common.PublicKey
+	Code      uint32
+	// Err is the registered error of the code, nil if it is unknown
+	Err error
+}
+
+// DecodeError looks up the custom error code of a program
+func DecodeError(programID common.PublicKey, code uint32) *CustomError {
+	return &CustomError{
+		ProgramID: programID,
+		Code:      code,
+		Err:       LookupError(programID, code),
+	}
+}
+
+func (e *CustomError) Error() string {
+	if e.Err == nil {
+		return fmt.Sprintf("program %v failed: custom program error: %#x", e.ProgramID, e.Code)
+	}
+	return fmt.Sprintf("program %v failed: custom program error: %#x: %v", e.ProgramID, e.Code, e.Err)
+}
+
+func (e *CustomError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,43 @@
+package program
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/program/stake"
+	"github.com/labyla/solana-go-sdk/program/system"
+	"github.com/labyla/solana-go-sdk/program/token"
+	"github.com/labyla/solana-go-sdk/program/token2022"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeError(t *testing.T) {
+	err := DecodeError(common.TokenProgramID, 1)
+	assert.ErrorIs(t, err, token.ErrInsufficientFunds)
+	assert.Equal(t, "program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA failed: custom program error: 0x1: insufficient funds", err.Error())
+
+	assert.ErrorIs(t, DecodeError(common.SystemProgramID, 0), system.ErrAccountAlreadyInUse)
+	assert.ErrorIs(t, DecodeError(common.Token2022ProgramID, 30), token2022.ErrTransferFeeExceedsMaximum)
+	assert.ErrorIs(t, DecodeError(common.StakeProgramID, 1), stake.ErrLockupInForce)
+
+	// unknown code
+	err = DecodeError(common.TokenProgramID, 9999)
+	assert.Nil(t, err.Err)
+	assert.Equal(t, "program TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA failed: custom program error: 0x270f", err.Error())
+}
+
+func TestRegisterError(t *testing.T) {
+	programID := common.PublicKeyFromString("35HSbe2xiLfid5QJeETGnUsGhkAiJWRKPrEGdQQ5xXrP")
+	errSlippage := errors.New("slippage exceeded")
+	errStale := errors.New("stale oracle")
+
+	assert.Nil(t, LookupError(programID, 6000))
+
+	RegisterError(programID, 6000, errSlippage)
+	assert.ErrorIs(t, DecodeError(programID, 6000), errSlippage)
+
+	RegisterErrors(programID, []error{errStale})
+	assert.ErrorIs(t, DecodeError(programID, 0), errStale)
+	assert.ErrorIs(t, DecodeError(programID, 6000), errSlippage)
+}
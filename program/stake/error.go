@@ -0,0 +1,45 @@
+package stake
+
+import "errors"
+
+// custom errors of the stake program
+var (
+	ErrNoCreditsToRedeem                                              = errors.New("not enough credits to redeem")
+	ErrLockupInForce                                                  = errors.New("lockup has not yet expired")
+	ErrAlreadyDeactivated                                             = errors.New("stake already deactivated")
+	ErrTooSoonToRedelegate                                            = errors.New("one re-delegation permitted per epoch")
+	ErrInsufficientStake                                              = errors.New("split amount is more than is staked")
+	ErrMergeTransientStake                                            = errors.New("stake account with transient stake cannot be merged")
+	ErrMergeMismatch                                                  = errors.New("stake account merge failed due to different authority, lockups or state")
+	ErrCustodianMissing                                               = errors.New("custodian address not present")
+	ErrCustodianSignatureMissing                                      = errors.New("custodian signature not present")
+	ErrInsufficientReferenceVotes                                     = errors.New("insufficient voting activity in the reference vote account")
+	ErrVoteAddressMismatch                                            = errors.New("stake account is not delegated to the provided vote account")
+	ErrMinimumDelinquentEpochsForDeactivationNotMet                   = errors.New("stake account has not been delinquent for the minimum epochs required for deactivation")
+	ErrInsufficientDelegation                                         = errors.New("delegation amount is less than the minimum")
+	ErrRedelegateTransientOrInactiveStake                             = errors.New("stake account with transient or inactive stake cannot be redelegated")
+	ErrRedelegateToSameVoteAccount                                    = errors.New("stake redelegation to the same vote account is not permitted")
+	ErrRedelegatedStakeMustFullyActivateBeforeDeactivationIsPermitted = errors.New("redelegated stake must be fully activated before deactivation")
+	ErrEpochRewardsActive                                             = errors.New("stake action is not permitted while the epoch rewards period is active")
+)
+
+// CustomErrors are the custom errors of the stake program, indexed by error code
+var CustomErrors = []error{
+	ErrNoCreditsToRedeem,
+	ErrLockupInForce,
+	ErrAlreadyDeactivated,
+	ErrTooSoonToRedelegate,
+	ErrInsufficientStake,
+	ErrMergeTransientStake,
+	ErrMergeMismatch,
+	ErrCustodianMissing,
+	ErrCustodianSignatureMissing,
+	ErrInsufficientReferenceVotes,
+	ErrVoteAddressMismatch,
+	ErrMinimumDelinquentEpochsForDeactivationNotMet,
+	ErrInsufficientDelegation,
+	ErrRedelegateTransientOrInactiveStake,
+	ErrRedelegateToSameVoteAccount,
+	ErrRedelegatedStakeMustFullyActivateBeforeDeactivationIsPermitted,
+	ErrEpochRewardsActive,
+}
@@ -34,8 +34,8 @@ type NonceAccount struct {
 }
 
 func NonceAccountDeserialize(data []byte) (NonceAccount, error) {
-	if len(data) < NonceAccountSize {
-		return NonceAccount{}, fmt.Errorf("nonce account data size is not enough")
+	if len(data) != NonceAccountSize {
+		return NonceAccount{}, fmt.Errorf("nonce account data size should be %v, got %v", NonceAccountSize, len(data))
 	}
 	version := binary.LittleEndian.Uint32(data[:4])
 	state := binary.LittleEndian.Uint32(data[4:8])
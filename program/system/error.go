@@ -0,0 +1,29 @@
+package system
+
+import "errors"
+
+// custom errors of the system program
+var (
+	ErrAccountAlreadyInUse           = errors.New("an account with the same address already exists")
+	ErrResultWithNegativeLamports    = errors.New("account does not have enough SOL to perform the operation")
+	ErrInvalidProgramId              = errors.New("cannot assign account to this program id")
+	ErrInvalidAccountDataLength      = errors.New("cannot allocate account data of this length")
+	ErrMaxSeedLengthExceeded         = errors.New("length of requested seed is too long")
+	ErrAddressWithSeedMismatch       = errors.New("provided address does not match addressed derived from seed")
+	ErrNonceNoRecentBlockhashes      = errors.New("advancing stored nonce requires a populated RecentBlockhashes sysvar")
+	ErrNonceBlockhashNotExpired      = errors.New("stored nonce is still in recent_blockhashes")
+	ErrNonceUnexpectedBlockhashValue = errors.New("specified nonce does not match stored nonce")
+)
+
+// CustomErrors are the custom errors of the system program, indexed by error code
+var CustomErrors = []error{
+	ErrAccountAlreadyInUse,
+	ErrResultWithNegativeLamports,
+	ErrInvalidProgramId,
+	ErrInvalidAccountDataLength,
+	ErrMaxSeedLengthExceeded,
+	ErrAddressWithSeedMismatch,
+	ErrNonceNoRecentBlockhashes,
+	ErrNonceBlockhashNotExpired,
+	ErrNonceUnexpectedBlockhashValue,
+}
@@ -46,3 +46,11 @@ func TestNonceAccountDeserialize(t *testing.T) {
 		})
 	}
 }
+
+func TestNonceAccountDeserialize_Size(t *testing.T) {
+	for _, l := range []int{0, NonceAccountSize - 1, NonceAccountSize + 1} {
+		if _, err := NonceAccountDeserialize(make([]byte, l)); err == nil {
+			t.Errorf("NonceAccountDeserialize() of %v bytes should fail", l)
+		}
+	}
+}
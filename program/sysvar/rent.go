@@ -0,0 +1,58 @@
+package sysvar
+
+import (
+	"math"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/pkg/bytes_decoder"
+)
+
+// AccountStorageOverhead is the bytes every account is charged for besides its data
+const AccountStorageOverhead = 128
+
+type Rent struct {
+	LamportsPerByteYear uint64
+	ExemptionThreshold  float64
+	BurnPercent         uint8
+}
+
+// DefaultRent is the rent of the mainnet, devnet and testnet clusters
+var DefaultRent = Rent{
+	LamportsPerByteYear: 3480,
+	ExemptionThreshold:  2.0,
+	BurnPercent:         50,
+}
+
+// MinimumBalance returns the lamports an account with the data length needs to be rent exempt
+func (r Rent) MinimumBalance(dataLen uint64) uint64 {
+	return uint64(float64((AccountStorageOverhead+dataLen)*r.LamportsPerByteYear) * r.ExemptionThreshold)
+}
+
+func DeserializeRent(data []byte, owner common.PublicKey) (Rent, error) {
+	if owner != common.SysVarPubkey {
+		return Rent{}, ErrInvalidAccountOwner
+	}
+
+	current := 0
+	lamportsPerByteYear, err := bytes_decoder.GetUint64(&current, data)
+	if err != nil {
+		return Rent{}, err
+	}
+	exemptionThreshold, err := bytes_decoder.GetUint64(&current, data)
+	if err != nil {
+		return Rent{}, err
+	}
+	burnPercent, err := bytes_decoder.GetUint8(&current, data)
+	if err != nil {
+		return Rent{}, err
+	}
+	if current != len(data) {
+		return Rent{}, ErrInvalidAccountDataSize
+	}
+
+	return Rent{
+		LamportsPerByteYear: lamportsPerByteYear,
+		ExemptionThreshold:  math.Float64frombits(exemptionThreshold),
+		BurnPercent:         burnPercent,
+	}, nil
+}
@@ -0,0 +1,30 @@
+package sysvar
+
+import (
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRent_MinimumBalance(t *testing.T) {
+	// the same as getMinimumBalanceForRentExemption
+	assert.Equal(t, uint64(890880), DefaultRent.MinimumBalance(0))
+	assert.Equal(t, uint64(1461600), DefaultRent.MinimumBalance(82))
+	assert.Equal(t, uint64(2039280), DefaultRent.MinimumBalance(165))
+}
+
+func TestDeserializeRent(t *testing.T) {
+	data := []byte{152, 13, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 64, 50}
+
+	rent, err := DeserializeRent(data, common.SysVarPubkey)
+	assert.Nil(t, err)
+	assert.Equal(t, DefaultRent, rent)
+
+	_, err = DeserializeRent(data, common.SystemProgramID)
+	assert.ErrorIs(t, err, ErrInvalidAccountOwner)
+	_, err = DeserializeRent(data[:16], common.SysVarPubkey)
+	assert.Error(t, err)
+	_, err = DeserializeRent(append(data, 0), common.SysVarPubkey)
+	assert.ErrorIs(t, err, ErrInvalidAccountDataSize)
+}
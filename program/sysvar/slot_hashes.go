@@ -1,6 +1,8 @@
 package sysvar
 
 import (
+	"fmt"
+
 	"github.com/labyla/solana-go-sdk/common"
 	"github.com/labyla/solana-go-sdk/pkg/bytes_decoder"
 )
@@ -18,13 +20,18 @@ func DeserializeSlotHashes(data []byte, owner common.PublicKey) (SlotHashes, err
 	}
 
 	current := 0
-	len, err := bytes_decoder.GetUint64(&current, data)
+	count, err := bytes_decoder.GetUint64(&current, data)
 	if err != nil {
 		return SlotHashes{}, err
 	}
 
-	v := make([]SlotHash, 0, len)
-	for i := uint64(0); i < len; i++ {
+	// each entry is a slot and a hash
+	if count > uint64(len(data)-current)/40 {
+		return SlotHashes{}, fmt.Errorf("%w: %v entries", ErrInvalidAccountDataSize, count)
+	}
+
+	v := make([]SlotHash, 0, count)
+	for i := uint64(0); i < count; i++ {
 		slot, err := bytes_decoder.GetUint64(&current, data)
 		if err != nil {
 			return SlotHashes{}, err
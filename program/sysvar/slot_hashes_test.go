@@ -61,3 +61,15 @@ func TestDeserializeSlotHashes(t *testing.T) {
 		})
 	}
 }
+
+func TestDeserializeSlotHashes_Adversarial(t *testing.T) {
+	for _, data := range [][]byte{
+		{1, 2, 3},
+		// a huge count must not be allocated
+		{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+		append([]byte{2, 0, 0, 0, 0, 0, 0, 0}, make([]byte, 79)...),
+	} {
+		_, err := DeserializeSlotHashes(data, common.SysVarPubkey)
+		assert.Error(t, err)
+	}
+}
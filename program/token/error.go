@@ -5,4 +5,53 @@ import "errors"
 var (
 	ErrInvalidAccountOwner    = errors.New("invalid account owner")
 	ErrInvalidAccountDataSize = errors.New("invalid account data size")
+	ErrInvalidAccountData     = errors.New("invalid account data")
 )
+
+// custom errors of the token program
+var (
+	ErrNotRentExempt                  = errors.New("lamport balance below rent-exempt threshold")
+	ErrInsufficientFunds              = errors.New("insufficient funds")
+	ErrInvalidMint                    = errors.New("invalid mint")
+	ErrMintMismatch                   = errors.New("account not associated with this mint")
+	ErrOwnerMismatch                  = errors.New("owner does not match")
+	ErrFixedSupply                    = errors.New("fixed supply")
+	ErrAlreadyInUse                   = errors.New("already in use")
+	ErrInvalidNumberOfProvidedSigners = errors.New("invalid number of provided signers")
+	ErrInvalidNumberOfRequiredSigners = errors.New("invalid number of required signers")
+	ErrUninitializedState             = errors.New("state is uninitialized")
+	ErrNativeNotSupported             = errors.New("instruction does not support native tokens")
+	ErrNonNativeHasBalance            = errors.New("non-native account can only be closed if its balance is zero")
+	ErrInvalidInstruction             = errors.New("invalid instruction")
+	ErrInvalidState                   = errors.New("state is invalid for requested operation")
+	ErrOverflow                       = errors.New("operation overflowed")
+	ErrAuthorityTypeNotSupported      = errors.New("account does not support specified authority type")
+	ErrMintCannotFreeze               = errors.New("this token mint cannot freeze accounts")
+	ErrAccountFrozen                  = errors.New("account is frozen")
+	ErrMintDecimalsMismatch           = errors.New("the provided decimals value different from the mint decimals")
+	ErrNonNativeNotSupported          = errors.New("instruction does not support non-native tokens")
+)
+
+// CustomErrors are the custom errors of the token program, indexed by error code
+var CustomErrors = []error{
+	ErrNotRentExempt,
+	ErrInsufficientFunds,
+	ErrInvalidMint,
+	ErrMintMismatch,
+	ErrOwnerMismatch,
+	ErrFixedSupply,
+	ErrAlreadyInUse,
+	ErrInvalidNumberOfProvidedSigners,
+	ErrInvalidNumberOfRequiredSigners,
+	ErrUninitializedState,
+	ErrNativeNotSupported,
+	ErrNonNativeHasBalance,
+	ErrInvalidInstruction,
+	ErrInvalidState,
+	ErrOverflow,
+	ErrAuthorityTypeNotSupported,
+	ErrMintCannotFreeze,
+	ErrAccountFrozen,
+	ErrMintDecimalsMismatch,
+	ErrNonNativeNotSupported,
+}
@@ -32,18 +32,17 @@ func MultisigAccountFromData(data []byte) (MultisigAccount, error) {
 	m := uint8(data[0])
 
 	n := uint8(data[1])
+	if n > MaxSigners || m > n {
+		return MultisigAccount{}, ErrInvalidAccountData
+	}
 
 	isInitialized := data[2] == 1
 
-	signers := make([]common.PublicKey, 0, MaxSigners)
-	current := 3
-	for current < MultisigAccountSize {
-		pubkey := common.PublicKeyFromBytes(data[current : current+32])
-		if pubkey == (common.PublicKey{}) {
-			break
-		}
-		signers = append(signers, pubkey)
-		current += 32
+	// only the first n of the MaxSigners slots are in use
+	signers := make([]common.PublicKey, 0, n)
+	for i := 0; i < int(n); i++ {
+		current := 3 + i*32
+		signers = append(signers, common.PublicKeyFromBytes(data[current:current+32]))
 	}
 
 	return MultisigAccount{
@@ -104,13 +103,19 @@ func MintAccountFromData(data []byte) (MintAccount, error) {
 		FreezeAuthority: freezeAuthority,
 	}
 
-	// Parse Token-2022 extensions if present
-	if account.IsToken2022 = token2022.HasExtensions(data); account.IsToken2022 {
-		extensions, err := token2022.ParseMintExtensions(data)
-		if err == nil {
-			account.Extensions = extensions
-		}
+	if len(data) == MintAccountSize {
+		return account, nil
+	}
+	// a token-2022 mint is padded to the size of a token account and followed by the account type and extensions
+	if !token2022.HasExtensions(data) {
+		return MintAccount{}, ErrInvalidAccountDataSize
 	}
+	extensions, err := token2022.ParseMintExtensions(data)
+	if err != nil {
+		return MintAccount{}, err
+	}
+	account.IsToken2022 = true
+	account.Extensions = extensions
 
 	return account, nil
 }
@@ -191,13 +196,16 @@ func TokenAccountFromData(data []byte) (TokenAccount, error) {
 		CloseAuthority:  closeAuthority,
 	}
 
-	// Parse Token-2022 extensions if present
-	if account.IsToken2022 = token2022.HasExtensions(data); account.IsToken2022 {
-		extensions, err := token2022.ParseAccountExtensions(data)
-		if err == nil {
-			account.Extensions = extensions
-		}
+	if len(data) == TokenAccountSize {
+		return account, nil
+	}
+	// a token-2022 account is followed by the account type and extensions
+	extensions, err := token2022.ParseAccountExtensions(data)
+	if err != nil {
+		return TokenAccount{}, err
 	}
+	account.IsToken2022 = true
+	account.Extensions = extensions
 
 	return account, nil
 }
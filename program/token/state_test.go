@@ -5,6 +5,7 @@ import (
 
 	"github.com/labyla/solana-go-sdk/common"
 	"github.com/labyla/solana-go-sdk/pkg/pointer"
+	"github.com/labyla/solana-go-sdk/program/token2022"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -258,3 +259,93 @@ func TestDeserializeTokenAccount(t *testing.T) {
 		})
 	}
 }
+
+func TestMintAccountFromData_Adversarial(t *testing.T) {
+	mint := make([]byte, MintAccountSize)
+	mint[45] = 1
+	token2022Mint := func(rest ...byte) []byte {
+		return append(append(append([]byte{}, mint...), make([]byte, TokenAccountSize-MintAccountSize)...), rest...)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{name: "short", data: mint[:MintAccountSize-1], err: ErrInvalidAccountDataSize},
+		{name: "trailing bytes", data: append(append([]byte{}, mint...), 1, 2, 3), err: ErrInvalidAccountDataSize},
+		{name: "padded without account type", data: token2022Mint(), err: ErrInvalidAccountDataSize},
+		{name: "wrong account type", data: token2022Mint(byte(token2022.AccountTypeAccount)), err: token2022.ErrInvalidAccountDataSize},
+		{name: "truncated tlv", data: token2022Mint(byte(token2022.AccountTypeMint), 3, 0, 32, 0, 1), err: token2022.ErrInvalidAccountDataSize},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MintAccountFromData(tt.data)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, MintAccount{}, got)
+		})
+	}
+
+	got, err := MintAccountFromData(token2022Mint(byte(token2022.AccountTypeMint), 9, 0, 0, 0))
+	assert.Nil(t, err)
+	assert.True(t, got.IsToken2022)
+	assert.Equal(t, &token2022.MintExtensions{NonTransferable: &token2022.NonTransferable{}}, got.Extensions)
+}
+
+func TestTokenAccountFromData_Adversarial(t *testing.T) {
+	account := make([]byte, TokenAccountSize)
+	account[108] = byte(TokenAccountStateInitialized)
+	token2022Account := func(rest ...byte) []byte {
+		return append(append([]byte{}, account...), rest...)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "wrong account type", data: token2022Account(byte(token2022.AccountTypeMint))},
+		{name: "truncated tlv", data: token2022Account(byte(token2022.AccountTypeAccount), 1, 0, 8, 0, 1)},
+		{name: "short extension", data: token2022Account(byte(token2022.AccountTypeAccount), 2, 0, 1, 0, 1)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := TokenAccountFromData(tt.data)
+			assert.ErrorIs(t, err, token2022.ErrInvalidAccountDataSize)
+			assert.Equal(t, TokenAccount{}, got)
+		})
+	}
+
+	_, err := TokenAccountFromData(account[:TokenAccountSize-1])
+	assert.ErrorIs(t, err, ErrInvalidAccountDataSize)
+
+	got, err := TokenAccountFromData(token2022Account(byte(token2022.AccountTypeAccount), 7, 0, 0, 0))
+	assert.Nil(t, err)
+	assert.True(t, got.IsToken2022)
+	assert.Equal(t, &token2022.AccountExtensions{ImmutableOwner: &token2022.ImmutableOwner{}}, got.Extensions)
+}
+
+func TestMultisigAccountFromData_Adversarial(t *testing.T) {
+	multisig := func(m, n uint8) []byte {
+		data := make([]byte, MultisigAccountSize)
+		data[0], data[1], data[2] = m, n, 1
+		return data
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		err  error
+	}{
+		{name: "short", data: multisig(1, 1)[:MultisigAccountSize-1], err: ErrInvalidAccountDataSize},
+		{name: "trailing byte", data: append(multisig(1, 1), 0), err: ErrInvalidAccountDataSize},
+		{name: "too many signers", data: multisig(1, MaxSigners+1), err: ErrInvalidAccountData},
+		{name: "m above n", data: multisig(3, 2), err: ErrInvalidAccountData},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := MultisigAccountFromData(tt.data)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, MultisigAccount{}, got)
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package token2022
+
+import (
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/pkg/bincode"
+	"github.com/labyla/solana-go-sdk/types"
+)
+
+// CpiGuardInstruction is the second byte of the data of an InstructionExtensionCpiGuard
+type CpiGuardInstruction uint8
+
+const (
+	CpiGuardInstructionEnable CpiGuardInstruction = iota
+	CpiGuardInstructionDisable
+)
+
+type EnableCpiGuardParam struct {
+	Account common.PublicKey
+	Auth    common.PublicKey
+	Signers []common.PublicKey
+}
+
+// EnableCpiGuard stops programs from moving the tokens of an account with the signature of its owner,
+// only delegates can. the account is reallocated if it has no CpiGuard extension.
+func EnableCpiGuard(param EnableCpiGuardParam) types.Instruction {
+	return cpiGuard(CpiGuardInstructionEnable, param.Account, param.Auth, param.Signers)
+}
+
+type DisableCpiGuardParam struct {
+	Account common.PublicKey
+	Auth    common.PublicKey
+	Signers []common.PublicKey
+}
+
+func DisableCpiGuard(param DisableCpiGuardParam) types.Instruction {
+	return cpiGuard(CpiGuardInstructionDisable, param.Account, param.Auth, param.Signers)
+}
+
+func cpiGuard(instruction CpiGuardInstruction, account, auth common.PublicKey, signers []common.PublicKey) types.Instruction {
+	data, err := bincode.SerializeData(struct {
+		Instruction         Instruction
+		CpiGuardInstruction CpiGuardInstruction
+	}{
+		Instruction:         InstructionExtensionCpiGuard,
+		CpiGuardInstruction: instruction,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts:  ownerAccounts(account, auth, signers),
+		Data:      data,
+	}
+}
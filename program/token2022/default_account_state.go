@@ -0,0 +1,90 @@
+package token2022
+
+import (
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/pkg/bincode"
+	"github.com/labyla/solana-go-sdk/types"
+)
+
+// AccountState is the state of a token account
+type AccountState uint8
+
+const (
+	AccountStateUninitialized AccountState = iota
+	AccountStateInitialized
+	AccountStateFrozen
+)
+
+// DefaultAccountStateInstruction is the second byte of the data of an InstructionExtensionDefaultAccountState
+type DefaultAccountStateInstruction uint8
+
+const (
+	DefaultAccountStateInstructionInitialize DefaultAccountStateInstruction = iota
+	DefaultAccountStateInstructionUpdate
+)
+
+type InitializeDefaultAccountStateParam struct {
+	Mint  common.PublicKey
+	State AccountState
+}
+
+// InitializeDefaultAccountState sets the state of the new token accounts of a mint, it must come before InitializeMint.
+// a mint with frozen accounts by default needs a freeze authority.
+func InitializeDefaultAccountState(param InitializeDefaultAccountStateParam) types.Instruction {
+	data, err := bincode.SerializeData(struct {
+		Instruction                    Instruction
+		DefaultAccountStateInstruction DefaultAccountStateInstruction
+		State                          AccountState
+	}{
+		Instruction:                    InstructionExtensionDefaultAccountState,
+		DefaultAccountStateInstruction: DefaultAccountStateInstructionInitialize,
+		State:                          param.State,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: param.Mint, IsSigner: false, IsWritable: true},
+		},
+		Data: data,
+	}
+}
+
+type UpdateDefaultAccountStateParam struct {
+	Mint common.PublicKey
+	// Auth is the freeze authority of the mint
+	Auth    common.PublicKey
+	Signers []common.PublicKey
+	State   AccountState
+}
+
+func UpdateDefaultAccountState(param UpdateDefaultAccountStateParam) types.Instruction {
+	data, err := bincode.SerializeData(struct {
+		Instruction                    Instruction
+		DefaultAccountStateInstruction DefaultAccountStateInstruction
+		State                          AccountState
+	}{
+		Instruction:                    InstructionExtensionDefaultAccountState,
+		DefaultAccountStateInstruction: DefaultAccountStateInstructionUpdate,
+		State:                          param.State,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	accounts := make([]types.AccountMeta, 0, 2+len(param.Signers))
+	accounts = append(accounts, types.AccountMeta{PubKey: param.Mint, IsSigner: false, IsWritable: true})
+	accounts = append(accounts, types.AccountMeta{PubKey: param.Auth, IsSigner: len(param.Signers) == 0, IsWritable: false})
+	for _, signerPubkey := range param.Signers {
+		accounts = append(accounts, types.AccountMeta{PubKey: signerPubkey, IsSigner: true, IsWritable: false})
+	}
+
+	return types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts:  accounts,
+		Data:      data,
+	}
+}
@@ -0,0 +1,67 @@
+package token2022
+
+import (
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInitializeDefaultAccountState(t *testing.T) {
+	assert.Equal(t, types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: testMint, IsSigner: false, IsWritable: true},
+		},
+		Data: []byte{28, 0, 2},
+	}, InitializeDefaultAccountState(InitializeDefaultAccountStateParam{
+		Mint:  testMint,
+		State: AccountStateFrozen,
+	}))
+}
+
+func TestUpdateDefaultAccountState(t *testing.T) {
+	tests := []struct {
+		name  string
+		param UpdateDefaultAccountStateParam
+		want  types.Instruction
+	}{
+		{
+			name:  "freeze authority",
+			param: UpdateDefaultAccountStateParam{Mint: testMint, Auth: testAuthority, State: AccountStateInitialized},
+			want: types.Instruction{
+				ProgramID: common.Token2022ProgramID,
+				Accounts: []types.AccountMeta{
+					{PubKey: testMint, IsSigner: false, IsWritable: true},
+					{PubKey: testAuthority, IsSigner: true, IsWritable: false},
+				},
+				Data: []byte{28, 1, 1},
+			},
+		},
+		{
+			name: "multisig",
+			param: UpdateDefaultAccountStateParam{
+				Mint:    testMint,
+				Auth:    testAuthority,
+				Signers: []common.PublicKey{testSigner1, testSigner2},
+				State:   AccountStateFrozen,
+			},
+			want: types.Instruction{
+				ProgramID: common.Token2022ProgramID,
+				Accounts: []types.AccountMeta{
+					{PubKey: testMint, IsSigner: false, IsWritable: true},
+					{PubKey: testAuthority, IsSigner: false, IsWritable: false},
+					{PubKey: testSigner1, IsSigner: true, IsWritable: false},
+					{PubKey: testSigner2, IsSigner: true, IsWritable: false},
+				},
+				Data: []byte{28, 1, 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, UpdateDefaultAccountState(tt.param))
+		})
+	}
+}
@@ -5,4 +5,139 @@ import "errors"
 var (
 	ErrInvalidAccountOwner    = errors.New("invalid account owner")
 	ErrInvalidAccountDataSize = errors.New("invalid account data size")
+	ErrUnknownExtensionSize   = errors.New("extension has no fixed size")
+
+	ErrExtraAccountMetasNotFound  = errors.New("extra account metas not found")
+	ErrInvalidExtraAccountMeta    = errors.New("invalid extra account meta")
+	ErrExtraAccountMetaResolution = errors.New("failed to resolve extra account meta")
 )
+
+// custom errors of the token-2022 program
+var (
+	ErrNotRentExempt                                    = errors.New("lamport balance below rent-exempt threshold")
+	ErrInsufficientFunds                                = errors.New("insufficient funds")
+	ErrInvalidMint                                      = errors.New("invalid mint")
+	ErrMintMismatch                                     = errors.New("account not associated with this mint")
+	ErrOwnerMismatch                                    = errors.New("owner does not match")
+	ErrFixedSupply                                      = errors.New("fixed supply")
+	ErrAlreadyInUse                                     = errors.New("already in use")
+	ErrInvalidNumberOfProvidedSigners                   = errors.New("invalid number of provided signers")
+	ErrInvalidNumberOfRequiredSigners                   = errors.New("invalid number of required signers")
+	ErrUninitializedState                               = errors.New("state is uninitialized")
+	ErrNativeNotSupported                               = errors.New("instruction does not support native tokens")
+	ErrNonNativeHasBalance                              = errors.New("non-native account can only be closed if its balance is zero")
+	ErrInvalidInstruction                               = errors.New("invalid instruction")
+	ErrInvalidState                                     = errors.New("state is invalid for requested operation")
+	ErrOverflow                                         = errors.New("operation overflowed")
+	ErrAuthorityTypeNotSupported                        = errors.New("account does not support specified authority type")
+	ErrMintCannotFreeze                                 = errors.New("this token mint cannot freeze accounts")
+	ErrAccountFrozen                                    = errors.New("account is frozen")
+	ErrMintDecimalsMismatch                             = errors.New("the provided decimals value different from the mint decimals")
+	ErrNonNativeNotSupported                            = errors.New("instruction does not support non-native tokens")
+	ErrExtensionTypeMismatch                            = errors.New("extension type does not match already existing extensions")
+	ErrExtensionBaseMismatch                            = errors.New("extension does not match the base type provided")
+	ErrExtensionAlreadyInitialized                      = errors.New("extension already initialized on this account")
+	ErrConfidentialTransferAccountHasBalance            = errors.New("an account can only be closed if its confidential balance is zero")
+	ErrConfidentialTransferAccountNotApproved           = errors.New("account not approved for confidential transfers")
+	ErrConfidentialTransferDepositsAndTransfersDisabled = errors.New("account not accepting deposits or transfers")
+	ErrConfidentialTransferElGamalPubkeyMismatch        = errors.New("elgamal public key mismatch")
+	ErrConfidentialTransferBalanceMismatch              = errors.New("balance mismatch")
+	ErrMintHasSupply                                    = errors.New("mint has non-zero supply, burn all tokens before closing the mint")
+	ErrNoAuthorityExists                                = errors.New("no authority exists to perform the desired operation")
+	ErrTransferFeeExceedsMaximum                        = errors.New("transfer fee exceeds maximum of 10,000 basis points")
+	ErrMintRequiredForTransfer                          = errors.New("mint required for this account to transfer tokens, use transfer_checked or transfer_checked_with_fee")
+	ErrFeeMismatch                                      = errors.New("calculated fee does not match expected fee")
+	ErrFeeParametersMismatch                            = errors.New("fee parameters associated with confidential transfer zero-knowledge proofs do not match fee parameters in mint")
+	ErrImmutableOwner                                   = errors.New("the owner authority cannot be changed")
+	ErrAccountHasWithheldTransferFees                   = errors.New("an account can only be closed if its withheld fee balance is zero, harvest fees to the mint and try again")
+	ErrNoMemo                                           = errors.New("no memo in previous instruction; required for recipient to receive a transfer")
+	ErrNonTransferable                                  = errors.New("transfer is disabled for this mint")
+	ErrNonTransferableNeedsImmutableOwnership           = errors.New("non-transferable tokens can't be minted to an account without immutable ownership")
+	ErrMaximumPendingBalanceCounterExceeded             = errors.New("the total number of deposit and transfer instructions to an account cannot exceed the associated maximum pending balance credit counter")
+	ErrMaximumDepositAmountExceeded                     = errors.New("deposit amount exceeds maximum limit")
+	ErrCpiGuardSettingsLocked                           = errors.New("cpi guard cannot be enabled or disabled in cpi")
+	ErrCpiGuardTransferBlocked                          = errors.New("cpi guard is enabled, and a program attempted to transfer user funds via cpi without using a delegate")
+	ErrCpiGuardBurnBlocked                              = errors.New("cpi guard is enabled, and a program attempted to burn user funds via cpi without using a delegate")
+	ErrCpiGuardCloseAccountBlocked                      = errors.New("cpi guard is enabled, and a program attempted to close an account via cpi without returning lamports to owner")
+	ErrCpiGuardApproveBlocked                           = errors.New("cpi guard is enabled, and a program attempted to approve a delegate via cpi")
+	ErrCpiGuardSetAuthorityBlocked                      = errors.New("cpi guard is enabled, and a program attempted to add or replace an authority via cpi")
+	ErrCpiGuardOwnerChangeBlocked                       = errors.New("account ownership cannot be changed while cpi guard is enabled")
+	ErrExtensionNotFound                                = errors.New("extension not found in account data")
+	ErrNonConfidentialTransfersDisabled                 = errors.New("non-confidential transfers disabled")
+	ErrConfidentialTransferFeeAccountHasWithheldFee     = errors.New("an account can only be closed if the confidential withheld fee is zero")
+	ErrInvalidExtensionCombination                      = errors.New("a mint or an account is initialized to an invalid combination of extensions")
+	ErrInvalidLengthForAlloc                            = errors.New("extension allocation with overwrite must use the same length")
+	ErrAccountDecryption                                = errors.New("failed to decrypt a confidential transfer account")
+	ErrProofGeneration                                  = errors.New("failed to generate a zero-knowledge proof needed for a token instruction")
+	ErrInvalidProofInstructionOffset                    = errors.New("an invalid proof instruction offset was provided")
+	ErrHarvestToMintDisabled                            = errors.New("harvest of withheld tokens to mint is disabled")
+	ErrSplitProofContextStateAccountsNotSupported       = errors.New("split proof context state accounts not supported for instruction")
+	ErrNotEnoughProofContextStateAccounts               = errors.New("not enough proof context state accounts provided")
+	ErrMalformedCiphertext                              = errors.New("ciphertext is malformed")
+	ErrCiphertextArithmeticFailed                       = errors.New("ciphertext arithmetic failed")
+)
+
+// CustomErrors are the custom errors of the token-2022 program, indexed by error code
+var CustomErrors = []error{
+	ErrNotRentExempt,
+	ErrInsufficientFunds,
+	ErrInvalidMint,
+	ErrMintMismatch,
+	ErrOwnerMismatch,
+	ErrFixedSupply,
+	ErrAlreadyInUse,
+	ErrInvalidNumberOfProvidedSigners,
+	ErrInvalidNumberOfRequiredSigners,
+	ErrUninitializedState,
+	ErrNativeNotSupported,
+	ErrNonNativeHasBalance,
+	ErrInvalidInstruction,
+	ErrInvalidState,
+	ErrOverflow,
+	ErrAuthorityTypeNotSupported,
+	ErrMintCannotFreeze,
+	ErrAccountFrozen,
+	ErrMintDecimalsMismatch,
+	ErrNonNativeNotSupported,
+	ErrExtensionTypeMismatch,
+	ErrExtensionBaseMismatch,
+	ErrExtensionAlreadyInitialized,
+	ErrConfidentialTransferAccountHasBalance,
+	ErrConfidentialTransferAccountNotApproved,
+	ErrConfidentialTransferDepositsAndTransfersDisabled,
+	ErrConfidentialTransferElGamalPubkeyMismatch,
+	ErrConfidentialTransferBalanceMismatch,
+	ErrMintHasSupply,
+	ErrNoAuthorityExists,
+	ErrTransferFeeExceedsMaximum,
+	ErrMintRequiredForTransfer,
+	ErrFeeMismatch,
+	ErrFeeParametersMismatch,
+	ErrImmutableOwner,
+	ErrAccountHasWithheldTransferFees,
+	ErrNoMemo,
+	ErrNonTransferable,
+	ErrNonTransferableNeedsImmutableOwnership,
+	ErrMaximumPendingBalanceCounterExceeded,
+	ErrMaximumDepositAmountExceeded,
+	ErrCpiGuardSettingsLocked,
+	ErrCpiGuardTransferBlocked,
+	ErrCpiGuardBurnBlocked,
+	ErrCpiGuardCloseAccountBlocked,
+	ErrCpiGuardApproveBlocked,
+	ErrCpiGuardSetAuthorityBlocked,
+	ErrCpiGuardOwnerChangeBlocked,
+	ErrExtensionNotFound,
+	ErrNonConfidentialTransfersDisabled,
+	ErrConfidentialTransferFeeAccountHasWithheldFee,
+	ErrInvalidExtensionCombination,
+	ErrInvalidLengthForAlloc,
+	ErrAccountDecryption,
+	ErrProofGeneration,
+	ErrInvalidProofInstructionOffset,
+	ErrHarvestToMintDisabled,
+	ErrSplitProofContextStateAccountsNotSupported,
+	ErrNotEnoughProofContextStateAccounts,
+	ErrMalformedCiphertext,
+	ErrCiphertextArithmeticFailed,
+}
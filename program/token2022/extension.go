@@ -0,0 +1,313 @@
+package token2022
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/labyla/solana-go-sdk/common"
+)
+
+// MintSize is the length of a mint without extensions
+const MintSize = 82
+
+// AccountType returns the type of account the extension belongs to, AccountTypeUninitialized if it is unknown
+func (t ExtensionType) AccountType() AccountType {
+	switch t {
+	case ExtensionTypeTransferFeeConfig,
+		ExtensionTypeMintCloseAuthority,
+		ExtensionTypeConfidentialTransferMint,
+		ExtensionTypeDefaultAccountState,
+		ExtensionTypeNonTransferable,
+		ExtensionTypeInterestBearingConfig,
+		ExtensionTypePermanentDelegate,
+		ExtensionTypeTransferHook,
+		ExtensionTypeConfidentialTransferFeeConfig,
+		ExtensionTypeMetadataPointer,
+		ExtensionTypeTokenMetadata,
+		ExtensionTypeGroupPointer,
+		ExtensionTypeTokenGroup,
+		ExtensionTypeGroupMemberPointer,
+		ExtensionTypeTokenGroupMember,
+		ExtensionTypeConfidentialMintBurn,
+		ExtensionTypeScaledUiAmount,
+		ExtensionTypePausable:
+		return AccountTypeMint
+	case ExtensionTypeTransferFeeAmount,
+		ExtensionTypeConfidentialTransferAccount,
+		ExtensionTypeImmutableOwner,
+		ExtensionTypeMemoTransfer,
+		ExtensionTypeNonTransferableAccount,
+		ExtensionTypeCpiGuard,
+		ExtensionTypeTransferHookAccount,
+		ExtensionTypeConfidentialTransferFeeAmount,
+		ExtensionTypePausableAccount:
+		return AccountTypeAccount
+	}
+	return AccountTypeUninitialized
+}
+
+// GetMintLen returns the length of a mint with the extensions, the account type and the TLV headers included.
+// TokenMetadata has no fixed size, the program reallocs the mint for it, see GetTokenMetadataRealloc.
+func GetMintLen(extensionTypes []ExtensionType) (int, error) {
+	return getAccountLen(MintSize, AccountTypeMint, extensionTypes)
+}
+
+// GetAccountLen returns the length of a token account with the extensions, the account type and the TLV headers included
+func GetAccountLen(extensionTypes []ExtensionType) (int, error) {
+	return getAccountLen(BaseAccountLength, AccountTypeAccount, extensionTypes)
+}
+
+// GetRequiredAccountExtensions returns the extensions every token account of a mint with the extensions has
+func GetRequiredAccountExtensions(mintExtensionTypes []ExtensionType) []ExtensionType {
+	var accountExtensionTypes []ExtensionType
+	for _, t := range mintExtensionTypes {
+		switch t {
+		case ExtensionTypeTransferFeeConfig:
+			accountExtensionTypes = append(accountExtensionTypes, ExtensionTypeTransferFeeAmount)
+		case ExtensionTypeNonTransferable:
+			accountExtensionTypes = append(accountExtensionTypes, ExtensionTypeNonTransferableAccount)
+		case ExtensionTypeTransferHook:
+			accountExtensionTypes = append(accountExtensionTypes, ExtensionTypeTransferHookAccount)
+		case ExtensionTypeConfidentialTransferFeeConfig:
+			accountExtensionTypes = append(accountExtensionTypes, ExtensionTypeConfidentialTransferFeeAmount)
+		case ExtensionTypePausable:
+			accountExtensionTypes = append(accountExtensionTypes, ExtensionTypePausableAccount)
+		}
+	}
+	return accountExtensionTypes
+}
+
+func getAccountLen(baseLen int, accountType AccountType, extensionTypes []ExtensionType) (int, error) {
+	if len(extensionTypes) == 0 {
+		return baseLen, nil
+	}
+
+	n := BaseAccountLength + 1
+	seen := map[ExtensionType]bool{}
+	for _, t := range extensionTypes {
+		if seen[t] {
+			continue
+		}
+		seen[t] = true
+
+		if t.AccountType() != accountType {
+			return 0, fmt.Errorf("%w: %v", ErrExtensionBaseMismatch, t)
+		}
+		size, ok := extensionSizes[t]
+		if !ok {
+			return 0, fmt.Errorf("%w: %v", ErrUnknownExtensionSize, t)
+		}
+		n += 4 + size
+	}
+	return adjustLenForMultisig(n), nil
+}
+
+// adjustLenForMultisig keeps an account with extensions from having the length of a multisig,
+// the program adds an empty type header to tell them apart
+func adjustLenForMultisig(n int) int {
+	if n == multisigSize {
+		return n + 2
+	}
+	return n
+}
+
+// SerializeMintWithExtensions returns the data of a mint with the extensions, the inverse of ParseMintExtensions.
+// the 82 bytes mint is padded to the length of a token account before the account type, like the program does.
+func SerializeMintWithExtensions(mint []byte, ext MintExtensions) ([]byte, error) {
+	if len(mint) != MintSize {
+		return nil, fmt.Errorf("%w, expected: %v, got: %v", ErrInvalidAccountDataSize, MintSize, len(mint))
+	}
+	tlv, err := ext.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return withExtensions(mint, AccountTypeMint, tlv), nil
+}
+
+// SerializeAccountWithExtensions returns the data of a token account with the extensions, the inverse of ParseAccountExtensions
+func SerializeAccountWithExtensions(account []byte, ext AccountExtensions) ([]byte, error) {
+	if len(account) != BaseAccountLength {
+		return nil, fmt.Errorf("%w, expected: %v, got: %v", ErrInvalidAccountDataSize, BaseAccountLength, len(account))
+	}
+	tlv, err := ext.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	return withExtensions(account, AccountTypeAccount, tlv), nil
+}
+
+func withExtensions(base []byte, accountType AccountType, tlv []byte) []byte {
+	if len(tlv) == 0 {
+		return append([]byte{}, base...)
+	}
+	data := make([]byte, BaseAccountLength, BaseAccountLength+1+len(tlv)+2)
+	copy(data, base)
+	data = append(data, byte(accountType))
+	data = append(data, tlv...)
+	return append(data, make([]byte, adjustLenForMultisig(len(data))-len(data))...)
+}
+
+// Serialize encodes the extensions as TLV entries in the order of their types
+func (e MintExtensions) Serialize() ([]byte, error) {
+	entries := map[ExtensionType][]byte{}
+	if e.MintCloseAuthority != nil {
+		entries[ExtensionTypeMintCloseAuthority] = e.MintCloseAuthority.CloseAuthority.Bytes()
+	}
+	if e.TransferFeeConfig != nil {
+		v := e.TransferFeeConfig
+		b := make([]byte, 0, TransferFeeConfigSize)
+		b = append(b, v.TransferFeeConfigAuthority.Bytes()...)
+		b = append(b, v.WithdrawWithheldAuthority.Bytes()...)
+		b = binary.LittleEndian.AppendUint64(b, v.WithheldAmount)
+		for _, fee := range []TransferFee{v.OlderTransferFee, v.NewerTransferFee} {
+			b = binary.LittleEndian.AppendUint64(b, fee.Epoch)
+			b = binary.LittleEndian.AppendUint64(b, fee.MaximumFee)
+			b = binary.LittleEndian.AppendUint16(b, fee.TransferFeeBasisPoints)
+		}
+		entries[ExtensionTypeTransferFeeConfig] = b
+	}
+	if e.DefaultAccountState != nil {
+		entries[ExtensionTypeDefaultAccountState] = []byte{e.DefaultAccountState.State}
+	}
+	if e.NonTransferable != nil {
+		entries[ExtensionTypeNonTransferable] = []byte{}
+	}
+	if e.InterestBearingConfig != nil {
+		v := e.InterestBearingConfig
+		b := make([]byte, 0, InterestBearingConfigSize)
+		b = append(b, v.RateAuthority.Bytes()...)
+		b = binary.LittleEndian.AppendUint64(b, uint64(v.InitializationTimestamp))
+		b = binary.LittleEndian.AppendUint16(b, uint16(v.PreUpdateAverageRate))
+		b = binary.LittleEndian.AppendUint64(b, uint64(v.LastUpdateTimestamp))
+		b = binary.LittleEndian.AppendUint16(b, uint16(v.CurrentRate))
+		entries[ExtensionTypeInterestBearingConfig] = b
+	}
+	if e.PermanentDelegate != nil {
+		entries[ExtensionTypePermanentDelegate] = e.PermanentDelegate.Delegate.Bytes()
+	}
+	if e.TransferHook != nil {
+		entries[ExtensionTypeTransferHook] = pubkeys(e.TransferHook.Authority, e.TransferHook.ProgramId)
+	}
+	if e.MetadataPointer != nil {
+		entries[ExtensionTypeMetadataPointer] = pubkeys(e.MetadataPointer.Authority, e.MetadataPointer.MetadataAddress)
+	}
+	if e.TokenMetadata != nil {
+		entries[ExtensionTypeTokenMetadata] = e.TokenMetadata.serialize()
+	}
+	if e.GroupPointer != nil {
+		entries[ExtensionTypeGroupPointer] = pubkeys(e.GroupPointer.Authority, e.GroupPointer.GroupAddress)
+	}
+	if e.TokenGroup != nil {
+		b := pubkeys(e.TokenGroup.UpdateAuthority, e.TokenGroup.Mint)
+		b = binary.LittleEndian.AppendUint64(b, e.TokenGroup.Size)
+		b = binary.LittleEndian.AppendUint64(b, e.TokenGroup.MaxSize)
+		entries[ExtensionTypeTokenGroup] = b
+	}
+	if e.GroupMemberPointer != nil {
+		entries[ExtensionTypeGroupMemberPointer] = pubkeys(e.GroupMemberPointer.Authority, e.GroupMemberPointer.MemberAddress)
+	}
+	if e.TokenGroupMember != nil {
+		b := pubkeys(e.TokenGroupMember.Mint, e.TokenGroupMember.Group)
+		b = binary.LittleEndian.AppendUint64(b, e.TokenGroupMember.MemberNumber)
+		entries[ExtensionTypeTokenGroupMember] = b
+	}
+	if e.ScaledUiAmountConfig != nil {
+		v := e.ScaledUiAmountConfig
+		b := make([]byte, 0, ScaledUiAmountConfigSize)
+		b = append(b, v.Authority.Bytes()...)
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(v.Multiplier))
+		b = binary.LittleEndian.AppendUint64(b, uint64(v.NewMultiplierEffectiveTimestamp))
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(v.NewMultiplier))
+		entries[ExtensionTypeScaledUiAmount] = b
+	}
+	if e.PausableConfig != nil {
+		entries[ExtensionTypePausable] = append(e.PausableConfig.Authority.Bytes(), boolByte(e.PausableConfig.Paused))
+	}
+	return serializeTLVExtensions(entries)
+}
+
+// Serialize encodes the extensions as TLV entries in the order of their types
+func (e AccountExtensions) Serialize() ([]byte, error) {
+	entries := map[ExtensionType][]byte{}
+	if e.TransferFeeAmount != nil {
+		entries[ExtensionTypeTransferFeeAmount] = binary.LittleEndian.AppendUint64(nil, e.TransferFeeAmount.WithheldAmount)
+	}
+	if e.ImmutableOwner != nil {
+		entries[ExtensionTypeImmutableOwner] = []byte{}
+	}
+	if e.MemoTransfer != nil {
+		entries[ExtensionTypeMemoTransfer] = []byte{boolByte(e.MemoTransfer.RequireIncomingTransferMemos)}
+	}
+	if e.NonTransferableAccount != nil {
+		entries[ExtensionTypeNonTransferableAccount] = []byte{}
+	}
+	if e.CpiGuard != nil {
+		entries[ExtensionTypeCpiGuard] = []byte{boolByte(e.CpiGuard.LockCpi)}
+	}
+	if e.TransferHookAccount != nil {
+		entries[ExtensionTypeTransferHookAccount] = []byte{boolByte(e.TransferHookAccount.Transferring)}
+	}
+	if e.PausableAccount != nil {
+		entries[ExtensionTypePausableAccount] = []byte{}
+	}
+	return serializeTLVExtensions(entries)
+}
+
+// serializeTLVExtensions is the inverse of parseTLVExtensions
+func serializeTLVExtensions(entries map[ExtensionType][]byte) ([]byte, error) {
+	extTypes := make([]ExtensionType, 0, len(entries))
+	n := 0
+	for extType, value := range entries {
+		if len(value) > math.MaxUint16 {
+			return nil, fmt.Errorf("%w: %v is %v bytes", ErrInvalidAccountDataSize, extType, len(value))
+		}
+		extTypes = append(extTypes, extType)
+		n += 4 + len(value)
+	}
+	sort.Slice(extTypes, func(i, j int) bool { return extTypes[i] < extTypes[j] })
+
+	data := make([]byte, 0, n)
+	for _, extType := range extTypes {
+		data = binary.LittleEndian.AppendUint16(data, uint16(extType))
+		data = binary.LittleEndian.AppendUint16(data, uint16(len(entries[extType])))
+		data = append(data, entries[extType]...)
+	}
+	return data, nil
+}
+
+func (m TokenMetadata) serialize() []byte {
+	b := make([]byte, 0, m.Size())
+	b = append(b, m.UpdateAuthority.Bytes()...)
+	b = append(b, m.Mint.Bytes()...)
+	b = appendBorshString(b, m.Name)
+	b = appendBorshString(b, m.Symbol)
+	b = appendBorshString(b, m.Uri)
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(m.AdditionalMetadata)))
+	for _, kv := range m.AdditionalMetadata {
+		b = appendBorshString(b, kv.Key)
+		b = appendBorshString(b, kv.Value)
+	}
+	return b
+}
+
+func appendBorshString(b []byte, s string) []byte {
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+func pubkeys(keys ...common.PublicKey) []byte {
+	b := make([]byte, 0, len(keys)*common.PublicKeyLength)
+	for _, key := range keys {
+		b = append(b, key.Bytes()...)
+	}
+	return b
+}
+
+func boolByte(v bool) byte {
+	if v {
+		return 1
+	}
+	return 0
+}
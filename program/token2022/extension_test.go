@@ -0,0 +1,202 @@
+package token2022
+
+import (
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMintLen(t *testing.T) {
+	tests := []struct {
+		name           string
+		extensionTypes []ExtensionType
+		want           int
+		err            error
+	}{
+		{name: "no extension", want: MintSize},
+		{name: "metadata pointer", extensionTypes: []ExtensionType{ExtensionTypeMetadataPointer}, want: 234},
+		{name: "transfer fee", extensionTypes: []ExtensionType{ExtensionTypeTransferFeeConfig}, want: 278},
+		{name: "empty extension", extensionTypes: []ExtensionType{ExtensionTypeNonTransferable}, want: 170},
+		{
+			name:           "duplicates are counted once",
+			extensionTypes: []ExtensionType{ExtensionTypeMintCloseAuthority, ExtensionTypeMintCloseAuthority},
+			want:           202,
+		},
+		{
+			name: "not the length of a multisig",
+			extensionTypes: []ExtensionType{
+				ExtensionTypeTransferFeeConfig,
+				ExtensionTypeMetadataPointer,
+				ExtensionTypeDefaultAccountState,
+				ExtensionTypeNonTransferable,
+			},
+			want: 357,
+		},
+		{name: "variable length", extensionTypes: []ExtensionType{ExtensionTypeTokenMetadata}, err: ErrUnknownExtensionSize},
+		{name: "account extension", extensionTypes: []ExtensionType{ExtensionTypeImmutableOwner}, err: ErrExtensionBaseMismatch},
+		{name: "unknown", extensionTypes: []ExtensionType{100}, err: ErrExtensionBaseMismatch},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetMintLen(tt.extensionTypes)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestGetAccountLen(t *testing.T) {
+	n, err := GetAccountLen(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, BaseAccountLength, n)
+
+	n, err = GetAccountLen([]ExtensionType{ExtensionTypeImmutableOwner})
+	assert.Nil(t, err)
+	assert.Equal(t, 170, n)
+
+	// the account of a mint with a transfer fee and a transfer hook
+	n, err = GetAccountLen(append(
+		GetRequiredAccountExtensions([]ExtensionType{ExtensionTypeTransferFeeConfig, ExtensionTypeTransferHook, ExtensionTypeMetadataPointer}),
+		ExtensionTypeImmutableOwner,
+	))
+	assert.Nil(t, err)
+	assert.Equal(t, 166+12+5+4, n)
+
+	_, err = GetAccountLen([]ExtensionType{ExtensionTypeTransferFeeConfig})
+	assert.ErrorIs(t, err, ErrExtensionBaseMismatch)
+}
+
+func TestSerializeMintWithExtensions(t *testing.T) {
+	authority := common.PublicKeyFromString("9aE476sH92Vz7DMPyq5WLPkrKWivxeuTKEFKd2sZZcde")
+	mint := common.PublicKeyFromString("FtvD2ymcAFh59DGGmJkANyJzEpLDR1GLgqDrUxfe2dPm")
+	extensions := MintExtensions{
+		MintCloseAuthority: &MintCloseAuthority{CloseAuthority: authority},
+		TransferFeeConfig: &TransferFeeConfig{
+			TransferFeeConfigAuthority: authority,
+			WithheldAmount:             7,
+			OlderTransferFee:           TransferFee{Epoch: 1, MaximumFee: 2, TransferFeeBasisPoints: 3},
+			NewerTransferFee:           TransferFee{Epoch: 4, MaximumFee: 5, TransferFeeBasisPoints: 6},
+		},
+		DefaultAccountState: &DefaultAccountState{State: 2},
+		NonTransferable:     &NonTransferable{},
+		InterestBearingConfig: &InterestBearingConfig{
+			RateAuthority:           authority,
+			InitializationTimestamp: 1700000000,
+			PreUpdateAverageRate:    -5,
+			LastUpdateTimestamp:     1700000001,
+			CurrentRate:             10,
+		},
+		PermanentDelegate:  &PermanentDelegate{Delegate: authority},
+		TransferHook:       &TransferHook{Authority: authority, ProgramId: common.MemoProgramID},
+		MetadataPointer:    &MetadataPointer{Authority: authority, MetadataAddress: mint},
+		GroupPointer:       &GroupPointer{Authority: authority, GroupAddress: mint},
+		TokenGroup:         &TokenGroup{UpdateAuthority: authority, Mint: mint, Size: 1, MaxSize: 1 << 40},
+		GroupMemberPointer: &GroupMemberPointer{Authority: authority, MemberAddress: mint},
+		TokenGroupMember:   &TokenGroupMember{Mint: mint, Group: mint, MemberNumber: 1},
+		ScaledUiAmountConfig: &ScaledUiAmountConfig{
+			Authority:                       authority,
+			Multiplier:                      1.5,
+			NewMultiplierEffectiveTimestamp: 1700000000,
+			NewMultiplier:                   2,
+		},
+		PausableConfig: &PausableConfig{Authority: authority, Paused: true},
+	}
+
+	base := make([]byte, MintSize)
+	base[0] = 1
+	data, err := SerializeMintWithExtensions(base, extensions)
+	require.Nil(t, err)
+	assert.Equal(t, base, data[:MintSize])
+	assert.Equal(t, make([]byte, BaseAccountLength-MintSize), data[MintSize:BaseAccountLength])
+
+	types, err := GetExtensionTypes(data)
+	require.Nil(t, err)
+	n, err := GetMintLen(types)
+	require.Nil(t, err)
+	assert.Equal(t, n, len(data))
+
+	parsed, err := ParseMintExtensions(data)
+	require.Nil(t, err)
+	assert.Equal(t, &extensions, parsed)
+
+	// with the variable length metadata
+	extensions.TokenMetadata = &TokenMetadata{
+		UpdateAuthority: authority,
+		Mint:            mint,
+		Name:            "Coin",
+		Symbol:          "CON",
+		Uri:             "https://example.com",
+		AdditionalMetadata: []struct {
+			Key   string
+			Value string
+		}{{Key: "a", Value: "1"}},
+	}
+	data, err = SerializeMintWithExtensions(base, extensions)
+	require.Nil(t, err)
+	assert.Equal(t, n+4+extensions.TokenMetadata.Size(), len(data))
+	parsed, err = ParseMintExtensions(data)
+	require.Nil(t, err)
+	assert.Equal(t, &extensions, parsed)
+
+	// a mint without extensions is left as is
+	data, err = SerializeMintWithExtensions(base, MintExtensions{})
+	require.Nil(t, err)
+	assert.Equal(t, base, data)
+
+	_, err = SerializeMintWithExtensions(make([]byte, BaseAccountLength), extensions)
+	assert.ErrorIs(t, err, ErrInvalidAccountDataSize)
+}
+
+func TestSerializeMintWithExtensions_MultisigLength(t *testing.T) {
+	extensions := MintExtensions{
+		TransferFeeConfig:   &TransferFeeConfig{},
+		MetadataPointer:     &MetadataPointer{},
+		DefaultAccountState: &DefaultAccountState{},
+		NonTransferable:     &NonTransferable{},
+	}
+	data, err := SerializeMintWithExtensions(make([]byte, MintSize), extensions)
+	require.Nil(t, err)
+	assert.Equal(t, 357, len(data))
+	assert.Equal(t, []byte{0, 0}, data[355:])
+
+	parsed, err := ParseMintExtensions(data)
+	require.Nil(t, err)
+	assert.Equal(t, &extensions, parsed)
+}
+
+func TestSerializeAccountWithExtensions(t *testing.T) {
+	extensions := AccountExtensions{
+		TransferFeeAmount:      &TransferFeeAmount{WithheldAmount: 100},
+		ImmutableOwner:         &ImmutableOwner{},
+		MemoTransfer:           &MemoTransfer{RequireIncomingTransferMemos: true},
+		NonTransferableAccount: &NonTransferableAccount{},
+		CpiGuard:               &CpiGuard{LockCpi: true},
+		TransferHookAccount:    &TransferHookAccount{},
+		PausableAccount:        &PausableAccount{},
+	}
+	data, err := SerializeAccountWithExtensions(make([]byte, BaseAccountLength), extensions)
+	require.Nil(t, err)
+
+	assert.Equal(t, []byte{
+		2,
+		2, 0, 8, 0, 100, 0, 0, 0, 0, 0, 0, 0,
+		7, 0, 0, 0,
+		8, 0, 1, 0, 1,
+		11, 0, 1, 0, 1,
+		13, 0, 0, 0,
+		15, 0, 1, 0, 0,
+		27, 0, 0, 0,
+	}, data[BaseAccountLength:])
+
+	types, err := GetExtensionTypes(data)
+	require.Nil(t, err)
+	n, err := GetAccountLen(types)
+	require.Nil(t, err)
+	assert.Equal(t, n, len(data))
+
+	parsed, err := ParseAccountExtensions(data)
+	require.Nil(t, err)
+	assert.Equal(t, &extensions, parsed)
+}
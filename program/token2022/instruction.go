@@ -1,6 +1,8 @@
 package token2022
 
 import (
+	"encoding/binary"
+
 	"github.com/labyla/solana-go-sdk/common"
 	"github.com/labyla/solana-go-sdk/pkg/bincode"
 	"github.com/labyla/solana-go-sdk/types"
@@ -31,21 +33,30 @@ const (
 	InstructionInitializeMultisig2
 	InstructionInitializeMint2
 	// Token-2022 specific instructions start here
+	InstructionGetAccountDataSize
+	InstructionExtensionImmutableOwner
+	InstructionAmountToUiAmount
+	InstructionUiAmountToAmount
 	InstructionInitializeMintCloseAuthority
 	InstructionExtensionTransferFeeConfig
 	InstructionExtensionConfidentialTransferMint
-	InstructionExtensionConfidentialTransferFeeConfig
 	InstructionExtensionDefaultAccountState
-	InstructionExtensionImmutableOwner
+	InstructionReallocate
 	InstructionExtensionMemoTransfer
+	InstructionCreateNativeMint
 	InstructionExtensionNonTransferable
 	InstructionExtensionInterestBearingConfig
 	InstructionExtensionCpiGuard
 	InstructionExtensionPermanentDelegate
 	InstructionExtensionTransferHook
+	InstructionExtensionConfidentialTransferFeeConfig
+	InstructionWithdrawExcessLamports
 	InstructionExtensionMetadataPointer
 	InstructionExtensionGroupPointer
 	InstructionExtensionGroupMemberPointer
+	InstructionExtensionConfidentialMintBurn
+	InstructionExtensionScaledUiAmount
+	InstructionExtensionPausable
 	// Add more as needed for Token-2022 extensions
 )
 
@@ -815,28 +826,165 @@ func InitializeMint2(param InitializeMint2Param) types.Instruction {
 	}
 }
 
-// Add Token-2022 specific functions as needed, e.g., for extensions
-// For example:
-// type InitializeImmutableOwnerParam struct {
-// 	Account common.PublicKey
-// }
-//
-// func InitializeImmutableOwner(param InitializeImmutableOwnerParam) types.Instruction {
-// 	data, err := bincode.SerializeData(struct {
-// 		Instruction Instruction
-// 	}{
-// 		Instruction: InstructionExtensionImmutableOwner,
-// 	})
-// 	if err != nil {
-// 		panic(err)
-// 	}
-//
-// 	return types.Instruction{
-// 		ProgramID: common.Token2022ProgramID,
-// 		Accounts: []types.AccountMeta{
-// 			{PubKey: param.Account, IsSigner: false, IsWritable: true},
-// 		},
-// 		Data: data,
-// 	}
-// }
-// Repeat for other extensions...
+type InitializeImmutableOwnerParam struct {
+	Account common.PublicKey
+}
+
+// InitializeImmutableOwner makes the owner of a token account unchangeable, it must come before InitializeAccount
+func InitializeImmutableOwner(param InitializeImmutableOwnerParam) types.Instruction {
+	data, err := bincode.SerializeData(struct {
+		Instruction Instruction
+	}{
+		Instruction: InstructionExtensionImmutableOwner,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: param.Account, IsSigner: false, IsWritable: true},
+		},
+		Data: data,
+	}
+}
+
+type InitializeMintCloseAuthorityParam struct {
+	Mint           common.PublicKey
+	CloseAuthority *common.PublicKey
+}
+
+// InitializeMintCloseAuthority lets the close authority close the mint once its supply is 0,
+// it must come before InitializeMint
+func InitializeMintCloseAuthority(param InitializeMintCloseAuthorityParam) types.Instruction {
+	data := appendPubkeyOption([]byte{byte(InstructionInitializeMintCloseAuthority)}, param.CloseAuthority)
+
+	return types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: param.Mint, IsSigner: false, IsWritable: true},
+		},
+		Data: data,
+	}
+}
+
+type ReallocateParam struct {
+	Account        common.PublicKey
+	Payer          common.PublicKey
+	Auth           common.PublicKey
+	Signers        []common.PublicKey
+	ExtensionTypes []ExtensionType
+}
+
+// Reallocate grows a token account to fit the account extensions, the payer funds the rent
+func Reallocate(param ReallocateParam) types.Instruction {
+	// the extension types are the rest of the data, without a length
+	data := make([]byte, 0, 1+2*len(param.ExtensionTypes))
+	data = append(data, byte(InstructionReallocate))
+	for _, extensionType := range param.ExtensionTypes {
+		data = binary.LittleEndian.AppendUint16(data, uint16(extensionType))
+	}
+
+	accounts := make([]types.AccountMeta, 0, 4+len(param.Signers))
+	accounts = append(accounts, types.AccountMeta{PubKey: param.Account, IsSigner: false, IsWritable: true})
+	accounts = append(accounts, types.AccountMeta{PubKey: param.Payer, IsSigner: true, IsWritable: true})
+	accounts = append(accounts, types.AccountMeta{PubKey: common.SystemProgramID, IsSigner: false, IsWritable: false})
+	accounts = append(accounts, types.AccountMeta{PubKey: param.Auth, IsSigner: len(param.Signers) == 0, IsWritable: false})
+	for _, signerPubkey := range param.Signers {
+		accounts = append(accounts, types.AccountMeta{PubKey: signerPubkey, IsSigner: true, IsWritable: false})
+	}
+
+	return types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts:  accounts,
+		Data:      data,
+	}
+}
+
+type InitializeNonTransferableMintParam struct {
+	Mint common.PublicKey
+}
+
+// InitializeNonTransferableMint makes the tokens of a mint non transferable, it must come before InitializeMint
+func InitializeNonTransferableMint(param InitializeNonTransferableMintParam) types.Instruction {
+	data, err := bincode.SerializeData(struct {
+		Instruction Instruction
+	}{
+		Instruction: InstructionExtensionNonTransferable,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: param.Mint, IsSigner: false, IsWritable: true},
+		},
+		Data: data,
+	}
+}
+
+type InitializePermanentDelegateParam struct {
+	Mint     common.PublicKey
+	Delegate common.PublicKey
+}
+
+// InitializePermanentDelegate lets the delegate transfer or burn any token of the mint,
+// it must come before InitializeMint
+func InitializePermanentDelegate(param InitializePermanentDelegateParam) types.Instruction {
+	data, err := bincode.SerializeData(struct {
+		Instruction Instruction
+		Delegate    common.PublicKey
+	}{
+		Instruction: InstructionExtensionPermanentDelegate,
+		Delegate:    param.Delegate,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: param.Mint, IsSigner: false, IsWritable: true},
+		},
+		Data: data,
+	}
+}
+
+type WithdrawExcessLamportsParam struct {
+	// From is a mint, a token account or a multisig
+	From    common.PublicKey
+	To      common.PublicKey
+	Auth    common.PublicKey
+	Signers []common.PublicKey
+}
+
+// WithdrawExcessLamports moves the lamports above the rent exempt minimum out of an account,
+// e.g. the SOL sent to a mint by mistake
+func WithdrawExcessLamports(param WithdrawExcessLamportsParam) types.Instruction {
+	data, err := bincode.SerializeData(struct {
+		Instruction Instruction
+	}{
+		Instruction: InstructionWithdrawExcessLamports,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	accounts := make([]types.AccountMeta, 0, 3+len(param.Signers))
+	accounts = append(accounts, types.AccountMeta{PubKey: param.From, IsSigner: false, IsWritable: true})
+	accounts = append(accounts, types.AccountMeta{PubKey: param.To, IsSigner: false, IsWritable: true})
+	accounts = append(accounts, types.AccountMeta{PubKey: param.Auth, IsSigner: len(param.Signers) == 0, IsWritable: false})
+	for _, signerPubkey := range param.Signers {
+		accounts = append(accounts, types.AccountMeta{PubKey: signerPubkey, IsSigner: true, IsWritable: false})
+	}
+
+	return types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts:  accounts,
+		Data:      data,
+	}
+}
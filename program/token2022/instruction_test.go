@@ -0,0 +1,178 @@
+package token2022
+
+import (
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/pkg/pointer"
+	"github.com/labyla/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInitializeImmutableOwner(t *testing.T) {
+	assert.Equal(t, types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: testAccount1, IsSigner: false, IsWritable: true},
+		},
+		Data: []byte{22},
+	}, InitializeImmutableOwner(InitializeImmutableOwnerParam{Account: testAccount1}))
+}
+
+func TestInitializeMintCloseAuthority(t *testing.T) {
+	tests := []struct {
+		name  string
+		param InitializeMintCloseAuthorityParam
+		want  types.Instruction
+	}{
+		{
+			name:  "close authority",
+			param: InitializeMintCloseAuthorityParam{Mint: testMint, CloseAuthority: pointer.Get(testAuthority)},
+			want: types.Instruction{
+				ProgramID: common.Token2022ProgramID,
+				Accounts: []types.AccountMeta{
+					{PubKey: testMint, IsSigner: false, IsWritable: true},
+				},
+				Data: concat([]byte{25, 1}, testAuthority.Bytes()),
+			},
+		},
+		{
+			name:  "no close authority",
+			param: InitializeMintCloseAuthorityParam{Mint: testMint},
+			want: types.Instruction{
+				ProgramID: common.Token2022ProgramID,
+				Accounts: []types.AccountMeta{
+					{PubKey: testMint, IsSigner: false, IsWritable: true},
+				},
+				Data: []byte{25, 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, InitializeMintCloseAuthority(tt.param))
+		})
+	}
+}
+
+func TestReallocate(t *testing.T) {
+	tests := []struct {
+		name  string
+		param ReallocateParam
+		want  types.Instruction
+	}{
+		{
+			name: "owner",
+			param: ReallocateParam{
+				Account:        testAccount1,
+				Payer:          testSigner1,
+				Auth:           testAuthority,
+				ExtensionTypes: []ExtensionType{ExtensionTypeMemoTransfer, ExtensionTypeCpiGuard},
+			},
+			want: types.Instruction{
+				ProgramID: common.Token2022ProgramID,
+				Accounts: []types.AccountMeta{
+					{PubKey: testAccount1, IsSigner: false, IsWritable: true},
+					{PubKey: testSigner1, IsSigner: true, IsWritable: true},
+					{PubKey: common.SystemProgramID, IsSigner: false, IsWritable: false},
+					{PubKey: testAuthority, IsSigner: true, IsWritable: false},
+				},
+				Data: []byte{29, 8, 0, 11, 0},
+			},
+		},
+		{
+			name: "multisig",
+			param: ReallocateParam{
+				Account:        testAccount1,
+				Payer:          testSigner1,
+				Auth:           testAuthority,
+				Signers:        []common.PublicKey{testSigner1, testSigner2},
+				ExtensionTypes: []ExtensionType{ExtensionTypeMemoTransfer},
+			},
+			want: types.Instruction{
+				ProgramID: common.Token2022ProgramID,
+				Accounts: []types.AccountMeta{
+					{PubKey: testAccount1, IsSigner: false, IsWritable: true},
+					{PubKey: testSigner1, IsSigner: true, IsWritable: true},
+					{PubKey: common.SystemProgramID, IsSigner: false, IsWritable: false},
+					{PubKey: testAuthority, IsSigner: false, IsWritable: false},
+					{PubKey: testSigner1, IsSigner: true, IsWritable: false},
+					{PubKey: testSigner2, IsSigner: true, IsWritable: false},
+				},
+				Data: []byte{29, 8, 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Reallocate(tt.param))
+		})
+	}
+}
+
+func TestInitializeNonTransferableMint(t *testing.T) {
+	assert.Equal(t, types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: testMint, IsSigner: false, IsWritable: true},
+		},
+		Data: []byte{32},
+	}, InitializeNonTransferableMint(InitializeNonTransferableMintParam{Mint: testMint}))
+}
+
+func TestInitializePermanentDelegate(t *testing.T) {
+	assert.Equal(t, types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: testMint, IsSigner: false, IsWritable: true},
+		},
+		Data: concat([]byte{35}, testAuthority.Bytes()),
+	}, InitializePermanentDelegate(InitializePermanentDelegateParam{Mint: testMint, Delegate: testAuthority}))
+}
+
+func TestWithdrawExcessLamports(t *testing.T) {
+	tests := []struct {
+		name  string
+		param WithdrawExcessLamportsParam
+		want  types.Instruction
+	}{
+		{
+			name:  "authority",
+			param: WithdrawExcessLamportsParam{From: testMint, To: testAccount1, Auth: testAuthority},
+			want: types.Instruction{
+				ProgramID: common.Token2022ProgramID,
+				Accounts: []types.AccountMeta{
+					{PubKey: testMint, IsSigner: false, IsWritable: true},
+					{PubKey: testAccount1, IsSigner: false, IsWritable: true},
+					{PubKey: testAuthority, IsSigner: true, IsWritable: false},
+				},
+				Data: []byte{38},
+			},
+		},
+		{
+			name: "multisig",
+			param: WithdrawExcessLamportsParam{
+				From:    testMint,
+				To:      testAccount1,
+				Auth:    testAuthority,
+				Signers: []common.PublicKey{testSigner1, testSigner2},
+			},
+			want: types.Instruction{
+				ProgramID: common.Token2022ProgramID,
+				Accounts: []types.AccountMeta{
+					{PubKey: testMint, IsSigner: false, IsWritable: true},
+					{PubKey: testAccount1, IsSigner: false, IsWritable: true},
+					{PubKey: testAuthority, IsSigner: false, IsWritable: false},
+					{PubKey: testSigner1, IsSigner: true, IsWritable: false},
+					{PubKey: testSigner2, IsSigner: true, IsWritable: false},
+				},
+				Data: []byte{38},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, WithdrawExcessLamports(tt.param))
+		})
+	}
+}
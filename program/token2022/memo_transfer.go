@@ -0,0 +1,67 @@
+package token2022
+
+import (
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/pkg/bincode"
+	"github.com/labyla/solana-go-sdk/types"
+)
+
+// MemoTransferInstruction is the second byte of the data of an InstructionExtensionMemoTransfer
+type MemoTransferInstruction uint8
+
+const (
+	MemoTransferInstructionEnable MemoTransferInstruction = iota
+	MemoTransferInstructionDisable
+)
+
+type EnableRequiredMemoTransfersParam struct {
+	Account common.PublicKey
+	Auth    common.PublicKey
+	Signers []common.PublicKey
+}
+
+// EnableRequiredMemoTransfers makes incoming transfers to a token account fail without a memo instruction
+// right before them. the account is reallocated if it has no MemoTransfer extension.
+func EnableRequiredMemoTransfers(param EnableRequiredMemoTransfersParam) types.Instruction {
+	return memoTransfer(MemoTransferInstructionEnable, param.Account, param.Auth, param.Signers)
+}
+
+type DisableRequiredMemoTransfersParam struct {
+	Account common.PublicKey
+	Auth    common.PublicKey
+	Signers []common.PublicKey
+}
+
+func DisableRequiredMemoTransfers(param DisableRequiredMemoTransfersParam) types.Instruction {
+	return memoTransfer(MemoTransferInstructionDisable, param.Account, param.Auth, param.Signers)
+}
+
+func memoTransfer(instruction MemoTransferInstruction, account, auth common.PublicKey, signers []common.PublicKey) types.Instruction {
+	data, err := bincode.SerializeData(struct {
+		Instruction             Instruction
+		MemoTransferInstruction MemoTransferInstruction
+	}{
+		Instruction:             InstructionExtensionMemoTransfer,
+		MemoTransferInstruction: instruction,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts:  ownerAccounts(account, auth, signers),
+		Data:      data,
+	}
+}
+
+// ownerAccounts are the accounts of an instruction the owner of a token account signs
+func ownerAccounts(account, auth common.PublicKey, signers []common.PublicKey) []types.AccountMeta {
+	accounts := make([]types.AccountMeta, 0, 2+len(signers))
+	accounts = append(accounts, types.AccountMeta{PubKey: account, IsSigner: false, IsWritable: true})
+	accounts = append(accounts, types.AccountMeta{PubKey: auth, IsSigner: len(signers) == 0, IsWritable: false})
+	for _, signerPubkey := range signers {
+		accounts = append(accounts, types.AccountMeta{PubKey: signerPubkey, IsSigner: true, IsWritable: false})
+	}
+	return accounts
+}
@@ -0,0 +1,40 @@
+package token2022
+
+import (
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnableRequiredMemoTransfers(t *testing.T) {
+	assert.Equal(t, types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: testAccount1, IsSigner: false, IsWritable: true},
+			{PubKey: testAuthority, IsSigner: true, IsWritable: false},
+		},
+		Data: []byte{30, 0},
+	}, EnableRequiredMemoTransfers(EnableRequiredMemoTransfersParam{
+		Account: testAccount1,
+		Auth:    testAuthority,
+	}))
+}
+
+func TestDisableRequiredMemoTransfers(t *testing.T) {
+	assert.Equal(t, types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: testAccount1, IsSigner: false, IsWritable: true},
+			{PubKey: testAuthority, IsSigner: false, IsWritable: false},
+			{PubKey: testSigner1, IsSigner: true, IsWritable: false},
+			{PubKey: testSigner2, IsSigner: true, IsWritable: false},
+		},
+		Data: []byte{30, 1},
+	}, DisableRequiredMemoTransfers(DisableRequiredMemoTransfersParam{
+		Account: testAccount1,
+		Auth:    testAuthority,
+		Signers: []common.PublicKey{testSigner1, testSigner2},
+	}))
+}
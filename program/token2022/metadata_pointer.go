@@ -0,0 +1,91 @@
+package token2022
+
+import (
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/pkg/bincode"
+	"github.com/labyla/solana-go-sdk/types"
+)
+
+// MetadataPointerInstruction is the second byte of the data of an InstructionExtensionMetadataPointer
+type MetadataPointerInstruction uint8
+
+const (
+	MetadataPointerInstructionInitialize MetadataPointerInstruction = iota
+	MetadataPointerInstructionUpdate
+)
+
+type InitializeMetadataPointerParam struct {
+	Mint            common.PublicKey
+	Authority       *common.PublicKey
+	MetadataAddress *common.PublicKey
+}
+
+// InitializeMetadataPointer init the metadata pointer of a mint, it must come before InitializeMint.
+// point it to the mint itself to keep the metadata in the mint.
+func InitializeMetadataPointer(param InitializeMetadataPointerParam) types.Instruction {
+	data, err := bincode.SerializeData(struct {
+		Instruction                Instruction
+		MetadataPointerInstruction MetadataPointerInstruction
+		Authority                  common.PublicKey
+		MetadataAddress            common.PublicKey
+	}{
+		Instruction:                InstructionExtensionMetadataPointer,
+		MetadataPointerInstruction: MetadataPointerInstructionInitialize,
+		Authority:                  optionalNonZeroPubkey(param.Authority),
+		MetadataAddress:            optionalNonZeroPubkey(param.MetadataAddress),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: param.Mint, IsSigner: false, IsWritable: true},
+		},
+		Data: data,
+	}
+}
+
+type UpdateMetadataPointerParam struct {
+	Mint            common.PublicKey
+	Auth            common.PublicKey
+	Signers         []common.PublicKey
+	MetadataAddress *common.PublicKey
+}
+
+func UpdateMetadataPointer(param UpdateMetadataPointerParam) types.Instruction {
+	data, err := bincode.SerializeData(struct {
+		Instruction                Instruction
+		MetadataPointerInstruction MetadataPointerInstruction
+		MetadataAddress            common.PublicKey
+	}{
+		Instruction:                InstructionExtensionMetadataPointer,
+		MetadataPointerInstruction: MetadataPointerInstructionUpdate,
+		MetadataAddress:            optionalNonZeroPubkey(param.MetadataAddress),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	accounts := make([]types.AccountMeta, 0, 2+len(param.Signers))
+	accounts = append(accounts, types.AccountMeta{PubKey: param.Mint, IsSigner: false, IsWritable: true})
+	accounts = append(accounts, types.AccountMeta{PubKey: param.Auth, IsSigner: len(param.Signers) == 0, IsWritable: false})
+	for _, signerPubkey := range param.Signers {
+		accounts = append(accounts, types.AccountMeta{PubKey: signerPubkey, IsSigner: true, IsWritable: false})
+	}
+
+	return types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts:  accounts,
+		Data:      data,
+	}
+}
+
+// optionalNonZeroPubkey packs a none as the zero pubkey, unlike a COption it is always 32 bytes
+func optionalNonZeroPubkey(pubkey *common.PublicKey) common.PublicKey {
+	if pubkey == nil {
+		return common.PublicKey{}
+	}
+	return *pubkey
+}
@@ -0,0 +1,57 @@
+package token2022
+
+import (
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/pkg/pointer"
+	"github.com/labyla/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInitializeMetadataPointer(t *testing.T) {
+	assert.Equal(t, types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: testMint, IsSigner: false, IsWritable: true},
+		},
+		Data: concat([]byte{39, 0}, testAuthority.Bytes(), testMint.Bytes()),
+	}, InitializeMetadataPointer(InitializeMetadataPointerParam{
+		Mint:            testMint,
+		Authority:       pointer.Get(testAuthority),
+		MetadataAddress: pointer.Get(testMint),
+	}))
+
+	assert.Equal(t, concat([]byte{39, 0}, make([]byte, 64)), InitializeMetadataPointer(InitializeMetadataPointerParam{
+		Mint: testMint,
+	}).Data)
+}
+
+func TestUpdateMetadataPointer(t *testing.T) {
+	assert.Equal(t, types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: testMint, IsSigner: false, IsWritable: true},
+			{PubKey: testAuthority, IsSigner: true, IsWritable: false},
+		},
+		Data: concat([]byte{39, 1}, testAccount1.Bytes()),
+	}, UpdateMetadataPointer(UpdateMetadataPointerParam{
+		Mint:            testMint,
+		Auth:            testAuthority,
+		MetadataAddress: pointer.Get(testAccount1),
+	}))
+
+	assert.Equal(t, types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: testMint, IsSigner: false, IsWritable: true},
+			{PubKey: testAuthority, IsSigner: false, IsWritable: false},
+			{PubKey: testSigner1, IsSigner: true, IsWritable: false},
+		},
+		Data: concat([]byte{39, 1}, make([]byte, 32)),
+	}, UpdateMetadataPointer(UpdateMetadataPointerParam{
+		Mint:    testMint,
+		Auth:    testAuthority,
+		Signers: []common.PublicKey{testSigner1},
+	}))
+}
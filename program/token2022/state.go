@@ -2,6 +2,7 @@ package token2022
 
 import (
 	"encoding/binary"
+	"math"
 
 	"github.com/labyla/solana-go-sdk/common"
 )
@@ -52,29 +53,35 @@ const (
 // BaseAccountLength is the length after which extensions start
 const BaseAccountLength = 165
 
+const multisigSize = 355
+
 // Extension sizes
 const (
-	MintCloseAuthoritySize     = 32
-	TransferFeeConfigSize      = 108
-	TransferFeeAmountSize      = 8
-	DefaultAccountStateSize    = 1
-	ImmutableOwnerSize         = 0
-	MemoTransferSize           = 1
-	NonTransferableSize        = 0
-	NonTransferableAccountSize = 0
-	InterestBearingConfigSize  = 52
-	CpiGuardSize               = 1
-	PermanentDelegateSize      = 32
-	TransferHookSize           = 64
-	TransferHookAccountSize    = 1
-	MetadataPointerSize        = 64
-	GroupPointerSize           = 64
-	GroupMemberPointerSize     = 64
-	TokenGroupSize             = 72
-	TokenGroupMemberSize       = 72
-	ScaledUiAmountConfigSize   = 26
-	PausableConfigSize         = 1
-	PausableAccountSize        = 0
+	MintCloseAuthoritySize            = 32
+	TransferFeeConfigSize             = 108
+	TransferFeeAmountSize             = 8
+	ConfidentialTransferMintSize      = 65
+	ConfidentialTransferAccountSize   = 295
+	DefaultAccountStateSize           = 1
+	ImmutableOwnerSize                = 0
+	MemoTransferSize                  = 1
+	NonTransferableSize               = 0
+	NonTransferableAccountSize        = 0
+	InterestBearingConfigSize         = 52
+	CpiGuardSize                      = 1
+	PermanentDelegateSize             = 32
+	TransferHookSize                  = 64
+	TransferHookAccountSize           = 1
+	ConfidentialTransferFeeConfigSize = 129
+	ConfidentialTransferFeeAmountSize = 64
+	MetadataPointerSize               = 64
+	GroupPointerSize                  = 64
+	GroupMemberPointerSize            = 64
+	TokenGroupSize                    = 80
+	TokenGroupMemberSize              = 72
+	ScaledUiAmountConfigSize          = 56
+	PausableConfigSize                = 33
+	PausableAccountSize               = 0
 )
 
 // MintCloseAuthority extension - allows closing a mint account
@@ -174,15 +181,15 @@ type GroupMemberPointer struct {
 type TokenGroup struct {
 	UpdateAuthority common.PublicKey
 	Mint            common.PublicKey
-	Size            uint32
-	MaxSize         uint32
+	Size            uint64
+	MaxSize         uint64
 }
 
 // TokenGroupMember extension - group member configuration stored in mint
 type TokenGroupMember struct {
 	Mint         common.PublicKey
 	Group        common.PublicKey
-	MemberNumber uint32
+	MemberNumber uint64
 }
 
 // ScaledUiAmountConfig extension - UI amount scaling
@@ -195,7 +202,8 @@ type ScaledUiAmountConfig struct {
 
 // PausableConfig extension - allows pausing mint operations
 type PausableConfig struct {
-	Paused bool
+	Authority common.PublicKey
+	Paused    bool
 }
 
 // PausableAccount extension - indicates account belongs to pausable mint
@@ -251,14 +259,13 @@ func ParseMintExtensions(data []byte) (*MintExtensions, error) {
 	}
 
 	// Account type is at BaseAccountLength
-	if len(data) <= BaseAccountLength+1 {
-		return nil, nil
-	}
-
 	accountType := AccountType(data[BaseAccountLength])
 	if accountType != AccountTypeMint {
 		return nil, ErrInvalidAccountDataSize
 	}
+	if len(data) == BaseAccountLength+1 {
+		return nil, nil
+	}
 
 	extensions := &MintExtensions{}
 	tlvData := data[BaseAccountLength+1:]
@@ -279,14 +286,13 @@ func ParseAccountExtensions(data []byte) (*AccountExtensions, error) {
 	}
 
 	// Account type is at BaseAccountLength
-	if len(data) <= BaseAccountLength+1 {
-		return nil, nil
-	}
-
 	accountType := AccountType(data[BaseAccountLength])
 	if accountType != AccountTypeAccount {
 		return nil, ErrInvalidAccountDataSize
 	}
+	if len(data) == BaseAccountLength+1 {
+		return nil, nil
+	}
 
 	extensions := &AccountExtensions{}
 	tlvData := data[BaseAccountLength+1:]
@@ -326,7 +332,47 @@ func parseTLVExtensions(data []byte, handler func(ExtensionType, []byte) error)
 	return nil
 }
 
+// extensionSizes are the sizes of the fixed size extensions
+var extensionSizes = map[ExtensionType]int{
+	ExtensionTypeMintCloseAuthority:            MintCloseAuthoritySize,
+	ExtensionTypeTransferFeeConfig:             TransferFeeConfigSize,
+	ExtensionTypeTransferFeeAmount:             TransferFeeAmountSize,
+	ExtensionTypeConfidentialTransferMint:      ConfidentialTransferMintSize,
+	ExtensionTypeConfidentialTransferAccount:   ConfidentialTransferAccountSize,
+	ExtensionTypeDefaultAccountState:           DefaultAccountStateSize,
+	ExtensionTypeImmutableOwner:                ImmutableOwnerSize,
+	ExtensionTypeMemoTransfer:                  MemoTransferSize,
+	ExtensionTypeNonTransferable:               NonTransferableSize,
+	ExtensionTypeInterestBearingConfig:         InterestBearingConfigSize,
+	ExtensionTypeCpiGuard:                      CpiGuardSize,
+	ExtensionTypePermanentDelegate:             PermanentDelegateSize,
+	ExtensionTypeNonTransferableAccount:        NonTransferableAccountSize,
+	ExtensionTypeTransferHook:                  TransferHookSize,
+	ExtensionTypeTransferHookAccount:           TransferHookAccountSize,
+	ExtensionTypeConfidentialTransferFeeConfig: ConfidentialTransferFeeConfigSize,
+	ExtensionTypeConfidentialTransferFeeAmount: ConfidentialTransferFeeAmountSize,
+	ExtensionTypeMetadataPointer:               MetadataPointerSize,
+	ExtensionTypeGroupPointer:                  GroupPointerSize,
+	ExtensionTypeGroupMemberPointer:            GroupMemberPointerSize,
+	ExtensionTypeTokenGroup:                    TokenGroupSize,
+	ExtensionTypeTokenGroupMember:              TokenGroupMemberSize,
+	ExtensionTypeScaledUiAmount:                ScaledUiAmountConfigSize,
+	ExtensionTypePausable:                      PausableConfigSize,
+	ExtensionTypePausableAccount:               PausableAccountSize,
+}
+
+// checkExtensionSize rejects a fixed size extension which is too short to be parsed
+func checkExtensionSize(extType ExtensionType, data []byte) error {
+	if size, ok := extensionSizes[extType]; ok && len(data) < size {
+		return ErrInvalidAccountDataSize
+	}
+	return nil
+}
+
 func parseMintExtension(ext *MintExtensions, extType ExtensionType, data []byte) error {
+	if err := checkExtensionSize(extType, data); err != nil {
+		return err
+	}
 	switch extType {
 	case ExtensionTypeMintCloseAuthority:
 		if len(data) >= 32 {
@@ -409,8 +455,8 @@ func parseMintExtension(ext *MintExtensions, extType ExtensionType, data []byte)
 			ext.TokenGroup = &TokenGroup{
 				UpdateAuthority: common.PublicKeyFromBytes(data[0:32]),
 				Mint:            common.PublicKeyFromBytes(data[32:64]),
-				Size:            binary.LittleEndian.Uint32(data[64:68]),
-				MaxSize:         binary.LittleEndian.Uint32(data[68:72]),
+				Size:            binary.LittleEndian.Uint64(data[64:72]),
+				MaxSize:         binary.LittleEndian.Uint64(data[72:80]),
 			}
 		}
 	case ExtensionTypeTokenGroupMember:
@@ -418,25 +464,39 @@ func parseMintExtension(ext *MintExtensions, extType ExtensionType, data []byte)
 			ext.TokenGroupMember = &TokenGroupMember{
 				Mint:         common.PublicKeyFromBytes(data[0:32]),
 				Group:        common.PublicKeyFromBytes(data[32:64]),
-				MemberNumber: binary.LittleEndian.Uint32(data[64:68]),
+				MemberNumber: binary.LittleEndian.Uint64(data[64:72]),
+			}
+		}
+	case ExtensionTypeScaledUiAmount:
+		if len(data) >= ScaledUiAmountConfigSize {
+			ext.ScaledUiAmountConfig = &ScaledUiAmountConfig{
+				Authority:                       common.PublicKeyFromBytes(data[0:32]),
+				Multiplier:                      math.Float64frombits(binary.LittleEndian.Uint64(data[32:40])),
+				NewMultiplierEffectiveTimestamp: int64(binary.LittleEndian.Uint64(data[40:48])),
+				NewMultiplier:                   math.Float64frombits(binary.LittleEndian.Uint64(data[48:56])),
 			}
 		}
 	case ExtensionTypePausable:
-		if len(data) >= 1 {
+		if len(data) >= PausableConfigSize {
 			ext.PausableConfig = &PausableConfig{
-				Paused: data[0] == 1,
+				Authority: common.PublicKeyFromBytes(data[0:32]),
+				Paused:    data[32] == 1,
 			}
 		}
 	case ExtensionTypeTokenMetadata:
-		// Variable length - parse carefully
-		if metadata, err := parseTokenMetadata(data); err == nil {
-			ext.TokenMetadata = metadata
+		metadata, err := parseTokenMetadata(data)
+		if err != nil {
+			return err
 		}
+		ext.TokenMetadata = metadata
 	}
 	return nil
 }
 
 func parseAccountExtension(ext *AccountExtensions, extType ExtensionType, data []byte) error {
+	if err := checkExtensionSize(extType, data); err != nil {
+		return err
+	}
 	switch extType {
 	case ExtensionTypeTransferFeeAmount:
 		if len(data) >= 8 {
@@ -474,7 +534,7 @@ func parseAccountExtension(ext *AccountExtensions, extType ExtensionType, data [
 
 // parseTokenMetadata parses variable-length token metadata
 func parseTokenMetadata(data []byte) (*TokenMetadata, error) {
-	if len(data) < 68 { // minimum: 2 pubkeys + 4 length bytes
+	if len(data) < 64+4*4 { // minimum: 2 pubkeys + 4 length prefixes
 		return nil, ErrInvalidAccountDataSize
 	}
 
@@ -484,80 +544,58 @@ func parseTokenMetadata(data []byte) (*TokenMetadata, error) {
 	}
 
 	offset := 64
-
-	// Parse name
-	if offset+4 > len(data) {
-		return metadata, nil
-	}
-	nameLen := int(binary.LittleEndian.Uint32(data[offset : offset+4]))
-	offset += 4
-	if offset+nameLen > len(data) {
-		return metadata, nil
+	readString := func() (string, error) {
+		if len(data)-offset < 4 {
+			return "", ErrInvalidAccountDataSize
+		}
+		l := binary.LittleEndian.Uint32(data[offset : offset+4])
+		offset += 4
+		if uint64(l) > uint64(len(data)-offset) {
+			return "", ErrInvalidAccountDataSize
+		}
+		v := string(data[offset : offset+int(l)])
+		offset += int(l)
+		return v, nil
 	}
-	metadata.Name = string(data[offset : offset+nameLen])
-	offset += nameLen
 
-	// Parse symbol
-	if offset+4 > len(data) {
-		return metadata, nil
-	}
-	symbolLen := int(binary.LittleEndian.Uint32(data[offset : offset+4]))
-	offset += 4
-	if offset+symbolLen > len(data) {
-		return metadata, nil
+	var err error
+	if metadata.Name, err = readString(); err != nil {
+		return nil, err
 	}
-	metadata.Symbol = string(data[offset : offset+symbolLen])
-	offset += symbolLen
-
-	// Parse URI
-	if offset+4 > len(data) {
-		return metadata, nil
+	if metadata.Symbol, err = readString(); err != nil {
+		return nil, err
 	}
-	uriLen := int(binary.LittleEndian.Uint32(data[offset : offset+4]))
-	offset += 4
-	if offset+uriLen > len(data) {
-		return metadata, nil
+	if metadata.Uri, err = readString(); err != nil {
+		return nil, err
 	}
-	metadata.Uri = string(data[offset : offset+uriLen])
-	offset += uriLen
 
-	// Parse additional metadata (key-value pairs)
-	if offset+4 > len(data) {
-		return metadata, nil
+	// additional metadata (key-value pairs)
+	if len(data)-offset < 4 {
+		return nil, ErrInvalidAccountDataSize
 	}
-	numPairs := int(binary.LittleEndian.Uint32(data[offset : offset+4]))
+	numPairs := binary.LittleEndian.Uint32(data[offset : offset+4])
 	offset += 4
-
-	for i := 0; i < numPairs && offset < len(data); i++ {
-		// Parse key
-		if offset+4 > len(data) {
-			break
-		}
-		keyLen := int(binary.LittleEndian.Uint32(data[offset : offset+4]))
-		offset += 4
-		if offset+keyLen > len(data) {
-			break
-		}
-		key := string(data[offset : offset+keyLen])
-		offset += keyLen
-
-		// Parse value
-		if offset+4 > len(data) {
-			break
+	// a pair takes at least its two length prefixes
+	if uint64(numPairs) > uint64(len(data)-offset)/8 {
+		return nil, ErrInvalidAccountDataSize
+	}
+	for i := uint32(0); i < numPairs; i++ {
+		key, err := readString()
+		if err != nil {
+			return nil, err
 		}
-		valueLen := int(binary.LittleEndian.Uint32(data[offset : offset+4]))
-		offset += 4
-		if offset+valueLen > len(data) {
-			break
+		value, err := readString()
+		if err != nil {
+			return nil, err
 		}
-		value := string(data[offset : offset+valueLen])
-		offset += valueLen
-
 		metadata.AdditionalMetadata = append(metadata.AdditionalMetadata, struct {
 			Key   string
 			Value string
 		}{Key: key, Value: value})
 	}
+	if offset != len(data) {
+		return nil, ErrInvalidAccountDataSize
+	}
 
 	return metadata, nil
 }
@@ -0,0 +1,93 @@
+package token2022
+
+import (
+	"encoding/binary"
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func tlv(extType ExtensionType, value []byte) []byte {
+	b := make([]byte, 4, 4+len(value))
+	binary.LittleEndian.PutUint16(b[0:2], uint16(extType))
+	binary.LittleEndian.PutUint16(b[2:4], uint16(len(value)))
+	return append(b, value...)
+}
+
+func mintWithExtensions(tlvs ...[]byte) []byte {
+	data := make([]byte, BaseAccountLength)
+	data = append(data, byte(AccountTypeMint))
+	for _, v := range tlvs {
+		data = append(data, v...)
+	}
+	return data
+}
+
+func borshString(s string) []byte {
+	b := make([]byte, 4, 4+len(s))
+	binary.LittleEndian.PutUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+func tokenMetadataValue(name, symbol, uri string, pairs ...string) []byte {
+	b := make([]byte, 64)
+	b = append(b, borshString(name)...)
+	b = append(b, borshString(symbol)...)
+	b = append(b, borshString(uri)...)
+	n := make([]byte, 4)
+	binary.LittleEndian.PutUint32(n, uint32(len(pairs)/2))
+	b = append(b, n...)
+	for _, p := range pairs {
+		b = append(b, borshString(p)...)
+	}
+	return b
+}
+
+func TestParseMintExtensions(t *testing.T) {
+	authority := common.PublicKeyFromString("9aE476sH92Vz7DMPyq5WLPkrKWivxeuTKEFKd2sZZcde")
+	extensions, err := ParseMintExtensions(mintWithExtensions(
+		tlv(ExtensionTypeMintCloseAuthority, authority.Bytes()),
+		tlv(ExtensionTypeTokenMetadata, tokenMetadataValue("name", "SYM", "uri", "k", "v")),
+	))
+	assert.Nil(t, err)
+	assert.Equal(t, authority, extensions.MintCloseAuthority.CloseAuthority)
+	assert.Equal(t, "name", extensions.TokenMetadata.Name)
+	assert.Equal(t, "SYM", extensions.TokenMetadata.Symbol)
+	assert.Equal(t, "uri", extensions.TokenMetadata.Uri)
+	assert.Len(t, extensions.TokenMetadata.AdditionalMetadata, 1)
+	assert.Equal(t, "v", extensions.TokenMetadata.AdditionalMetadata[0].Value)
+}
+
+func TestParseMintExtensions_Adversarial(t *testing.T) {
+	metadata := tokenMetadataValue("name", "SYM", "uri", "k", "v")
+	hugeString := append(make([]byte, 64), 0xff, 0xff, 0xff, 0xff)
+	hugePairs := append(tokenMetadataValue("n", "s", "u")[:64+15], 0xff, 0xff, 0xff, 0x7f)
+
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{name: "tlv longer than data", data: mintWithExtensions([]byte{3, 0, 200, 0}, make([]byte, 10))},
+		{name: "short fixed size extension", data: mintWithExtensions(tlv(ExtensionTypeTransferFeeConfig, make([]byte, 100)))},
+		{name: "short pointer", data: mintWithExtensions(tlv(ExtensionTypeMetadataPointer, make([]byte, 63)))},
+		{name: "metadata truncated", data: mintWithExtensions(tlv(ExtensionTypeTokenMetadata, metadata[:len(metadata)-1]))},
+		{name: "metadata trailing byte", data: mintWithExtensions(tlv(ExtensionTypeTokenMetadata, append(metadata, 0)))},
+		{name: "metadata huge string", data: mintWithExtensions(tlv(ExtensionTypeTokenMetadata, append(hugeString, make([]byte, 12)...)))},
+		{name: "metadata huge pair count", data: mintWithExtensions(tlv(ExtensionTypeTokenMetadata, hugePairs))},
+		{name: "wrong account type", data: append(make([]byte, BaseAccountLength), byte(AccountTypeAccount), 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseMintExtensions(tt.data)
+			assert.ErrorIs(t, err, ErrInvalidAccountDataSize)
+		})
+	}
+}
+
+func TestParseAccountExtensions_Adversarial(t *testing.T) {
+	data := append(make([]byte, BaseAccountLength), byte(AccountTypeAccount))
+	data = append(data, tlv(ExtensionTypeTransferFeeAmount, make([]byte, 7))...)
+	_, err := ParseAccountExtensions(data)
+	assert.ErrorIs(t, err, ErrInvalidAccountDataSize)
+}
@@ -0,0 +1,324 @@
+package token2022
+
+import (
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/program/sysvar"
+	"github.com/labyla/solana-go-sdk/types"
+	"github.com/near/borsh-go"
+)
+
+// the token metadata interface identifies instructions by the first 8 bytes of
+// sha256("spl_token_metadata_interface:<name>") instead of a tag
+var (
+	tokenMetadataInitializeDiscriminator      = [8]byte{210, 225, 30, 162, 88, 184, 77, 141}
+	tokenMetadataUpdateFieldDiscriminator     = [8]byte{221, 233, 49, 45, 181, 202, 220, 200}
+	tokenMetadataRemoveKeyDiscriminator       = [8]byte{234, 18, 32, 56, 89, 141, 37, 181}
+	tokenMetadataUpdateAuthorityDiscriminator = [8]byte{215, 228, 166, 228, 84, 100, 86, 123}
+	tokenMetadataEmitDiscriminator            = [8]byte{250, 166, 180, 250, 13, 12, 184, 70}
+)
+
+type TokenMetadataFieldType uint8
+
+const (
+	TokenMetadataFieldTypeName TokenMetadataFieldType = iota
+	TokenMetadataFieldTypeSymbol
+	TokenMetadataFieldTypeUri
+	TokenMetadataFieldTypeKey
+)
+
+// TokenMetadataField is a field of the token metadata, Key is the name of a custom field
+type TokenMetadataField struct {
+	Type TokenMetadataFieldType
+	Key  string
+}
+
+var (
+	TokenMetadataFieldName   = TokenMetadataField{Type: TokenMetadataFieldTypeName}
+	TokenMetadataFieldSymbol = TokenMetadataField{Type: TokenMetadataFieldTypeSymbol}
+	TokenMetadataFieldUri    = TokenMetadataField{Type: TokenMetadataFieldTypeUri}
+)
+
+// TokenMetadataFieldKey is a custom field, it is kept in the additional metadata
+func TokenMetadataFieldKey(key string) TokenMetadataField {
+	return TokenMetadataField{Type: TokenMetadataFieldTypeKey, Key: key}
+}
+
+type InitializeTokenMetadataParam struct {
+	// Metadata is the account the metadata pointer points to, usually the mint
+	Metadata        common.PublicKey
+	UpdateAuthority common.PublicKey
+	Mint            common.PublicKey
+	MintAuthority   common.PublicKey
+	Name            string
+	Symbol          string
+	Uri             string
+}
+
+// InitializeTokenMetadata writes the metadata after InitializeMint. the mint grows to fit it,
+// transfer the lamports GetTokenMetadataRealloc returns to the mint first.
+func InitializeTokenMetadata(param InitializeTokenMetadataParam) types.Instruction {
+	data, err := borsh.Serialize(struct {
+		Discriminator [8]byte
+		Name          string
+		Symbol        string
+		Uri           string
+	}{
+		Discriminator: tokenMetadataInitializeDiscriminator,
+		Name:          param.Name,
+		Symbol:        param.Symbol,
+		Uri:           param.Uri,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: param.Metadata, IsSigner: false, IsWritable: true},
+			{PubKey: param.UpdateAuthority, IsSigner: false, IsWritable: false},
+			{PubKey: param.Mint, IsSigner: false, IsWritable: false},
+			{PubKey: param.MintAuthority, IsSigner: true, IsWritable: false},
+		},
+		Data: data,
+	}
+}
+
+type UpdateTokenMetadataFieldParam struct {
+	Metadata        common.PublicKey
+	UpdateAuthority common.PublicKey
+	Field           TokenMetadataField
+	Value           string
+}
+
+// UpdateTokenMetadataField sets a field, a custom field is added if it doesn't exist
+func UpdateTokenMetadataField(param UpdateTokenMetadataFieldParam) types.Instruction {
+	var data []byte
+	var err error
+	if param.Field.Type == TokenMetadataFieldTypeKey {
+		data, err = borsh.Serialize(struct {
+			Discriminator [8]byte
+			FieldType     TokenMetadataFieldType
+			Key           string
+			Value         string
+		}{
+			Discriminator: tokenMetadataUpdateFieldDiscriminator,
+			FieldType:     param.Field.Type,
+			Key:           param.Field.Key,
+			Value:         param.Value,
+		})
+	} else {
+		data, err = borsh.Serialize(struct {
+			Discriminator [8]byte
+			FieldType     TokenMetadataFieldType
+			Value         string
+		}{
+			Discriminator: tokenMetadataUpdateFieldDiscriminator,
+			FieldType:     param.Field.Type,
+			Value:         param.Value,
+		})
+	}
+	if err != nil {
+		panic(err)
+	}
+
+	return types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: param.Metadata, IsSigner: false, IsWritable: true},
+			{PubKey: param.UpdateAuthority, IsSigner: true, IsWritable: false},
+		},
+		Data: data,
+	}
+}
+
+type RemoveTokenMetadataKeyParam struct {
+	Metadata        common.PublicKey
+	UpdateAuthority common.PublicKey
+	Key             string
+	// Idempotent doesn't fail if the key doesn't exist
+	Idempotent bool
+}
+
+func RemoveTokenMetadataKey(param RemoveTokenMetadataKeyParam) types.Instruction {
+	data, err := borsh.Serialize(struct {
+		Discriminator [8]byte
+		Idempotent    bool
+		Key           string
+	}{
+		Discriminator: tokenMetadataRemoveKeyDiscriminator,
+		Idempotent:    param.Idempotent,
+		Key:           param.Key,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: param.Metadata, IsSigner: false, IsWritable: true},
+			{PubKey: param.UpdateAuthority, IsSigner: true, IsWritable: false},
+		},
+		Data: data,
+	}
+}
+
+type UpdateTokenMetadataAuthorityParam struct {
+	Metadata        common.PublicKey
+	UpdateAuthority common.PublicKey
+	// NewAuthority nil makes the metadata immutable
+	NewAuthority *common.PublicKey
+}
+
+func UpdateTokenMetadataAuthority(param UpdateTokenMetadataAuthorityParam) types.Instruction {
+	data, err := borsh.Serialize(struct {
+		Discriminator [8]byte
+		NewAuthority  common.PublicKey
+	}{
+		Discriminator: tokenMetadataUpdateAuthorityDiscriminator,
+		NewAuthority:  optionalNonZeroPubkey(param.NewAuthority),
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: param.Metadata, IsSigner: false, IsWritable: true},
+			{PubKey: param.UpdateAuthority, IsSigner: true, IsWritable: false},
+		},
+		Data: data,
+	}
+}
+
+type EmitTokenMetadataParam struct {
+	Metadata common.PublicKey
+	// Start and End select a range of the packed metadata, nil for all of it
+	Start *uint64
+	End   *uint64
+}
+
+// EmitTokenMetadata sets the packed metadata as the return data of the transaction, for simulations
+func EmitTokenMetadata(param EmitTokenMetadataParam) types.Instruction {
+	data, err := borsh.Serialize(struct {
+		Discriminator [8]byte
+		Start         *uint64
+		End           *uint64
+	}{
+		Discriminator: tokenMetadataEmitDiscriminator,
+		Start:         param.Start,
+		End:           param.End,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	return types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: param.Metadata, IsSigner: false, IsWritable: false},
+		},
+		Data: data,
+	}
+}
+
+// Size returns the length of the packed metadata
+func (m TokenMetadata) Size() int {
+	n := 32 + 32 + 4 + len(m.Name) + 4 + len(m.Symbol) + 4 + len(m.Uri) + 4
+	for _, kv := range m.AdditionalMetadata {
+		n += 4 + len(kv.Key) + 4 + len(kv.Value)
+	}
+	return n
+}
+
+// Update sets a field like UpdateTokenMetadataField does on chain
+func (m *TokenMetadata) Update(field TokenMetadataField, value string) {
+	switch field.Type {
+	case TokenMetadataFieldTypeName:
+		m.Name = value
+	case TokenMetadataFieldTypeSymbol:
+		m.Symbol = value
+	case TokenMetadataFieldTypeUri:
+		m.Uri = value
+	case TokenMetadataFieldTypeKey:
+		// copy first, the slice may be shared with the metadata this one was copied from
+		additionalMetadata := make([]struct {
+			Key   string
+			Value string
+		}, len(m.AdditionalMetadata), len(m.AdditionalMetadata)+1)
+		copy(additionalMetadata, m.AdditionalMetadata)
+		m.AdditionalMetadata = additionalMetadata
+
+		for i := range m.AdditionalMetadata {
+			if m.AdditionalMetadata[i].Key == field.Key {
+				m.AdditionalMetadata[i].Value = value
+				return
+			}
+		}
+		m.AdditionalMetadata = append(m.AdditionalMetadata, struct {
+			Key   string
+			Value string
+		}{Key: field.Key, Value: value})
+	}
+}
+
+// RemoveKey removes a custom field like RemoveTokenMetadataKey does on chain, it returns false if there was none
+func (m *TokenMetadata) RemoveKey(key string) bool {
+	for i, kv := range m.AdditionalMetadata {
+		if kv.Key == key {
+			additionalMetadata := make([]struct {
+				Key   string
+				Value string
+			}, 0, len(m.AdditionalMetadata)-1)
+			additionalMetadata = append(additionalMetadata, m.AdditionalMetadata[:i]...)
+			m.AdditionalMetadata = append(additionalMetadata, m.AdditionalMetadata[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// TokenMetadataRealloc is how a mint changes when its metadata is written
+type TokenMetadataRealloc struct {
+	OldLen int
+	NewLen int
+	// ExtraLamports is what the mint misses to stay rent exempt with the new length
+	ExtraLamports uint64
+}
+
+// GetTokenMetadataRealloc returns how the mint changes when its metadata becomes the metadata. token-2022
+// reallocs the mint for the metadata but doesn't fund it, transfer ExtraLamports to the mint before the
+// metadata instruction. a zero rent uses sysvar.DefaultRent.
+func GetTokenMetadataRealloc(mintData []byte, mintLamports uint64, metadata TokenMetadata, rent sysvar.Rent) (TokenMetadataRealloc, error) {
+	if len(mintData) <= BaseAccountLength || AccountType(mintData[BaseAccountLength]) != AccountTypeMint {
+		return TokenMetadataRealloc{}, ErrInvalidAccountDataSize
+	}
+	if rent == (sysvar.Rent{}) {
+		rent = sysvar.DefaultRent
+	}
+
+	// the program sizes the account by the extensions in use, not by its current length
+	usedLen, currentLen := 0, 0
+	if err := parseTLVExtensions(mintData[BaseAccountLength+1:], func(extType ExtensionType, data []byte) error {
+		usedLen += 4 + len(data)
+		if extType == ExtensionTypeTokenMetadata {
+			currentLen = 4 + len(data)
+		}
+		return nil
+	}); err != nil {
+		return TokenMetadataRealloc{}, err
+	}
+
+	newLen := BaseAccountLength + 1 + usedLen - currentLen + 4 + metadata.Size()
+	// an account of the size of a multisig gets an extra type header to tell them apart
+	if newLen == multisigSize {
+		newLen += 2
+	}
+	realloc := TokenMetadataRealloc{OldLen: len(mintData), NewLen: newLen}
+	if minimumBalance := rent.MinimumBalance(uint64(newLen)); minimumBalance > mintLamports {
+		realloc.ExtraLamports = minimumBalance - mintLamports
+	}
+	return realloc, nil
+}
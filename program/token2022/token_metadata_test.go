@@ -0,0 +1,193 @@
+package token2022
+
+import (
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/pkg/pointer"
+	"github.com/labyla/solana-go-sdk/program/sysvar"
+	"github.com/labyla/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitializeTokenMetadata(t *testing.T) {
+	assert.Equal(t, types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: testMint, IsSigner: false, IsWritable: true},
+			{PubKey: testAccount1, IsSigner: false, IsWritable: false},
+			{PubKey: testMint, IsSigner: false, IsWritable: false},
+			{PubKey: testAuthority, IsSigner: true, IsWritable: false},
+		},
+		Data: concat(
+			[]byte{210, 225, 30, 162, 88, 184, 77, 141},
+			[]byte{4, 0, 0, 0}, []byte("Coin"),
+			[]byte{3, 0, 0, 0}, []byte("CON"),
+			[]byte{19, 0, 0, 0}, []byte("https://example.com"),
+		),
+	}, InitializeTokenMetadata(InitializeTokenMetadataParam{
+		Metadata:        testMint,
+		UpdateAuthority: testAccount1,
+		Mint:            testMint,
+		MintAuthority:   testAuthority,
+		Name:            "Coin",
+		Symbol:          "CON",
+		Uri:             "https://example.com",
+	}))
+}
+
+func TestUpdateTokenMetadataField(t *testing.T) {
+	tests := []struct {
+		name  string
+		field TokenMetadataField
+		value string
+		want  []byte
+	}{
+		{
+			name:  "name",
+			field: TokenMetadataFieldName,
+			value: "A",
+			want:  []byte{221, 233, 49, 45, 181, 202, 220, 200, 0, 1, 0, 0, 0, 'A'},
+		},
+		{
+			name:  "uri",
+			field: TokenMetadataFieldUri,
+			value: "",
+			want:  []byte{221, 233, 49, 45, 181, 202, 220, 200, 2, 0, 0, 0, 0},
+		},
+		{
+			name:  "custom key",
+			field: TokenMetadataFieldKey("ab"),
+			value: "c",
+			want:  []byte{221, 233, 49, 45, 181, 202, 220, 200, 3, 2, 0, 0, 0, 'a', 'b', 1, 0, 0, 0, 'c'},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, types.Instruction{
+				ProgramID: common.Token2022ProgramID,
+				Accounts: []types.AccountMeta{
+					{PubKey: testMint, IsSigner: false, IsWritable: true},
+					{PubKey: testAuthority, IsSigner: true, IsWritable: false},
+				},
+				Data: tt.want,
+			}, UpdateTokenMetadataField(UpdateTokenMetadataFieldParam{
+				Metadata:        testMint,
+				UpdateAuthority: testAuthority,
+				Field:           tt.field,
+				Value:           tt.value,
+			}))
+		})
+	}
+}
+
+func TestRemoveTokenMetadataKey(t *testing.T) {
+	assert.Equal(t, types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: testMint, IsSigner: false, IsWritable: true},
+			{PubKey: testAuthority, IsSigner: true, IsWritable: false},
+		},
+		Data: []byte{234, 18, 32, 56, 89, 141, 37, 181, 1, 2, 0, 0, 0, 'a', 'b'},
+	}, RemoveTokenMetadataKey(RemoveTokenMetadataKeyParam{
+		Metadata:        testMint,
+		UpdateAuthority: testAuthority,
+		Key:             "ab",
+		Idempotent:      true,
+	}))
+}
+
+func TestUpdateTokenMetadataAuthority(t *testing.T) {
+	assert.Equal(t, types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: testMint, IsSigner: false, IsWritable: true},
+			{PubKey: testAuthority, IsSigner: true, IsWritable: false},
+		},
+		Data: concat([]byte{215, 228, 166, 228, 84, 100, 86, 123}, testAccount1.Bytes()),
+	}, UpdateTokenMetadataAuthority(UpdateTokenMetadataAuthorityParam{
+		Metadata:        testMint,
+		UpdateAuthority: testAuthority,
+		NewAuthority:    pointer.Get(testAccount1),
+	}))
+
+	assert.Equal(t, concat([]byte{215, 228, 166, 228, 84, 100, 86, 123}, make([]byte, 32)), UpdateTokenMetadataAuthority(UpdateTokenMetadataAuthorityParam{
+		Metadata:        testMint,
+		UpdateAuthority: testAuthority,
+	}).Data)
+}
+
+func TestEmitTokenMetadata(t *testing.T) {
+	assert.Equal(t, types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: testMint, IsSigner: false, IsWritable: false},
+		},
+		Data: []byte{250, 166, 180, 250, 13, 12, 184, 70, 0, 0},
+	}, EmitTokenMetadata(EmitTokenMetadataParam{Metadata: testMint}))
+
+	assert.Equal(t,
+		[]byte{250, 166, 180, 250, 13, 12, 184, 70, 1, 64, 0, 0, 0, 0, 0, 0, 0, 0},
+		EmitTokenMetadata(EmitTokenMetadataParam{Metadata: testMint, Start: pointer.Get[uint64](64)}).Data,
+	)
+}
+
+func TestTokenMetadata_Update(t *testing.T) {
+	metadata, err := parseTokenMetadata(tokenMetadataValue("Coin", "CON", "uri", "a", "1", "b", "2"))
+	require.Nil(t, err)
+	assert.Equal(t, len(tokenMetadataValue("Coin", "CON", "uri", "a", "1", "b", "2")), metadata.Size())
+
+	updated := *metadata
+	updated.Update(TokenMetadataFieldName, "Token")
+	updated.Update(TokenMetadataFieldKey("a"), "10")
+	updated.Update(TokenMetadataFieldKey("c"), "3")
+	assert.True(t, updated.RemoveKey("b"))
+	assert.False(t, updated.RemoveKey("b"))
+
+	want, err := parseTokenMetadata(tokenMetadataValue("Token", "CON", "uri", "a", "10", "c", "3"))
+	require.Nil(t, err)
+	assert.Equal(t, want, &updated)
+
+	// the original is left as it was
+	assert.Equal(t, "1", metadata.AdditionalMetadata[0].Value)
+	assert.Len(t, metadata.AdditionalMetadata, 2)
+}
+
+func TestGetTokenMetadataRealloc(t *testing.T) {
+	mint := mintWithExtensions(
+		tlv(ExtensionTypeMetadataPointer, make([]byte, MetadataPointerSize)),
+		tlv(ExtensionTypeTokenMetadata, tokenMetadataValue("Coin", "CON", "uri")),
+	)
+	extensions, err := ParseMintExtensions(mint)
+	require.Nil(t, err)
+	lamports := sysvar.DefaultRent.MinimumBalance(uint64(len(mint)))
+
+	// a longer name
+	metadata := *extensions.TokenMetadata
+	metadata.Update(TokenMetadataFieldName, "Coins")
+	realloc, err := GetTokenMetadataRealloc(mint, lamports, metadata, sysvar.Rent{})
+	require.Nil(t, err)
+	assert.Equal(t, TokenMetadataRealloc{
+		OldLen:        len(mint),
+		NewLen:        len(mint) + 1,
+		ExtraLamports: 6960,
+	}, realloc)
+
+	// a shorter one needs nothing
+	metadata.Update(TokenMetadataFieldName, "C")
+	realloc, err = GetTokenMetadataRealloc(mint, lamports, metadata, sysvar.DefaultRent)
+	require.Nil(t, err)
+	assert.Equal(t, TokenMetadataRealloc{OldLen: len(mint), NewLen: len(mint) - 3}, realloc)
+
+	// the first metadata, the slack after the extensions is dropped
+	pointerOnly := append(mintWithExtensions(tlv(ExtensionTypeMetadataPointer, make([]byte, MetadataPointerSize))), make([]byte, 10)...)
+	metadata = TokenMetadata{Name: "Coin", Symbol: "CON", Uri: "uri"}
+	realloc, err = GetTokenMetadataRealloc(pointerOnly, 0, metadata, sysvar.DefaultRent)
+	require.Nil(t, err)
+	assert.Equal(t, len(mint), realloc.NewLen)
+	assert.Equal(t, sysvar.DefaultRent.MinimumBalance(uint64(len(mint))), realloc.ExtraLamports)
+
+	_, err = GetTokenMetadataRealloc(make([]byte, 82), 0, metadata, sysvar.DefaultRent)
+	assert.ErrorIs(t, err, ErrInvalidAccountDataSize)
+}
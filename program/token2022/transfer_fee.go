@@ -0,0 +1,322 @@
+package token2022
+
+import (
+	"encoding/binary"
+	"math/bits"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/pkg/bincode"
+	"github.com/labyla/solana-go-sdk/types"
+)
+
+// MaxFeeBasisPoints is a fee of 100%
+const MaxFeeBasisPoints = 10_000
+
+// TransferFeeInstruction is the second byte of the data of an InstructionExtensionTransferFeeConfig
+type TransferFeeInstruction uint8
+
+const (
+	TransferFeeInstructionInitializeTransferFeeConfig TransferFeeInstruction = iota
+	TransferFeeInstructionTransferCheckedWithFee
+	TransferFeeInstructionWithdrawWithheldTokensFromMint
+	TransferFeeInstructionWithdrawWithheldTokensFromAccounts
+	TransferFeeInstructionHarvestWithheldTokensToMint
+	TransferFeeInstructionSetTransferFee
+)
+
+type InitializeTransferFeeConfigParam struct {
+	Mint                       common.PublicKey
+	TransferFeeConfigAuthority *common.PublicKey
+	WithdrawWithheldAuthority  *common.PublicKey
+	TransferFeeBasisPoints     uint16
+	MaximumFee                 uint64
+}
+
+// InitializeTransferFeeConfig init the transfer fee of a mint, it must come before InitializeMint.
+// pass nil authorities if the fee can't be changed or withdrawn.
+func InitializeTransferFeeConfig(param InitializeTransferFeeConfigParam) types.Instruction {
+	// the authorities are COption<Pubkey>, packed without the key when they are none
+	data := make([]byte, 0, 2+2*33+2+8)
+	data = append(data, byte(InstructionExtensionTransferFeeConfig), byte(TransferFeeInstructionInitializeTransferFeeConfig))
+	data = appendPubkeyOption(data, param.TransferFeeConfigAuthority)
+	data = appendPubkeyOption(data, param.WithdrawWithheldAuthority)
+	data = binary.LittleEndian.AppendUint16(data, param.TransferFeeBasisPoints)
+	data = binary.LittleEndian.AppendUint64(data, param.MaximumFee)
+
+	return types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: param.Mint, IsSigner: false, IsWritable: true},
+		},
+		Data: data,
+	}
+}
+
+type TransferCheckedWithFeeParam struct {
+	From     common.PublicKey
+	To       common.PublicKey
+	Mint     common.PublicKey
+	Auth     common.PublicKey
+	Signers  []common.PublicKey
+	Amount   uint64
+	Decimals uint8
+	// Fee must equal the fee the mint charges for the amount in this epoch
+	Fee uint64
+}
+
+func TransferCheckedWithFee(param TransferCheckedWithFeeParam) types.Instruction {
+	data, err := bincode.SerializeData(struct {
+		Instruction            Instruction
+		TransferFeeInstruction TransferFeeInstruction
+		Amount                 uint64
+		Decimals               uint8
+		Fee                    uint64
+	}{
+		Instruction:            InstructionExtensionTransferFeeConfig,
+		TransferFeeInstruction: TransferFeeInstructionTransferCheckedWithFee,
+		Amount:                 param.Amount,
+		Decimals:               param.Decimals,
+		Fee:                    param.Fee,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	accounts := make([]types.AccountMeta, 0, 4+len(param.Signers))
+	accounts = append(accounts, types.AccountMeta{PubKey: param.From, IsSigner: false, IsWritable: true})
+	accounts = append(accounts, types.AccountMeta{PubKey: param.Mint, IsSigner: false, IsWritable: false})
+	accounts = append(accounts, types.AccountMeta{PubKey: param.To, IsSigner: false, IsWritable: true})
+	accounts = append(accounts, types.AccountMeta{PubKey: param.Auth, IsSigner: len(param.Signers) == 0, IsWritable: false})
+	for _, signerPubkey := range param.Signers {
+		accounts = append(accounts, types.AccountMeta{PubKey: signerPubkey, IsSigner: true, IsWritable: false})
+	}
+
+	return types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts:  accounts,
+		Data:      data,
+	}
+}
+
+type WithdrawWithheldTokensFromMintParam struct {
+	Mint        common.PublicKey
+	Destination common.PublicKey
+	// Auth is the withdraw withheld authority
+	Auth    common.PublicKey
+	Signers []common.PublicKey
+}
+
+// WithdrawWithheldTokensFromMint moves the fees harvested to the mint to the destination
+func WithdrawWithheldTokensFromMint(param WithdrawWithheldTokensFromMintParam) types.Instruction {
+	accounts := make([]types.AccountMeta, 0, 3+len(param.Signers))
+	accounts = append(accounts, types.AccountMeta{PubKey: param.Mint, IsSigner: false, IsWritable: true})
+	accounts = append(accounts, types.AccountMeta{PubKey: param.Destination, IsSigner: false, IsWritable: true})
+	accounts = append(accounts, types.AccountMeta{PubKey: param.Auth, IsSigner: len(param.Signers) == 0, IsWritable: false})
+	for _, signerPubkey := range param.Signers {
+		accounts = append(accounts, types.AccountMeta{PubKey: signerPubkey, IsSigner: true, IsWritable: false})
+	}
+
+	return types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts:  accounts,
+		Data:      []byte{byte(InstructionExtensionTransferFeeConfig), byte(TransferFeeInstructionWithdrawWithheldTokensFromMint)},
+	}
+}
+
+type WithdrawWithheldTokensFromAccountsParam struct {
+	Mint        common.PublicKey
+	Destination common.PublicKey
+	// Auth is the withdraw withheld authority
+	Auth    common.PublicKey
+	Signers []common.PublicKey
+	Sources []common.PublicKey
+}
+
+// WithdrawWithheldTokensFromAccounts moves the fees withheld in the token accounts to the destination
+func WithdrawWithheldTokensFromAccounts(param WithdrawWithheldTokensFromAccountsParam) types.Instruction {
+	if len(param.Sources) > 255 {
+		panic("too many source accounts")
+	}
+
+	accounts := make([]types.AccountMeta, 0, 3+len(param.Signers)+len(param.Sources))
+	accounts = append(accounts, types.AccountMeta{PubKey: param.Mint, IsSigner: false, IsWritable: false})
+	accounts = append(accounts, types.AccountMeta{PubKey: param.Destination, IsSigner: false, IsWritable: true})
+	accounts = append(accounts, types.AccountMeta{PubKey: param.Auth, IsSigner: len(param.Signers) == 0, IsWritable: false})
+	for _, signerPubkey := range param.Signers {
+		accounts = append(accounts, types.AccountMeta{PubKey: signerPubkey, IsSigner: true, IsWritable: false})
+	}
+	for _, source := range param.Sources {
+		accounts = append(accounts, types.AccountMeta{PubKey: source, IsSigner: false, IsWritable: true})
+	}
+
+	return types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts:  accounts,
+		Data: []byte{
+			byte(InstructionExtensionTransferFeeConfig),
+			byte(TransferFeeInstructionWithdrawWithheldTokensFromAccounts),
+			uint8(len(param.Sources)),
+		},
+	}
+}
+
+type HarvestWithheldTokensToMintParam struct {
+	Mint    common.PublicKey
+	Sources []common.PublicKey
+}
+
+// HarvestWithheldTokensToMint moves the fees withheld in the token accounts to the mint, anyone can call it
+func HarvestWithheldTokensToMint(param HarvestWithheldTokensToMintParam) types.Instruction {
+	accounts := make([]types.AccountMeta, 0, 1+len(param.Sources))
+	accounts = append(accounts, types.AccountMeta{PubKey: param.Mint, IsSigner: false, IsWritable: true})
+	for _, source := range param.Sources {
+		accounts = append(accounts, types.AccountMeta{PubKey: source, IsSigner: false, IsWritable: true})
+	}
+
+	return types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts:  accounts,
+		Data:      []byte{byte(InstructionExtensionTransferFeeConfig), byte(TransferFeeInstructionHarvestWithheldTokensToMint)},
+	}
+}
+
+type SetTransferFeeParam struct {
+	Mint common.PublicKey
+	// Auth is the transfer fee config authority
+	Auth                   common.PublicKey
+	Signers                []common.PublicKey
+	TransferFeeBasisPoints uint16
+	MaximumFee             uint64
+}
+
+// SetTransferFee sets the fee which takes effect two epochs later
+func SetTransferFee(param SetTransferFeeParam) types.Instruction {
+	data, err := bincode.SerializeData(struct {
+		Instruction            Instruction
+		TransferFeeInstruction TransferFeeInstruction
+		TransferFeeBasisPoints uint16
+		MaximumFee             uint64
+	}{
+		Instruction:            InstructionExtensionTransferFeeConfig,
+		TransferFeeInstruction: TransferFeeInstructionSetTransferFee,
+		TransferFeeBasisPoints: param.TransferFeeBasisPoints,
+		MaximumFee:             param.MaximumFee,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	accounts := make([]types.AccountMeta, 0, 2+len(param.Signers))
+	accounts = append(accounts, types.AccountMeta{PubKey: param.Mint, IsSigner: false, IsWritable: true})
+	accounts = append(accounts, types.AccountMeta{PubKey: param.Auth, IsSigner: len(param.Signers) == 0, IsWritable: false})
+	for _, signerPubkey := range param.Signers {
+		accounts = append(accounts, types.AccountMeta{PubKey: signerPubkey, IsSigner: true, IsWritable: false})
+	}
+
+	return types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts:  accounts,
+		Data:      data,
+	}
+}
+
+func appendPubkeyOption(data []byte, pubkey *common.PublicKey) []byte {
+	if pubkey == nil {
+		return append(data, 0)
+	}
+	data = append(data, 1)
+	return append(data, pubkey.Bytes()...)
+}
+
+// CalculateFee returns the fee charged on a transfer of the amount, rounded up and capped at the maximum fee
+func (f TransferFee) CalculateFee(amount uint64) uint64 {
+	if f.TransferFeeBasisPoints == 0 || amount == 0 {
+		return 0
+	}
+	fee, ok := ceilDiv(amount, uint64(f.TransferFeeBasisPoints), MaxFeeBasisPoints)
+	if !ok || fee > f.MaximumFee {
+		return f.MaximumFee
+	}
+	return fee
+}
+
+// CalculatePostFeeAmount returns what the destination receives from a transfer of the amount
+func (f TransferFee) CalculatePostFeeAmount(amount uint64) uint64 {
+	return amount - f.CalculateFee(amount)
+}
+
+// CalculatePreFeeAmount returns the smallest amount to transfer for the destination to receive the post fee amount
+func (f TransferFee) CalculatePreFeeAmount(postFeeAmount uint64) (uint64, error) {
+	switch {
+	case f.TransferFeeBasisPoints == 0:
+		return postFeeAmount, nil
+	case postFeeAmount == 0:
+		return 0, nil
+	case f.TransferFeeBasisPoints > MaxFeeBasisPoints:
+		return 0, ErrOverflow
+	}
+
+	maxAmount, carry := bits.Add64(postFeeAmount, f.MaximumFee, 0)
+	if f.TransferFeeBasisPoints == MaxFeeBasisPoints {
+		if carry != 0 {
+			return 0, ErrOverflow
+		}
+		return maxAmount, nil
+	}
+
+	amount, ok := ceilDiv(postFeeAmount, MaxFeeBasisPoints, MaxFeeBasisPoints-uint64(f.TransferFeeBasisPoints))
+	// the fee can't be more than the maximum fee
+	if !ok || amount-postFeeAmount >= f.MaximumFee {
+		if carry != 0 {
+			return 0, ErrOverflow
+		}
+		return maxAmount, nil
+	}
+	return amount, nil
+}
+
+// CalculateInverseFee returns the fee charged on the pre fee amount of the post fee amount
+func (f TransferFee) CalculateInverseFee(postFeeAmount uint64) (uint64, error) {
+	amount, err := f.CalculatePreFeeAmount(postFeeAmount)
+	if err != nil {
+		return 0, err
+	}
+	return f.CalculateFee(amount), nil
+}
+
+// GetEpochFee returns the fee in effect at the epoch
+func (c TransferFeeConfig) GetEpochFee(epoch uint64) TransferFee {
+	if epoch >= c.NewerTransferFee.Epoch {
+		return c.NewerTransferFee
+	}
+	return c.OlderTransferFee
+}
+
+// CalculateEpochFee returns the fee and what the destination receives from a transfer of the amount at the epoch
+func (c TransferFeeConfig) CalculateEpochFee(epoch, amount uint64) (fee uint64, postFeeAmount uint64) {
+	fee = c.GetEpochFee(epoch).CalculateFee(amount)
+	return fee, amount - fee
+}
+
+// CalculateEpochPreFeeAmount returns the amount to transfer at the epoch for the destination to receive the
+// post fee amount, and the fee to pass to TransferCheckedWithFee
+func (c TransferFeeConfig) CalculateEpochPreFeeAmount(epoch, postFeeAmount uint64) (amount uint64, fee uint64, err error) {
+	transferFee := c.GetEpochFee(epoch)
+	amount, err = transferFee.CalculatePreFeeAmount(postFeeAmount)
+	if err != nil {
+		return 0, 0, err
+	}
+	return amount, transferFee.CalculateFee(amount), nil
+}
+
+// ceilDiv returns ceil(a*b/d) in 128 bits, false if it doesn't fit in an uint64
+func ceilDiv(a, b, d uint64) (uint64, bool) {
+	hi, lo := bits.Mul64(a, b)
+	lo, carry := bits.Add64(lo, d-1, 0)
+	hi += carry
+	if hi >= d {
+		return 0, false
+	}
+	q, _ := bits.Div64(hi, lo, d)
+	return q, true
+}
@@ -0,0 +1,327 @@
+package token2022
+
+import (
+	"math"
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/pkg/pointer"
+	"github.com/labyla/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	testMint      = common.PublicKeyFromString("FtvD2ymcAFh59DGGmJkANyJzEpLDR1GLgqDrUxfe2dPm")
+	testAuthority = common.PublicKeyFromString("BkXBQ9ThbQffhmG39c2TbXW94pEmVGJAvxWk6hfxRvUJ")
+	testAccount1  = common.PublicKeyFromString("EvN4kgKmCmYzdbd5kL8Q8YgkUW5RoqMTpBczrfLExtx7")
+	testAccount2  = common.PublicKeyFromString("9qeP9DmjXAmKQc4wy133XZrQ3Fo4ejsYteA7X4YFJ3an")
+	testSigner1   = common.PublicKeyFromString("5vftMkHL72JaJG6ExQfGAsT2uGVHpRR7oTNUPMs68Y2N")
+	testSigner2   = common.PublicKeyFromString("6frdqXQAgJMyKwmZxkLYbdGjnYTvUceh6LNhkQt2siQp")
+)
+
+func concat(bs ...[]byte) []byte {
+	var b []byte
+	for _, v := range bs {
+		b = append(b, v...)
+	}
+	return b
+}
+
+func TestInitializeTransferFeeConfig(t *testing.T) {
+	tests := []struct {
+		name  string
+		param InitializeTransferFeeConfigParam
+		want  types.Instruction
+	}{
+		{
+			name: "both authorities",
+			param: InitializeTransferFeeConfigParam{
+				Mint:                       testMint,
+				TransferFeeConfigAuthority: pointer.Get(testAuthority),
+				WithdrawWithheldAuthority:  pointer.Get(testAccount1),
+				TransferFeeBasisPoints:     50,
+				MaximumFee:                 5000,
+			},
+			want: types.Instruction{
+				ProgramID: common.Token2022ProgramID,
+				Accounts: []types.AccountMeta{
+					{PubKey: testMint, IsSigner: false, IsWritable: true},
+				},
+				Data: concat(
+					[]byte{26, 0, 1}, testAuthority.Bytes(),
+					[]byte{1}, testAccount1.Bytes(),
+					[]byte{50, 0, 136, 19, 0, 0, 0, 0, 0, 0},
+				),
+			},
+		},
+		{
+			name: "no authority",
+			param: InitializeTransferFeeConfigParam{
+				Mint:                   testMint,
+				TransferFeeBasisPoints: 10000,
+				MaximumFee:             math.MaxUint64,
+			},
+			want: types.Instruction{
+				ProgramID: common.Token2022ProgramID,
+				Accounts: []types.AccountMeta{
+					{PubKey: testMint, IsSigner: false, IsWritable: true},
+				},
+				Data: []byte{26, 0, 0, 0, 16, 39, 255, 255, 255, 255, 255, 255, 255, 255},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, InitializeTransferFeeConfig(tt.param))
+		})
+	}
+}
+
+func TestTransferCheckedWithFee(t *testing.T) {
+	tests := []struct {
+		name  string
+		param TransferCheckedWithFeeParam
+		want  types.Instruction
+	}{
+		{
+			name: "single owner",
+			param: TransferCheckedWithFeeParam{
+				From:     testAccount1,
+				To:       testAccount2,
+				Mint:     testMint,
+				Auth:     testAuthority,
+				Amount:   1000000,
+				Decimals: 6,
+				Fee:      5000,
+			},
+			want: types.Instruction{
+				ProgramID: common.Token2022ProgramID,
+				Accounts: []types.AccountMeta{
+					{PubKey: testAccount1, IsSigner: false, IsWritable: true},
+					{PubKey: testMint, IsSigner: false, IsWritable: false},
+					{PubKey: testAccount2, IsSigner: false, IsWritable: true},
+					{PubKey: testAuthority, IsSigner: true, IsWritable: false},
+				},
+				Data: []byte{26, 1, 64, 66, 15, 0, 0, 0, 0, 0, 6, 136, 19, 0, 0, 0, 0, 0, 0},
+			},
+		},
+		{
+			name: "multisig",
+			param: TransferCheckedWithFeeParam{
+				From:     testAccount1,
+				To:       testAccount2,
+				Mint:     testMint,
+				Auth:     testAuthority,
+				Signers:  []common.PublicKey{testSigner1, testSigner2},
+				Amount:   1,
+				Decimals: 9,
+				Fee:      1,
+			},
+			want: types.Instruction{
+				ProgramID: common.Token2022ProgramID,
+				Accounts: []types.AccountMeta{
+					{PubKey: testAccount1, IsSigner: false, IsWritable: true},
+					{PubKey: testMint, IsSigner: false, IsWritable: false},
+					{PubKey: testAccount2, IsSigner: false, IsWritable: true},
+					{PubKey: testAuthority, IsSigner: false, IsWritable: false},
+					{PubKey: testSigner1, IsSigner: true, IsWritable: false},
+					{PubKey: testSigner2, IsSigner: true, IsWritable: false},
+				},
+				Data: []byte{26, 1, 1, 0, 0, 0, 0, 0, 0, 0, 9, 1, 0, 0, 0, 0, 0, 0, 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, TransferCheckedWithFee(tt.param))
+		})
+	}
+}
+
+func TestWithdrawWithheldTokensFromMint(t *testing.T) {
+	assert.Equal(t, types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: testMint, IsSigner: false, IsWritable: true},
+			{PubKey: testAccount1, IsSigner: false, IsWritable: true},
+			{PubKey: testAuthority, IsSigner: false, IsWritable: false},
+			{PubKey: testSigner1, IsSigner: true, IsWritable: false},
+		},
+		Data: []byte{26, 2},
+	}, WithdrawWithheldTokensFromMint(WithdrawWithheldTokensFromMintParam{
+		Mint:        testMint,
+		Destination: testAccount1,
+		Auth:        testAuthority,
+		Signers:     []common.PublicKey{testSigner1},
+	}))
+}
+
+func TestWithdrawWithheldTokensFromAccounts(t *testing.T) {
+	assert.Equal(t, types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: testMint, IsSigner: false, IsWritable: false},
+			{PubKey: testAccount1, IsSigner: false, IsWritable: true},
+			{PubKey: testAuthority, IsSigner: false, IsWritable: false},
+			{PubKey: testSigner1, IsSigner: true, IsWritable: false},
+			{PubKey: testSigner2, IsSigner: true, IsWritable: false},
+			{PubKey: testAccount2, IsSigner: false, IsWritable: true},
+		},
+		Data: []byte{26, 3, 1},
+	}, WithdrawWithheldTokensFromAccounts(WithdrawWithheldTokensFromAccountsParam{
+		Mint:        testMint,
+		Destination: testAccount1,
+		Auth:        testAuthority,
+		Signers:     []common.PublicKey{testSigner1, testSigner2},
+		Sources:     []common.PublicKey{testAccount2},
+	}))
+
+	assert.Equal(t, types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: testMint, IsSigner: false, IsWritable: false},
+			{PubKey: testAccount1, IsSigner: false, IsWritable: true},
+			{PubKey: testAuthority, IsSigner: true, IsWritable: false},
+			{PubKey: testAccount2, IsSigner: false, IsWritable: true},
+			{PubKey: testSigner1, IsSigner: false, IsWritable: true},
+		},
+		Data: []byte{26, 3, 2},
+	}, WithdrawWithheldTokensFromAccounts(WithdrawWithheldTokensFromAccountsParam{
+		Mint:        testMint,
+		Destination: testAccount1,
+		Auth:        testAuthority,
+		Sources:     []common.PublicKey{testAccount2, testSigner1},
+	}))
+}
+
+func TestHarvestWithheldTokensToMint(t *testing.T) {
+	assert.Equal(t, types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: testMint, IsSigner: false, IsWritable: true},
+			{PubKey: testAccount1, IsSigner: false, IsWritable: true},
+			{PubKey: testAccount2, IsSigner: false, IsWritable: true},
+		},
+		Data: []byte{26, 4},
+	}, HarvestWithheldTokensToMint(HarvestWithheldTokensToMintParam{
+		Mint:    testMint,
+		Sources: []common.PublicKey{testAccount1, testAccount2},
+	}))
+}
+
+func TestSetTransferFee(t *testing.T) {
+	assert.Equal(t, types.Instruction{
+		ProgramID: common.Token2022ProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: testMint, IsSigner: false, IsWritable: true},
+			{PubKey: testAuthority, IsSigner: true, IsWritable: false},
+		},
+		Data: []byte{26, 5, 100, 0, 0, 228, 11, 84, 2, 0, 0, 0},
+	}, SetTransferFee(SetTransferFeeParam{
+		Mint:                   testMint,
+		Auth:                   testAuthority,
+		TransferFeeBasisPoints: 100,
+		MaximumFee:             10000000000,
+	}))
+}
+
+func TestTransferFee_CalculateFee(t *testing.T) {
+	tests := []struct {
+		name   string
+		fee    TransferFee
+		amount uint64
+		want   uint64
+	}{
+		{name: "zero basis points", fee: TransferFee{MaximumFee: 10}, amount: 100, want: 0},
+		{name: "zero amount", fee: TransferFee{TransferFeeBasisPoints: 100, MaximumFee: 10}, amount: 0, want: 0},
+		{name: "rounded up", fee: TransferFee{TransferFeeBasisPoints: 1, MaximumFee: math.MaxUint64}, amount: 1, want: 1},
+		{name: "exact", fee: TransferFee{TransferFeeBasisPoints: 1, MaximumFee: math.MaxUint64}, amount: 10000, want: 1},
+		{name: "just above", fee: TransferFee{TransferFeeBasisPoints: 1, MaximumFee: math.MaxUint64}, amount: 10001, want: 2},
+		{name: "capped", fee: TransferFee{TransferFeeBasisPoints: 1, MaximumFee: 5000}, amount: math.MaxUint64, want: 5000},
+		{name: "full amount", fee: TransferFee{TransferFeeBasisPoints: 10000, MaximumFee: math.MaxUint64}, amount: math.MaxUint64, want: math.MaxUint64},
+		{name: "one percent", fee: TransferFee{TransferFeeBasisPoints: 100, MaximumFee: math.MaxUint64}, amount: 123456, want: 1235},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.fee.CalculateFee(tt.amount))
+			assert.Equal(t, tt.amount-tt.want, tt.fee.CalculatePostFeeAmount(tt.amount))
+		})
+	}
+}
+
+func TestTransferFee_CalculatePreFeeAmount(t *testing.T) {
+	tests := []struct {
+		name    string
+		fee     TransferFee
+		post    uint64
+		want    uint64
+		wantErr error
+	}{
+		{name: "zero basis points", fee: TransferFee{MaximumFee: 10}, post: 100, want: 100},
+		{name: "zero amount", fee: TransferFee{TransferFeeBasisPoints: 100, MaximumFee: 10}, post: 0, want: 0},
+		{name: "one percent", fee: TransferFee{TransferFeeBasisPoints: 100, MaximumFee: math.MaxUint64}, post: 9900, want: 10000},
+		{name: "rounded", fee: TransferFee{TransferFeeBasisPoints: 1, MaximumFee: math.MaxUint64}, post: 1, want: 2},
+		{name: "maximum fee", fee: TransferFee{TransferFeeBasisPoints: 5000, MaximumFee: 10}, post: 100, want: 110},
+		{name: "full fee", fee: TransferFee{TransferFeeBasisPoints: 10000, MaximumFee: 5}, post: 1, want: 6},
+		{name: "huge amount capped", fee: TransferFee{TransferFeeBasisPoints: 9999, MaximumFee: 100}, post: math.MaxUint64 - 100, want: math.MaxUint64},
+		{name: "overflow", fee: TransferFee{TransferFeeBasisPoints: 10000, MaximumFee: 1}, post: math.MaxUint64, wantErr: ErrOverflow},
+		{name: "overflow uncapped", fee: TransferFee{TransferFeeBasisPoints: 5000, MaximumFee: math.MaxUint64}, post: math.MaxUint64/2 + 1, wantErr: ErrOverflow},
+		{name: "invalid basis points", fee: TransferFee{TransferFeeBasisPoints: 10001, MaximumFee: 1}, post: 1, wantErr: ErrOverflow},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fee.CalculatePreFeeAmount(tt.post)
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+			// the destination receives exactly the post fee amount
+			assert.Equal(t, tt.post, tt.fee.CalculatePostFeeAmount(got))
+		})
+	}
+}
+
+func TestTransferFee_RoundTrip(t *testing.T) {
+	for _, fee := range []TransferFee{
+		{TransferFeeBasisPoints: 1, MaximumFee: math.MaxUint64},
+		{TransferFeeBasisPoints: 33, MaximumFee: 7},
+		{TransferFeeBasisPoints: 250, MaximumFee: 1000},
+		{TransferFeeBasisPoints: 9999, MaximumFee: math.MaxUint64},
+	} {
+		for post := uint64(1); post < 20000; post += 7 {
+			pre, err := fee.CalculatePreFeeAmount(post)
+			assert.Nil(t, err)
+			assert.Equal(t, post, fee.CalculatePostFeeAmount(pre), "fee %+v, post %v", fee, post)
+			inverse, err := fee.CalculateInverseFee(post)
+			assert.Nil(t, err)
+			assert.Equal(t, pre-post, inverse)
+		}
+	}
+}
+
+func TestTransferFeeConfig_Epoch(t *testing.T) {
+	config := TransferFeeConfig{
+		OlderTransferFee: TransferFee{Epoch: 0, TransferFeeBasisPoints: 10, MaximumFee: math.MaxUint64},
+		NewerTransferFee: TransferFee{Epoch: 100, TransferFeeBasisPoints: 50, MaximumFee: 30},
+	}
+	assert.Equal(t, config.OlderTransferFee, config.GetEpochFee(99))
+	assert.Equal(t, config.NewerTransferFee, config.GetEpochFee(100))
+
+	fee, post := config.CalculateEpochFee(99, 10000)
+	assert.Equal(t, uint64(10), fee)
+	assert.Equal(t, uint64(9990), post)
+	fee, post = config.CalculateEpochFee(100, 10000)
+	assert.Equal(t, uint64(30), fee)
+	assert.Equal(t, uint64(9970), post)
+
+	amount, fee, err := config.CalculateEpochPreFeeAmount(99, 9990)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(10000), amount)
+	assert.Equal(t, uint64(10), fee)
+	amount, fee, err = config.CalculateEpochPreFeeAmount(101, 9970)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(10000), amount)
+	assert.Equal(t, uint64(30), fee)
+}
@@ -0,0 +1,401 @@
+package token2022
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/types"
+)
+
+// ExtraAccountMetasSeed is the seed of the account where a transfer hook program keeps the extra accounts of a mint
+const ExtraAccountMetasSeed = "extra-account-metas"
+
+// ExtraAccountMetaSize is the packed size of an ExtraAccountMeta
+const ExtraAccountMetaSize = 35
+
+// transferHookExecuteDiscriminator is sha256("spl-transfer-hook-interface:execute")[:8], it is also the TLV type
+// of the extra accounts of the execute instruction
+var transferHookExecuteDiscriminator = [8]byte{105, 37, 101, 197, 75, 251, 102, 26}
+
+// the address of an extra account is a pubkey, a PDA of the hook program, a pubkey stored in some data,
+// or a PDA of the program at ExtraAccountMetaDiscriminatorExternalPDA + the index of the program account
+const (
+	ExtraAccountMetaDiscriminatorPubkey      uint8 = 0
+	ExtraAccountMetaDiscriminatorPDA         uint8 = 1
+	ExtraAccountMetaDiscriminatorPubkeyData  uint8 = 2
+	ExtraAccountMetaDiscriminatorExternalPDA uint8 = 128
+)
+
+type ExtraAccountMeta struct {
+	Discriminator uint8
+	// AddressConfig is the pubkey, the packed seeds, or where the pubkey is stored
+	AddressConfig [32]byte
+	IsSigner      bool
+	IsWritable    bool
+}
+
+type SeedType uint8
+
+const (
+	SeedTypeUninitialized SeedType = iota
+	SeedTypeLiteral
+	SeedTypeInstructionData
+	SeedTypeAccountKey
+	SeedTypeAccountData
+)
+
+// Seed is a seed of a PDA of an extra account
+type Seed struct {
+	Type SeedType
+	// Bytes is the literal seed
+	Bytes []byte
+	// Index is the offset in the instruction data, or the index of the account for an account key
+	Index uint8
+	// AccountIndex and DataIndex locate a seed in the data of an account
+	AccountIndex uint8
+	DataIndex    uint8
+	// Length is the length of a seed in the instruction data or in the data of an account
+	Length uint8
+}
+
+// PubkeyDataType tells where a pubkey of ExtraAccountMetaDiscriminatorPubkeyData is stored
+type PubkeyDataType uint8
+
+const (
+	PubkeyDataTypeUninitialized PubkeyDataType = iota
+	PubkeyDataTypeInstructionData
+	PubkeyDataTypeAccountData
+)
+
+// AccountDataFetcher returns the data of an account
+type AccountDataFetcher func(ctx context.Context, pubkey common.PublicKey) ([]byte, error)
+
+func FindExtraAccountMetasAddress(mint, programID common.PublicKey) (common.PublicKey, uint8, error) {
+	return common.FindProgramAddress([][]byte{[]byte(ExtraAccountMetasSeed), mint.Bytes()}, programID)
+}
+
+func NewExtraAccountMeta(pubkey common.PublicKey, isSigner, isWritable bool) ExtraAccountMeta {
+	return ExtraAccountMeta{
+		Discriminator: ExtraAccountMetaDiscriminatorPubkey,
+		AddressConfig: pubkey,
+		IsSigner:      isSigner,
+		IsWritable:    isWritable,
+	}
+}
+
+// NewExtraAccountMetaWithSeeds is a PDA of the transfer hook program
+func NewExtraAccountMetaWithSeeds(seeds []Seed, isSigner, isWritable bool) (ExtraAccountMeta, error) {
+	config, err := packSeeds(seeds)
+	if err != nil {
+		return ExtraAccountMeta{}, err
+	}
+	return ExtraAccountMeta{
+		Discriminator: ExtraAccountMetaDiscriminatorPDA,
+		AddressConfig: config,
+		IsSigner:      isSigner,
+		IsWritable:    isWritable,
+	}, nil
+}
+
+// NewExternalPDAExtraAccountMeta is a PDA of the program at the account index
+func NewExternalPDAExtraAccountMeta(programIndex uint8, seeds []Seed, isSigner, isWritable bool) (ExtraAccountMeta, error) {
+	if programIndex >= 128 {
+		return ExtraAccountMeta{}, fmt.Errorf("%w: program index %v", ErrInvalidExtraAccountMeta, programIndex)
+	}
+	config, err := packSeeds(seeds)
+	if err != nil {
+		return ExtraAccountMeta{}, err
+	}
+	return ExtraAccountMeta{
+		Discriminator: ExtraAccountMetaDiscriminatorExternalPDA + programIndex,
+		AddressConfig: config,
+		IsSigner:      isSigner,
+		IsWritable:    isWritable,
+	}, nil
+}
+
+// DeserializeExtraAccountMetaList returns the extra accounts of the execute instruction from the
+// account at FindExtraAccountMetasAddress
+func DeserializeExtraAccountMetaList(data []byte) ([]ExtraAccountMeta, error) {
+	// TLV entries of an 8 bytes type and a 4 bytes length
+	offset := 0
+	for len(data)-offset >= 12 {
+		length := int(binary.LittleEndian.Uint32(data[offset+8 : offset+12]))
+		if length > len(data)-offset-12 {
+			return nil, ErrInvalidAccountDataSize
+		}
+		value := data[offset+12 : offset+12+length]
+		if bytes.Equal(data[offset:offset+8], transferHookExecuteDiscriminator[:]) {
+			return deserializeExtraAccountMetas(value)
+		}
+		offset += 12 + length
+	}
+	return nil, ErrExtraAccountMetasNotFound
+}
+
+func deserializeExtraAccountMetas(data []byte) ([]ExtraAccountMeta, error) {
+	if len(data) < 4 {
+		return nil, ErrInvalidAccountDataSize
+	}
+	count := binary.LittleEndian.Uint32(data[0:4])
+	if uint64(count) > uint64(len(data)-4)/ExtraAccountMetaSize {
+		return nil, ErrInvalidAccountDataSize
+	}
+
+	metas := make([]ExtraAccountMeta, 0, count)
+	for i := 0; i < int(count); i++ {
+		b := data[4+i*ExtraAccountMetaSize : 4+(i+1)*ExtraAccountMetaSize]
+		if b[33] > 1 || b[34] > 1 {
+			return nil, fmt.Errorf("%w: invalid bool", ErrInvalidExtraAccountMeta)
+		}
+		meta := ExtraAccountMeta{
+			Discriminator: b[0],
+			IsSigner:      b[33] == 1,
+			IsWritable:    b[34] == 1,
+		}
+		copy(meta.AddressConfig[:], b[1:33])
+		metas = append(metas, meta)
+	}
+	return metas, nil
+}
+
+// Seeds unpacks the seeds of a PDA
+func (m ExtraAccountMeta) Seeds() ([]Seed, error) {
+	if m.Discriminator != ExtraAccountMetaDiscriminatorPDA && m.Discriminator < ExtraAccountMetaDiscriminatorExternalPDA {
+		return nil, fmt.Errorf("%w: not a PDA", ErrInvalidExtraAccountMeta)
+	}
+
+	config := m.AddressConfig[:]
+	var seeds []Seed
+	for i := 0; i < len(config); {
+		seed := Seed{Type: SeedType(config[i])}
+		rest := config[i+1:]
+		switch seed.Type {
+		case SeedTypeUninitialized:
+			return seeds, nil
+		case SeedTypeLiteral:
+			if len(rest) < 1 || int(rest[0]) > len(rest)-1 {
+				return nil, fmt.Errorf("%w: literal seed too long", ErrInvalidExtraAccountMeta)
+			}
+			seed.Bytes = append([]byte{}, rest[1:1+rest[0]]...)
+			i += 2 + len(seed.Bytes)
+		case SeedTypeInstructionData:
+			if len(rest) < 2 {
+				return nil, fmt.Errorf("%w: truncated seed", ErrInvalidExtraAccountMeta)
+			}
+			seed.Index, seed.Length = rest[0], rest[1]
+			i += 3
+		case SeedTypeAccountKey:
+			if len(rest) < 1 {
+				return nil, fmt.Errorf("%w: truncated seed", ErrInvalidExtraAccountMeta)
+			}
+			seed.Index = rest[0]
+			i += 2
+		case SeedTypeAccountData:
+			if len(rest) < 3 {
+				return nil, fmt.Errorf("%w: truncated seed", ErrInvalidExtraAccountMeta)
+			}
+			seed.AccountIndex, seed.DataIndex, seed.Length = rest[0], rest[1], rest[2]
+			i += 4
+		default:
+			return nil, fmt.Errorf("%w: unknown seed type %v", ErrInvalidExtraAccountMeta, seed.Type)
+		}
+		seeds = append(seeds, seed)
+	}
+	return seeds, nil
+}
+
+func packSeeds(seeds []Seed) ([32]byte, error) {
+	var b []byte
+	for _, seed := range seeds {
+		switch seed.Type {
+		case SeedTypeLiteral:
+			if len(seed.Bytes) > common.MaxSeedLength {
+				return [32]byte{}, fmt.Errorf("%w: literal seed too long", ErrInvalidExtraAccountMeta)
+			}
+			b = append(b, byte(seed.Type), uint8(len(seed.Bytes)))
+			b = append(b, seed.Bytes...)
+		case SeedTypeInstructionData:
+			b = append(b, byte(seed.Type), seed.Index, seed.Length)
+		case SeedTypeAccountKey:
+			b = append(b, byte(seed.Type), seed.Index)
+		case SeedTypeAccountData:
+			b = append(b, byte(seed.Type), seed.AccountIndex, seed.DataIndex, seed.Length)
+		default:
+			return [32]byte{}, fmt.Errorf("%w: unknown seed type %v", ErrInvalidExtraAccountMeta, seed.Type)
+		}
+	}
+	if len(b) > 32 {
+		return [32]byte{}, fmt.Errorf("%w: seeds don't fit in 32 bytes", ErrInvalidExtraAccountMeta)
+	}
+	var config [32]byte
+	copy(config[:], b)
+	return config, nil
+}
+
+// ResolveExtraAccountMetas returns the accounts the metas resolve to for the instruction. a meta can refer to
+// the accounts of the instruction and to the ones resolved before it. an account which is already in the
+// instruction doesn't get more privileges than it has there.
+func ResolveExtraAccountMetas(ctx context.Context, instruction types.Instruction, metas []ExtraAccountMeta, fetch AccountDataFetcher) ([]types.AccountMeta, error) {
+	accounts := append([]types.AccountMeta{}, instruction.Accounts...)
+	for i, meta := range metas {
+		pubkey, err := resolveExtraAccountMeta(ctx, meta, instruction.ProgramID, instruction.Data, accounts, fetch)
+		if err != nil {
+			return nil, fmt.Errorf("%w %v: %v", ErrExtraAccountMetaResolution, i, err)
+		}
+
+		accountMeta := types.AccountMeta{PubKey: pubkey, IsSigner: meta.IsSigner, IsWritable: meta.IsWritable}
+		found, isSigner, isWritable := false, false, false
+		for _, account := range accounts {
+			if account.PubKey == pubkey {
+				found = true
+				isSigner = isSigner || account.IsSigner
+				isWritable = isWritable || account.IsWritable
+			}
+		}
+		if found {
+			accountMeta.IsSigner = accountMeta.IsSigner && isSigner
+			accountMeta.IsWritable = accountMeta.IsWritable && isWritable
+		}
+		accounts = append(accounts, accountMeta)
+	}
+	return accounts[len(instruction.Accounts):], nil
+}
+
+func resolveExtraAccountMeta(ctx context.Context, meta ExtraAccountMeta, programID common.PublicKey, data []byte, accounts []types.AccountMeta, fetch AccountDataFetcher) (common.PublicKey, error) {
+	accountData := func(index uint8) ([]byte, error) {
+		if int(index) >= len(accounts) {
+			return nil, fmt.Errorf("account %v not found", index)
+		}
+		return fetch(ctx, accounts[index].PubKey)
+	}
+
+	switch {
+	case meta.Discriminator == ExtraAccountMetaDiscriminatorPubkey:
+		return meta.AddressConfig, nil
+	case meta.Discriminator == ExtraAccountMetaDiscriminatorPubkeyData:
+		var b []byte
+		var offset int
+		switch PubkeyDataType(meta.AddressConfig[0]) {
+		case PubkeyDataTypeInstructionData:
+			b, offset = data, int(meta.AddressConfig[1])
+		case PubkeyDataTypeAccountData:
+			var err error
+			if b, err = accountData(meta.AddressConfig[1]); err != nil {
+				return common.PublicKey{}, err
+			}
+			offset = int(meta.AddressConfig[2])
+		default:
+			return common.PublicKey{}, fmt.Errorf("unknown pubkey data type %v", meta.AddressConfig[0])
+		}
+		if offset+common.PublicKeyLength > len(b) {
+			return common.PublicKey{}, fmt.Errorf("pubkey at %v out of range", offset)
+		}
+		return common.PublicKeyFromBytes(b[offset : offset+common.PublicKeyLength]), nil
+	case meta.Discriminator == ExtraAccountMetaDiscriminatorPDA || meta.Discriminator >= ExtraAccountMetaDiscriminatorExternalPDA:
+		if meta.Discriminator >= ExtraAccountMetaDiscriminatorExternalPDA {
+			index := int(meta.Discriminator - ExtraAccountMetaDiscriminatorExternalPDA)
+			if index >= len(accounts) {
+				return common.PublicKey{}, fmt.Errorf("program account %v not found", index)
+			}
+			programID = accounts[index].PubKey
+		}
+
+		seeds, err := meta.Seeds()
+		if err != nil {
+			return common.PublicKey{}, err
+		}
+		pdaSeeds := make([][]byte, 0, len(seeds))
+		for _, seed := range seeds {
+			switch seed.Type {
+			case SeedTypeLiteral:
+				pdaSeeds = append(pdaSeeds, seed.Bytes)
+			case SeedTypeInstructionData:
+				if int(seed.Index)+int(seed.Length) > len(data) {
+					return common.PublicKey{}, fmt.Errorf("instruction data too small for a seed at %v", seed.Index)
+				}
+				pdaSeeds = append(pdaSeeds, data[seed.Index:seed.Index+seed.Length])
+			case SeedTypeAccountKey:
+				if int(seed.Index) >= len(accounts) {
+					return common.PublicKey{}, fmt.Errorf("account %v not found", seed.Index)
+				}
+				pdaSeeds = append(pdaSeeds, accounts[seed.Index].PubKey.Bytes())
+			case SeedTypeAccountData:
+				b, err := accountData(seed.AccountIndex)
+				if err != nil {
+					return common.PublicKey{}, err
+				}
+				if int(seed.DataIndex)+int(seed.Length) > len(b) {
+					return common.PublicKey{}, fmt.Errorf("data of account %v too small for a seed at %v", seed.AccountIndex, seed.DataIndex)
+				}
+				pdaSeeds = append(pdaSeeds, b[seed.DataIndex:seed.DataIndex+seed.Length])
+			}
+		}
+		pubkey, _, err := common.FindProgramAddress(pdaSeeds, programID)
+		return pubkey, err
+	}
+	return common.PublicKey{}, fmt.Errorf("unknown discriminator %v", meta.Discriminator)
+}
+
+// AddTransferHookAccounts returns the TransferChecked or TransferCheckedWithFee instruction with the accounts the
+// transfer hook program of the mint needs: the resolved extra accounts, the program and the extra account metas account.
+func AddTransferHookAccounts(ctx context.Context, instruction types.Instruction, hookProgramID common.PublicKey, fetch AccountDataFetcher) (types.Instruction, error) {
+	var amount []byte
+	switch data := instruction.Data; {
+	case instruction.ProgramID != common.Token2022ProgramID || len(instruction.Accounts) < 4:
+	case len(data) == 10 && Instruction(data[0]) == InstructionTransferChecked:
+		amount = data[1:9]
+	case len(data) == 19 && Instruction(data[0]) == InstructionExtensionTransferFeeConfig &&
+		TransferFeeInstruction(data[1]) == TransferFeeInstructionTransferCheckedWithFee:
+		amount = data[2:10]
+	}
+	if amount == nil {
+		return types.Instruction{}, fmt.Errorf("%w: not a checked transfer", ErrInvalidInstruction)
+	}
+
+	extraAccountMetas, _, err := FindExtraAccountMetasAddress(instruction.Accounts[1].PubKey, hookProgramID)
+	if err != nil {
+		return types.Instruction{}, err
+	}
+	data, err := fetch(ctx, extraAccountMetas)
+	if err != nil {
+		return types.Instruction{}, err
+	}
+	metas, err := DeserializeExtraAccountMetaList(data)
+	if err != nil {
+		return types.Instruction{}, err
+	}
+
+	// the metas refer to the accounts and the data of the execute instruction of the hook program
+	execute := types.Instruction{
+		ProgramID: hookProgramID,
+		Accounts: []types.AccountMeta{
+			{PubKey: instruction.Accounts[0].PubKey, IsSigner: false, IsWritable: false},
+			{PubKey: instruction.Accounts[1].PubKey, IsSigner: false, IsWritable: false},
+			{PubKey: instruction.Accounts[2].PubKey, IsSigner: false, IsWritable: false},
+			{PubKey: instruction.Accounts[3].PubKey, IsSigner: false, IsWritable: false},
+			{PubKey: extraAccountMetas, IsSigner: false, IsWritable: false},
+		},
+		Data: append(append([]byte{}, transferHookExecuteDiscriminator[:]...), amount...),
+	}
+	extraAccounts, err := ResolveExtraAccountMetas(ctx, execute, metas, fetch)
+	if err != nil {
+		return types.Instruction{}, err
+	}
+
+	accounts := make([]types.AccountMeta, 0, len(instruction.Accounts)+len(extraAccounts)+2)
+	accounts = append(accounts, instruction.Accounts...)
+	accounts = append(accounts, extraAccounts...)
+	accounts = append(accounts,
+		types.AccountMeta{PubKey: hookProgramID, IsSigner: false, IsWritable: false},
+		types.AccountMeta{PubKey: extraAccountMetas, IsSigner: false, IsWritable: false},
+	)
+	return types.Instruction{
+		ProgramID: instruction.ProgramID,
+		Accounts:  accounts,
+		Data:      instruction.Data,
+	}, nil
+}
@@ -0,0 +1,221 @@
+package token2022
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testHookProgramID = common.PublicKeyFromString("8B1ytD3WMxoYQFJDk5MFn5fBgR3S7RBbVmKvfbSCuLYf")
+
+func serializeExtraAccountMetaList(metas []ExtraAccountMeta) []byte {
+	value := binary.LittleEndian.AppendUint32(nil, uint32(len(metas)))
+	for _, meta := range metas {
+		value = append(value, meta.Discriminator)
+		value = append(value, meta.AddressConfig[:]...)
+		value = append(value, boolByte(meta.IsSigner), boolByte(meta.IsWritable))
+	}
+	return concat(
+		transferHookExecuteDiscriminator[:],
+		binary.LittleEndian.AppendUint32(nil, uint32(len(value))),
+		value,
+	)
+}
+
+func mapFetcher(accounts map[common.PublicKey][]byte) AccountDataFetcher {
+	return func(ctx context.Context, pubkey common.PublicKey) ([]byte, error) {
+		data, ok := accounts[pubkey]
+		if !ok {
+			return nil, errors.New("account not found")
+		}
+		return data, nil
+	}
+}
+
+func TestExtraAccountMetaSeeds(t *testing.T) {
+	seeds := []Seed{
+		{Type: SeedTypeLiteral, Bytes: []byte("seed")},
+		{Type: SeedTypeInstructionData, Index: 8, Length: 8},
+		{Type: SeedTypeAccountKey, Index: 1},
+		{Type: SeedTypeAccountData, AccountIndex: 0, DataIndex: 32, Length: 32},
+	}
+	meta, err := NewExtraAccountMetaWithSeeds(seeds, false, true)
+	require.Nil(t, err)
+	assert.Equal(t, ExtraAccountMetaDiscriminatorPDA, meta.Discriminator)
+	assert.Equal(t, [32]byte{1, 4, 's', 'e', 'e', 'd', 2, 8, 8, 3, 1, 4, 0, 32, 32}, meta.AddressConfig)
+
+	got, err := meta.Seeds()
+	require.Nil(t, err)
+	assert.Equal(t, seeds, got)
+
+	meta, err = NewExternalPDAExtraAccountMeta(6, seeds[2:3], false, false)
+	require.Nil(t, err)
+	assert.Equal(t, uint8(134), meta.Discriminator)
+	got, err = meta.Seeds()
+	require.Nil(t, err)
+	assert.Equal(t, seeds[2:3], got)
+
+	_, err = NewExtraAccountMetaWithSeeds([]Seed{{Type: SeedTypeLiteral, Bytes: make([]byte, 31)}}, false, false)
+	assert.ErrorIs(t, err, ErrInvalidExtraAccountMeta)
+	_, err = NewExternalPDAExtraAccountMeta(128, nil, false, false)
+	assert.ErrorIs(t, err, ErrInvalidExtraAccountMeta)
+	_, err = NewExtraAccountMeta(testAccount1, false, false).Seeds()
+	assert.ErrorIs(t, err, ErrInvalidExtraAccountMeta)
+	_, err = ExtraAccountMeta{Discriminator: 1, AddressConfig: [32]byte{1, 40}}.Seeds()
+	assert.ErrorIs(t, err, ErrInvalidExtraAccountMeta)
+	_, err = ExtraAccountMeta{Discriminator: 1, AddressConfig: [32]byte{9}}.Seeds()
+	assert.ErrorIs(t, err, ErrInvalidExtraAccountMeta)
+}
+
+func TestDeserializeExtraAccountMetaList(t *testing.T) {
+	metas := []ExtraAccountMeta{
+		NewExtraAccountMeta(testAccount1, true, false),
+		NewExtraAccountMeta(testAccount2, false, true),
+	}
+	data := serializeExtraAccountMetaList(metas)
+
+	got, err := DeserializeExtraAccountMetaList(data)
+	require.Nil(t, err)
+	assert.Equal(t, metas, got)
+
+	// another entry first
+	got, err = DeserializeExtraAccountMetaList(concat([]byte{1, 2, 3, 4, 5, 6, 7, 8, 2, 0, 0, 0, 9, 9}, data))
+	require.Nil(t, err)
+	assert.Equal(t, metas, got)
+
+	got, err = DeserializeExtraAccountMetaList(serializeExtraAccountMetaList(nil))
+	require.Nil(t, err)
+	assert.Empty(t, got)
+
+	_, err = DeserializeExtraAccountMetaList([]byte{1, 2, 3, 4, 5, 6, 7, 8, 0, 0, 0, 0})
+	assert.ErrorIs(t, err, ErrExtraAccountMetasNotFound)
+	_, err = DeserializeExtraAccountMetaList(data[:len(data)-1])
+	assert.ErrorIs(t, err, ErrInvalidAccountDataSize)
+
+	invalid := append([]byte{}, data...)
+	invalid[len(invalid)-1] = 2
+	_, err = DeserializeExtraAccountMetaList(invalid)
+	assert.ErrorIs(t, err, ErrInvalidExtraAccountMeta)
+}
+
+func TestAddTransferHookAccounts(t *testing.T) {
+	owner := common.PublicKeyFromString("9aE476sH92Vz7DMPyq5WLPkrKWivxeuTKEFKd2sZZcde")
+	sourceData := make([]byte, BaseAccountLength)
+	copy(sourceData[32:64], owner.Bytes())
+	destinationData := make([]byte, BaseAccountLength)
+	copy(destinationData[0:32], testMint.Bytes())
+
+	must := func(meta ExtraAccountMeta, err error) ExtraAccountMeta {
+		require.Nil(t, err)
+		return meta
+	}
+	metas := []ExtraAccountMeta{
+		// 5
+		NewExtraAccountMeta(common.MemoProgramID, false, false),
+		// 6, a counter of the amount sent from the source
+		must(NewExtraAccountMetaWithSeeds([]Seed{
+			{Type: SeedTypeLiteral, Bytes: []byte("counter")},
+			{Type: SeedTypeAccountKey, Index: 0},
+			{Type: SeedTypeInstructionData, Index: 8, Length: 8},
+		}, false, true)),
+		// 7, the owner of the source
+		{Discriminator: ExtraAccountMetaDiscriminatorPubkeyData, AddressConfig: [32]byte{2, 0, 32}},
+		// 8, a PDA of the program at 5 from the destination data and the account at 7
+		must(NewExternalPDAExtraAccountMeta(5, []Seed{
+			{Type: SeedTypeAccountData, AccountIndex: 2, DataIndex: 0, Length: 32},
+			{Type: SeedTypeAccountKey, Index: 7},
+		}, false, false)),
+		// 9, the execute instruction only has read only accounts
+		NewExtraAccountMeta(testAccount1, true, true),
+	}
+
+	extraAccountMetas, _, err := FindExtraAccountMetasAddress(testMint, testHookProgramID)
+	require.Nil(t, err)
+	fetch := mapFetcher(map[common.PublicKey][]byte{
+		extraAccountMetas: serializeExtraAccountMetaList(metas),
+		testAccount1:      sourceData,
+		testAccount2:      destinationData,
+	})
+
+	counter, _, err := common.FindProgramAddress([][]byte{
+		[]byte("counter"),
+		testAccount1.Bytes(),
+		{0x40, 0x42, 0x0f, 0, 0, 0, 0, 0},
+	}, testHookProgramID)
+	require.Nil(t, err)
+	external, _, err := common.FindProgramAddress([][]byte{testMint.Bytes(), owner.Bytes()}, common.MemoProgramID)
+	require.Nil(t, err)
+
+	wantExtraAccounts := []types.AccountMeta{
+		{PubKey: common.MemoProgramID, IsSigner: false, IsWritable: false},
+		{PubKey: counter, IsSigner: false, IsWritable: true},
+		{PubKey: owner, IsSigner: false, IsWritable: false},
+		{PubKey: external, IsSigner: false, IsWritable: false},
+		{PubKey: testAccount1, IsSigner: false, IsWritable: false},
+		{PubKey: testHookProgramID, IsSigner: false, IsWritable: false},
+		{PubKey: extraAccountMetas, IsSigner: false, IsWritable: false},
+	}
+
+	t.Run("transfer checked", func(t *testing.T) {
+		instruction := TransferChecked(TransferCheckedParam{
+			From:     testAccount1,
+			To:       testAccount2,
+			Mint:     testMint,
+			Auth:     owner,
+			Amount:   1_000_000,
+			Decimals: 6,
+		})
+		got, err := AddTransferHookAccounts(context.Background(), instruction, testHookProgramID, fetch)
+		require.Nil(t, err)
+		assert.Equal(t, instruction.ProgramID, got.ProgramID)
+		assert.Equal(t, instruction.Data, got.Data)
+		assert.Equal(t, append(instruction.Accounts[:4:4], wantExtraAccounts...), got.Accounts)
+		assert.Len(t, instruction.Accounts, 4)
+	})
+
+	t.Run("transfer checked with fee", func(t *testing.T) {
+		instruction := TransferCheckedWithFee(TransferCheckedWithFeeParam{
+			From:     testAccount1,
+			To:       testAccount2,
+			Mint:     testMint,
+			Auth:     testAuthority,
+			Signers:  []common.PublicKey{testSigner1},
+			Amount:   1_000_000,
+			Decimals: 6,
+			Fee:      100,
+		})
+		got, err := AddTransferHookAccounts(context.Background(), instruction, testHookProgramID, fetch)
+		require.Nil(t, err)
+		assert.Equal(t, append(instruction.Accounts[:5:5], wantExtraAccounts...), got.Accounts)
+	})
+
+	t.Run("not a checked transfer", func(t *testing.T) {
+		_, err := AddTransferHookAccounts(context.Background(), Transfer(TransferParam{
+			From:   testAccount1,
+			To:     testAccount2,
+			Auth:   owner,
+			Amount: 1,
+		}), testHookProgramID, fetch)
+		assert.ErrorIs(t, err, ErrInvalidInstruction)
+	})
+
+	t.Run("missing account data", func(t *testing.T) {
+		_, err := AddTransferHookAccounts(context.Background(), TransferChecked(TransferCheckedParam{
+			From:     testAccount2,
+			To:       testAccount1,
+			Mint:     testMint,
+			Auth:     owner,
+			Amount:   1,
+			Decimals: 6,
+		}), testHookProgramID, mapFetcher(map[common.PublicKey][]byte{
+			extraAccountMetas: serializeExtraAccountMetaList(metas),
+		}))
+		assert.ErrorIs(t, err, ErrExtraAccountMetaResolution)
+	})
+}
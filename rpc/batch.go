@@ -0,0 +1,177 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrBatchNotSent         = errors.New("rpc: batch has not been sent")
+	ErrBatchMissingResponse = errors.New("rpc: no response for the request in batch")
+)
+
+// Batch queues several calls and sends them in a single http request
+type Batch struct {
+	client   *RpcClient
+	requests []JsonRpcRequest
+	results  []json.RawMessage
+	sent     bool
+}
+
+// BatchCall is a handle of a queued call. its result is available after the batch is sent.
+type BatchCall[T any] struct {
+	batch *Batch
+	index int
+}
+
+// NewBatch creates an empty batch which will be sent by the client
+func (c *RpcClient) NewBatch() *Batch {
+	return &Batch{client: c}
+}
+
+// BatchAdd queues a call into the batch. params follows the same layout as `Call`,
+// the first one is the method name.
+func BatchAdd[T any](b *Batch, params ...any) BatchCall[T] {
+	// ids start from 1 and are unique in the batch
+	id := uint64(len(b.requests) + 1)
+	req := newJsonRpcRequest(id, params)
+	for index := range b.client.modifiers.payload {
+		b.client.modifiers.payload[index](&req)
+	}
+	req.Id = id
+	b.requests = append(b.requests, req)
+	return BatchCall[T]{batch: b, index: len(b.requests) - 1}
+}
+
+// Len returns the number of queued calls
+func (b *Batch) Len() int {
+	return len(b.requests)
+}
+
+// Send posts all queued calls in one http request. responses are matched by id
+// so the order returned by the node doesn't matter.
+func (b *Batch) Send(ctx context.Context) error {
+	if len(b.requests) == 0 {
+		b.sent = true
+		return nil
+	}
+
+	j, err := json.Marshal(b.requests)
+	if err != nil {
+		return fmt.Errorf("rpc: failed to prepare payload, err: %v", err)
+	}
+
+	idempotent := true
+	for _, req := range b.requests {
+		idempotent = idempotent && isIdempotent(req.Method)
+	}
+
+	body, err := b.client.post(ctx, j, idempotent)
+	if err != nil {
+		return fmt.Errorf("rpc: call error, err: %v, body: %v", err, string(body))
+	}
+
+	var responses []json.RawMessage
+	if err := json.Unmarshal(body, &responses); err != nil {
+		// some nodes reply a single error object when the whole batch is rejected
+		var single JsonRpcResponse[json.RawMessage]
+		if json.Unmarshal(body, &single) == nil && single.Error != nil {
+			return single.Error
+		}
+		return fmt.Errorf("rpc: failed to json decode body, err: %v", err)
+	}
+
+	idToIndex := make(map[uint64]int, len(b.requests))
+	for i, req := range b.requests {
+		idToIndex[req.Id] = i
+	}
+
+	b.results = make([]json.RawMessage, len(b.requests))
+	for _, response := range responses {
+		var header struct {
+			Id *uint64 `json:"id"`
+		}
+		if err := json.Unmarshal(response, &header); err != nil || header.Id == nil {
+			continue
+		}
+		if i, ok := idToIndex[*header.Id]; ok {
+			b.results[i] = response
+		}
+	}
+	b.sent = true
+
+	return nil
+}
+
+// Result returns the response of the call. the rpc error, if any, is in the `Error` field
+// the same as a single call.
+func (c BatchCall[T]) Result() (JsonRpcResponse[T], error) {
+	var output JsonRpcResponse[T]
+	if !c.batch.sent {
+		return output, ErrBatchNotSent
+	}
+	if c.index >= len(c.batch.results) || c.batch.results[c.index] == nil {
+		return output, fmt.Errorf("%w, method: %v", ErrBatchMissingResponse, c.batch.requests[c.index].Method)
+	}
+	if err := json.Unmarshal(c.batch.results[c.index], &output); err != nil {
+		return output, fmt.Errorf("rpc: failed to json decode body, err: %v", err)
+	}
+	return output, nil
+}
+
+// GetBalance queues a `getBalance` call
+func (b *Batch) GetBalance(base58Addr string) BatchCall[ValueWithContext[uint64]] {
+	return BatchAdd[ValueWithContext[uint64]](b, "getBalance", base58Addr)
+}
+
+// GetBalanceWithConfig queues a `getBalance` call
+func (b *Batch) GetBalanceWithConfig(base58Addr string, cfg GetBalanceConfig) BatchCall[ValueWithContext[uint64]] {
+	return BatchAdd[ValueWithContext[uint64]](b, "getBalance", base58Addr, cfg)
+}
+
+// GetAccountInfo queues a `getAccountInfo` call
+func (b *Batch) GetAccountInfo(base58Addr string) BatchCall[ValueWithContext[AccountInfo]] {
+	return BatchAdd[ValueWithContext[AccountInfo]](b, "getAccountInfo", base58Addr)
+}
+
+// GetAccountInfoWithConfig queues a `getAccountInfo` call
+func (b *Batch) GetAccountInfoWithConfig(base58Addr string, cfg GetAccountInfoConfig) BatchCall[ValueWithContext[AccountInfo]] {
+	return BatchAdd[ValueWithContext[AccountInfo]](b, "getAccountInfo", base58Addr, cfg)
+}
+
+// GetMultipleAccountsWithConfig queues a `getMultipleAccounts` call
+func (b *Batch) GetMultipleAccountsWithConfig(base58Addrs []string, cfg GetMultipleAccountsConfig) BatchCall[ValueWithContext[[]AccountInfo]] {
+	return BatchAdd[ValueWithContext[[]AccountInfo]](b, "getMultipleAccounts", base58Addrs, cfg)
+}
+
+// GetSignatureStatuses queues a `getSignatureStatuses` call
+func (b *Batch) GetSignatureStatuses(signatures []string) BatchCall[ValueWithContext[SignatureStatuses]] {
+	return BatchAdd[ValueWithContext[SignatureStatuses]](b, "getSignatureStatuses", signatures)
+}
+
+// GetSignatureStatusesWithConfig queues a `getSignatureStatuses` call
+func (b *Batch) GetSignatureStatusesWithConfig(signatures []string, cfg GetSignatureStatusesConfig) BatchCall[ValueWithContext[SignatureStatuses]] {
+	return BatchAdd[ValueWithContext[SignatureStatuses]](b, "getSignatureStatuses", signatures, cfg)
+}
+
+// GetTokenAccountBalance queues a `getTokenAccountBalance` call
+func (b *Batch) GetTokenAccountBalance(base58Addr string) BatchCall[ValueWithContext[TokenAccountBalance]] {
+	return BatchAdd[ValueWithContext[TokenAccountBalance]](b, "getTokenAccountBalance", base58Addr)
+}
+
+// GetTokenAccountBalanceWithConfig queues a `getTokenAccountBalance` call
+func (b *Batch) GetTokenAccountBalanceWithConfig(base58Addr string, cfg GetTokenAccountBalanceConfig) BatchCall[ValueWithContext[TokenAccountBalance]] {
+	return BatchAdd[ValueWithContext[TokenAccountBalance]](b, "getTokenAccountBalance", base58Addr, cfg)
+}
+
+// GetSlot queues a `getSlot` call
+func (b *Batch) GetSlot() BatchCall[uint64] {
+	return BatchAdd[uint64](b, "getSlot")
+}
+
+// GetLatestBlockhash queues a `getLatestBlockhash` call
+func (b *Batch) GetLatestBlockhash() BatchCall[ValueWithContext[GetLatestBlockhashValue]] {
+	return BatchAdd[ValueWithContext[GetLatestBlockhashValue]](b, "getLatestBlockhash")
+}
@@ -0,0 +1,102 @@
+package rpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/internal/client_test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatch(t *testing.T) {
+	type results struct {
+		Balance     JsonRpcResponse[ValueWithContext[uint64]]
+		BalanceErr  error
+		Statuses    JsonRpcResponse[ValueWithContext[SignatureStatuses]]
+		StatusesErr error
+		Slot        JsonRpcResponse[uint64]
+		SlotErr     error
+	}
+	client_test.TestAll(
+		t,
+		[]client_test.Param{
+			{
+				Name:         "out of order",
+				RequestBody:  `[{"jsonrpc":"2.0","id":1,"method":"getBalance","params":["RNfp4xTbBb4C3kcv2KqtAj8mu4YhMHxqm1Skg9uchZ7",{"commitment":"finalized"}]},{"jsonrpc":"2.0","id":2,"method":"getSignatureStatuses","params":[["3yyWPtnRqAVfAsVz2aFyjqLd7wMgS9Jvbb4C59cz5QY6Ff9fcQJnGJdj5yCHEDKB3xD8EhXKj8csBzQFXZWkjh1S"]]},{"jsonrpc":"2.0","id":3,"method":"getSlot"}]`,
+				ResponseBody: `[{"jsonrpc":"2.0","result":86245,"id":3},{"jsonrpc":"2.0","error":{"code":-32602,"message":"Invalid param: WrongSize"},"id":2},{"jsonrpc":"2.0","result":{"context":{"slot":73914708},"value":6999995000},"id":1}]`,
+				F: func(url string) (any, error) {
+					c := NewRpcClient(url)
+					b := c.NewBatch()
+					balance := b.GetBalanceWithConfig("RNfp4xTbBb4C3kcv2KqtAj8mu4YhMHxqm1Skg9uchZ7", GetBalanceConfig{Commitment: CommitmentFinalized})
+					statuses := b.GetSignatureStatuses([]string{"3yyWPtnRqAVfAsVz2aFyjqLd7wMgS9Jvbb4C59cz5QY6Ff9fcQJnGJdj5yCHEDKB3xD8EhXKj8csBzQFXZWkjh1S"})
+					slot := b.GetSlot()
+					if err := b.Send(context.Background()); err != nil {
+						return nil, err
+					}
+					var r results
+					r.Balance, r.BalanceErr = balance.Result()
+					r.Statuses, r.StatusesErr = statuses.Result()
+					r.Slot, r.SlotErr = slot.Result()
+					return r, nil
+				},
+				ExpectedValue: results{
+					Balance: JsonRpcResponse[ValueWithContext[uint64]]{
+						JsonRpc: "2.0",
+						Id:      1,
+						Result: ValueWithContext[uint64]{
+							Context: Context{Slot: 73914708},
+							Value:   6999995000,
+						},
+					},
+					Statuses: JsonRpcResponse[ValueWithContext[SignatureStatuses]]{
+						JsonRpc: "2.0",
+						Id:      2,
+						Error: &JsonRpcError{
+							Code:    -32602,
+							Message: "Invalid param: WrongSize",
+						},
+					},
+					Slot: JsonRpcResponse[uint64]{
+						JsonRpc: "2.0",
+						Id:      3,
+						Result:  86245,
+					},
+				},
+				ExpectedError: nil,
+			},
+		},
+	)
+}
+
+func TestBatch_MissingResponse(t *testing.T) {
+	client_test.TestAll(
+		t,
+		[]client_test.Param{
+			{
+				RequestBody:  `[{"jsonrpc":"2.0","id":1,"method":"getSlot"},{"jsonrpc":"2.0","id":2,"method":"getSlot"}]`,
+				ResponseBody: `[{"jsonrpc":"2.0","result":1,"id":1}]`,
+				F: func(url string) (any, error) {
+					c := NewRpcClient(url)
+					b := c.NewBatch()
+					b.GetSlot()
+					slot := b.GetSlot()
+					if err := b.Send(context.Background()); err != nil {
+						return nil, err
+					}
+					_, err := slot.Result()
+					assert.ErrorIs(t, err, ErrBatchMissingResponse)
+					return b.Len(), nil
+				},
+				ExpectedValue: 2,
+				ExpectedError: nil,
+			},
+		},
+	)
+}
+
+func TestBatch_NotSent(t *testing.T) {
+	c := NewRpcClient(LocalnetRPCEndpoint)
+	b := c.NewBatch()
+	_, err := b.GetSlot().Result()
+	assert.ErrorIs(t, err, ErrBatchNotSent)
+}
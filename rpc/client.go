@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"time"
 )
 
 const (
@@ -63,9 +64,10 @@ type ValueWithContext[T any] struct {
 }
 
 type RpcClient struct {
-	endpoint   string
-	httpClient HttpClient
-	modifiers  struct {
+	endpoint    string
+	httpClient  HttpClient
+	retryPolicy *RetryPolicy
+	modifiers   struct {
 		payload      []ModifierPayload
 		httpRequest  []ModifierHttpRequest
 		httpResponse []ModifierHttpResponse
@@ -91,25 +93,57 @@ func New(opts ...Option) RpcClient {
 
 // Call will return body of response. if http code beyond 200~300, the error also returns.
 func (c *RpcClient) Call(ctx context.Context, params ...any) ([]byte, error) {
-	// prepare payload
-	payload := JsonRpcRequest{
+	j, err := preparePayload(params, c.modifiers.payload...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare payload, err: %v", err)
+	}
+	return c.post(ctx, j, isIdempotent(params[0].(string)))
+}
+
+func newJsonRpcRequest(id uint64, params []any) JsonRpcRequest {
+	return JsonRpcRequest{
 		JsonRpc: "2.0",
-		Id:      1,
+		Id:      id,
 		Method:  params[0].(string),
 		Params:  params[1:],
 	}
-	for index := range c.modifiers.payload {
-		c.modifiers.payload[index](&payload)
+}
+
+func preparePayload(params []any, modifiers ...ModifierPayload) ([]byte, error) {
+	payload := newJsonRpcRequest(1, params)
+	for index := range modifiers {
+		modifiers[index](&payload)
 	}
-	j, err := json.Marshal(payload)
-	if err != nil {
-		return nil, fmt.Errorf("failed to prepare payload, err: %v", err)
+	return json.Marshal(payload)
+}
+
+// post sends the json body to the endpoint and returns the body of response.
+// it retries according to the retry policy if one is configured.
+func (c *RpcClient) post(ctx context.Context, j []byte, idempotent bool) ([]byte, error) {
+	for attempt := 1; ; attempt++ {
+		body, res, err := c.postOnce(ctx, j, attempt)
+		if c.retryPolicy == nil {
+			return body, err
+		}
+		wait, retry := c.retryPolicy.next(ctx, attempt, idempotent, res, body, err)
+		if !retry {
+			return body, err
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return body, err
+		case <-timer.C:
+		}
 	}
+}
 
+func (c *RpcClient) postOnce(ctx context.Context, j []byte, attempt int) ([]byte, *http.Response, error) {
 	// prepare request
-	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewBuffer(j))
+	req, err := http.NewRequestWithContext(withAttempt(ctx, attempt), "POST", c.endpoint, bytes.NewBuffer(j))
 	if err != nil {
-		return nil, fmt.Errorf("failed to do http.NewRequestWithContext, err: %v", err)
+		return nil, nil, fmt.Errorf("failed to do http.NewRequestWithContext, err: %v", err)
 	}
 	for index := range c.modifiers.httpRequest {
 		c.modifiers.httpRequest[index](req)
@@ -118,26 +152,29 @@ func (c *RpcClient) Call(ctx context.Context, params ...any) ([]byte, error) {
 
 	// do request
 	res, err := c.httpClient.Do(req)
+	if err != nil {
+		err = &AttemptError{Attempt: attempt, Err: err}
+	}
 	for index := range c.modifiers.httpResponse {
 		c.modifiers.httpResponse[index](res, err)
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to do request, err: %v", err)
+		return nil, nil, fmt.Errorf("failed to do request, err: %w", err)
 	}
 	defer res.Body.Close()
 
 	// parse body
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read body, err: %v", err)
+		return nil, res, fmt.Errorf("failed to read body, err: %v", err)
 	}
 
 	// check response code
 	if res.StatusCode < 200 || res.StatusCode > 300 {
-		return body, fmt.Errorf("get status code: %v", res.StatusCode)
+		return body, res, fmt.Errorf("get status code: %v", res.StatusCode)
 	}
 
-	return body, nil
+	return body, res, nil
 }
 
 func call[T any](c *RpcClient, ctx context.Context, params ...any) (T, error) {
@@ -0,0 +1,183 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// json-rpc 2.0 and solana server error codes
+const (
+	ErrorCodeParse          = -32700
+	ErrorCodeInvalidRequest = -32600
+	ErrorCodeMethodNotFound = -32601
+	ErrorCodeInvalidParams  = -32602
+	ErrorCodeInternal       = -32603
+
+	ErrorCodeBlockCleanedUp                           = -32001
+	ErrorCodeSendTransactionPreflightFailure          = -32002
+	ErrorCodeTransactionSignatureVerificationFailure  = -32003
+	ErrorCodeBlockNotAvailable                        = -32004
+	ErrorCodeNodeUnhealthy                            = -32005
+	ErrorCodeTransactionPrecompileVerificationFailure = -32006
+	ErrorCodeSlotSkipped                              = -32007
+	ErrorCodeNoSnapshot                               = -32008
+	ErrorCodeLongTermStorageSlotSkipped               = -32009
+	ErrorCodeKeyExcludedFromSecondaryIndex            = -32010
+	ErrorCodeTransactionHistoryNotAvailable           = -32011
+	ErrorCodeScanError                                = -32012
+	ErrorCodeTransactionSignatureLenMismatch          = -32013
+	ErrorCodeBlockStatusNotAvailableYet               = -32014
+	ErrorCodeUnsupportedTransactionVersion            = -32015
+	ErrorCodeMinContextSlotNotReached                 = -32016
+	ErrorCodeEpochRewardsPeriodActive                 = -32017
+	ErrorCodeSlotNotEpochBoundary                     = -32018
+	ErrorCodeLongTermStorageUnreachable               = -32019
+)
+
+// sentinel errors of JsonRpcError, use them with errors.Is
+var (
+	ErrParse          = errors.New("rpc: parse error")
+	ErrInvalidRequest = errors.New("rpc: invalid request")
+	ErrMethodNotFound = errors.New("rpc: method not found")
+	ErrInvalidParams  = errors.New("rpc: invalid params")
+	ErrInternal       = errors.New("rpc: internal error")
+
+	ErrBlockCleanedUp                           = errors.New("rpc: block cleaned up")
+	ErrSendTransactionPreflightFailure          = errors.New("rpc: send transaction preflight failure")
+	ErrTransactionSignatureVerificationFailure  = errors.New("rpc: transaction signature verification failure")
+	ErrBlockNotAvailable                        = errors.New("rpc: block not available")
+	ErrNodeUnhealthy                            = errors.New("rpc: node is unhealthy")
+	ErrTransactionPrecompileVerificationFailure = errors.New("rpc: transaction precompile verification failure")
+	ErrSlotSkipped                              = errors.New("rpc: slot skipped")
+	ErrNoSnapshot                               = errors.New("rpc: no snapshot")
+	ErrLongTermStorageSlotSkipped               = errors.New("rpc: long-term storage slot skipped")
+	ErrKeyExcludedFromSecondaryIndex            = errors.New("rpc: key excluded from secondary index")
+	ErrTransactionHistoryNotAvailable           = errors.New("rpc: transaction history not available")
+	ErrScanError                                = errors.New("rpc: scan error")
+	ErrTransactionSignatureLenMismatch          = errors.New("rpc: transaction signature length mismatch")
+	ErrBlockStatusNotAvailableYet               = errors.New("rpc: block status not available yet")
+	ErrUnsupportedTransactionVersion            = errors.New("rpc: unsupported transaction version")
+	ErrMinContextSlotNotReached                 = errors.New("rpc: minimum context slot has not been reached")
+	ErrEpochRewardsPeriodActive                 = errors.New("rpc: epoch rewards period active")
+	ErrSlotNotEpochBoundary                     = errors.New("rpc: slot is not epoch boundary")
+	ErrLongTermStorageUnreachable               = errors.New("rpc: long-term storage unreachable")
+)
+
+var errorCodeToSentinel = map[int]error{
+	ErrorCodeParse:          ErrParse,
+	ErrorCodeInvalidRequest: ErrInvalidRequest,
+	ErrorCodeMethodNotFound: ErrMethodNotFound,
+	ErrorCodeInvalidParams:  ErrInvalidParams,
+	ErrorCodeInternal:       ErrInternal,
+
+	ErrorCodeBlockCleanedUp:                           ErrBlockCleanedUp,
+	ErrorCodeSendTransactionPreflightFailure:          ErrSendTransactionPreflightFailure,
+	ErrorCodeTransactionSignatureVerificationFailure:  ErrTransactionSignatureVerificationFailure,
+	ErrorCodeBlockNotAvailable:                        ErrBlockNotAvailable,
+	ErrorCodeNodeUnhealthy:                            ErrNodeUnhealthy,
+	ErrorCodeTransactionPrecompileVerificationFailure: ErrTransactionPrecompileVerificationFailure,
+	ErrorCodeSlotSkipped:                              ErrSlotSkipped,
+	ErrorCodeNoSnapshot:                               ErrNoSnapshot,
+	ErrorCodeLongTermStorageSlotSkipped:               ErrLongTermStorageSlotSkipped,
+	ErrorCodeKeyExcludedFromSecondaryIndex:            ErrKeyExcludedFromSecondaryIndex,
+	ErrorCodeTransactionHistoryNotAvailable:           ErrTransactionHistoryNotAvailable,
+	ErrorCodeScanError:                                ErrScanError,
+	ErrorCodeTransactionSignatureLenMismatch:          ErrTransactionSignatureLenMismatch,
+	ErrorCodeBlockStatusNotAvailableYet:               ErrBlockStatusNotAvailableYet,
+	ErrorCodeUnsupportedTransactionVersion:            ErrUnsupportedTransactionVersion,
+	ErrorCodeMinContextSlotNotReached:                 ErrMinContextSlotNotReached,
+	ErrorCodeEpochRewardsPeriodActive:                 ErrEpochRewardsPeriodActive,
+	ErrorCodeSlotNotEpochBoundary:                     ErrSlotNotEpochBoundary,
+	ErrorCodeLongTermStorageUnreachable:               ErrLongTermStorageUnreachable,
+}
+
+// Is reports whether the error code matches the sentinel error
+func (e *JsonRpcError) Is(target error) bool {
+	sentinel, ok := errorCodeToSentinel[e.Code]
+	return ok && sentinel == target
+}
+
+// Unwrap returns the typed data of the error, if the code carries one. it makes
+// errors.As work with *SendTransactionPreflightFailure, *NodeUnhealthyError,
+// *MinContextSlotNotReachedError and, through the preflight failure, *TransactionError.
+func (e *JsonRpcError) Unwrap() error {
+	if e.Data == nil {
+		return nil
+	}
+	switch e.Code {
+	case ErrorCodeSendTransactionPreflightFailure:
+		var v SendTransactionPreflightFailure
+		if decodeErrorData(e.Data, &v) != nil {
+			return nil
+		}
+		v.Message = e.Message
+		return &v
+	case ErrorCodeNodeUnhealthy:
+		var v NodeUnhealthyError
+		if decodeErrorData(e.Data, &v) != nil {
+			return nil
+		}
+		return &v
+	case ErrorCodeMinContextSlotNotReached:
+		var v MinContextSlotNotReachedError
+		if decodeErrorData(e.Data, &v) != nil {
+			return nil
+		}
+		return &v
+	}
+	return nil
+}
+
+func decodeErrorData(data any, v any) error {
+	b, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// SendTransactionPreflightFailure is the data of -32002, the simulation before sending failed
+type SendTransactionPreflightFailure struct {
+	Message       string            `json:"-"`
+	Err           *TransactionError `json:"err"`
+	Logs          []string          `json:"logs"`
+	Accounts      []*AccountInfo    `json:"accounts"`
+	UnitsConsumed *uint64           `json:"unitsConsumed"`
+	ReturnData    *ReturnData       `json:"returnData"`
+}
+
+func (e *SendTransactionPreflightFailure) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+	return fmt.Sprintf("%v, err: %v", e.Message, e.Err)
+}
+
+func (e *SendTransactionPreflightFailure) Unwrap() error {
+	if e.Err == nil {
+		return nil
+	}
+	return e.Err
+}
+
+// NodeUnhealthyError is the data of -32005
+type NodeUnhealthyError struct {
+	NumSlotsBehind *uint64 `json:"numSlotsBehind"`
+}
+
+func (e *NodeUnhealthyError) Error() string {
+	if e.NumSlotsBehind == nil {
+		return "node is unhealthy"
+	}
+	return fmt.Sprintf("node is behind by %v slots", *e.NumSlotsBehind)
+}
+
+// MinContextSlotNotReachedError is the data of -32016
+type MinContextSlotNotReachedError struct {
+	ContextSlot uint64 `json:"contextSlot"`
+}
+
+func (e *MinContextSlotNotReachedError) Error() string {
+	return fmt.Sprintf("minimum context slot has not been reached, context slot: %v", e.ContextSlot)
+}
@@ -0,0 +1,159 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJsonRpcError_Is(t *testing.T) {
+	var err error = &JsonRpcError{Code: -32005, Message: "Node is behind by 42 slots"}
+	assert.ErrorIs(t, err, ErrNodeUnhealthy)
+	assert.False(t, errors.Is(err, ErrBlockNotAvailable))
+
+	err = &JsonRpcError{Code: -32601, Message: "Method not found"}
+	assert.ErrorIs(t, err, ErrMethodNotFound)
+
+	err = &JsonRpcError{Code: -1, Message: "unknown"}
+	assert.False(t, errors.Is(err, ErrInternal))
+}
+
+func TestJsonRpcError_PreflightFailure(t *testing.T) {
+	server, _ := newScriptedServer(t, []scriptedResponse{
+		{status: 200, body: `{"jsonrpc":"2.0","error":{"code":-32002,"message":"Transaction simulation failed: Error processing Instruction 0: custom program error: 0x1","data":{"accounts":null,"err":{"InstructionError":[0,{"Custom":1}]},"logs":["Program 11111111111111111111111111111111 invoke [1]","Transfer: insufficient lamports 19078520, need 1000000000000","Program 11111111111111111111111111111111 failed: custom program error: 0x1"],"returnData":null,"unitsConsumed":150}},"id":1}`},
+	})
+	defer server.Close()
+
+	c := New(WithEndpoint(server.URL))
+	res, err := c.SendTransaction(context.Background(), "tx")
+	assert.Nil(t, err)
+
+	err = res.GetError()
+	assert.ErrorIs(t, err, ErrSendTransactionPreflightFailure)
+	assert.ErrorIs(t, err, ErrTransactionInstructionError)
+
+	var preflight *SendTransactionPreflightFailure
+	assert.True(t, errors.As(err, &preflight))
+	assert.Equal(t, uint64(150), *preflight.UnitsConsumed)
+	assert.Len(t, preflight.Logs, 3)
+
+	var ie *InstructionError
+	assert.True(t, errors.As(err, &ie))
+	assert.Equal(t, uint8(0), ie.Index)
+	assert.Equal(t, uint32(1), *ie.Custom)
+	assert.Equal(t, "instruction #0 failed: custom program error: 0x1", ie.Error())
+}
+
+func TestJsonRpcError_NodeUnhealthy(t *testing.T) {
+	var err error = &JsonRpcError{
+		Code:    -32005,
+		Message: "Node is behind by 42 slots",
+		Data:    map[string]any{"numSlotsBehind": 42},
+	}
+	var unhealthy *NodeUnhealthyError
+	assert.True(t, errors.As(err, &unhealthy))
+	assert.Equal(t, uint64(42), *unhealthy.NumSlotsBehind)
+
+	// data is optional
+	err = &JsonRpcError{Code: -32005, Message: "Node is unhealthy"}
+	assert.False(t, errors.As(err, &unhealthy))
+	assert.ErrorIs(t, err, ErrNodeUnhealthy)
+}
+
+func TestTransactionError(t *testing.T) {
+	u8 := func(v uint8) *uint8 { return &v }
+	u32 := func(v uint32) *uint32 { return &v }
+
+	tests := []struct {
+		name     string
+		raw      string
+		expected TransactionError
+		message  string
+	}{
+		{
+			name:     "unit variant",
+			raw:      `"BlockhashNotFound"`,
+			expected: TransactionError{Kind: TransactionErrorBlockhashNotFound},
+			message:  "BlockhashNotFound",
+		},
+		{
+			name: "instruction error with custom code",
+			raw:  `{"InstructionError":[2,{"Custom":6001}]}`,
+			expected: TransactionError{
+				Kind:             TransactionErrorInstructionError,
+				InstructionError: &InstructionError{Index: 2, Kind: InstructionErrorCustom, Custom: u32(6001)},
+			},
+			message: "instruction #2 failed: custom program error: 0x1771",
+		},
+		{
+			name: "instruction error unit variant",
+			raw:  `{"InstructionError":[0,"InvalidAccountData"]}`,
+			expected: TransactionError{
+				Kind:             TransactionErrorInstructionError,
+				InstructionError: &InstructionError{Index: 0, Kind: "InvalidAccountData"},
+			},
+			message: "instruction #0 failed: InvalidAccountData",
+		},
+		{
+			name: "instruction error borsh io error",
+			raw:  `{"InstructionError":[1,{"BorshIoError":"Unexpected length of input"}]}`,
+			expected: TransactionError{
+				Kind:             TransactionErrorInstructionError,
+				InstructionError: &InstructionError{Index: 1, Kind: InstructionErrorBorshIoError, Message: "Unexpected length of input"},
+			},
+			message: "instruction #1 failed: BorshIoError: Unexpected length of input",
+		},
+		{
+			name:     "duplicate instruction",
+			raw:      `{"DuplicateInstruction":3}`,
+			expected: TransactionError{Kind: TransactionErrorDuplicateInstruction, Index: u8(3)},
+			message:  "DuplicateInstruction at instruction #3",
+		},
+		{
+			name:     "insufficient funds for rent",
+			raw:      `{"InsufficientFundsForRent":{"account_index":2}}`,
+			expected: TransactionError{Kind: TransactionErrorInsufficientFundsForRent, AccountIndex: u8(2)},
+			message:  "InsufficientFundsForRent at account #2",
+		},
+		{
+			name:     "unknown variant",
+			raw:      `{"SomethingNew":{"a":1}}`,
+			expected: TransactionError{Kind: "SomethingNew", Data: json.RawMessage(`{"a":1}`)},
+			message:  `SomethingNew: {"a":1}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got TransactionError
+			assert.Nil(t, json.Unmarshal([]byte(tt.raw), &got))
+			assert.Equal(t, tt.expected, got)
+			assert.Equal(t, tt.message, got.Error())
+
+			b, err := json.Marshal(got)
+			assert.Nil(t, err)
+			assert.JSONEq(t, tt.raw, string(b))
+		})
+	}
+}
+
+func TestParseTransactionError(t *testing.T) {
+	got, err := ParseTransactionError(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, got)
+
+	got, err = ParseTransactionError(map[string]any{
+		"InstructionError": []any{float64(0), map[string]any{"Custom": float64(1)}},
+	})
+	assert.Nil(t, err)
+	assert.ErrorIs(t, got, ErrTransactionInstructionError)
+
+	got, err = ParseTransactionError("AlreadyProcessed")
+	assert.Nil(t, err)
+	assert.ErrorIs(t, got, ErrTransactionAlreadyProcessed)
+
+	_, err = ParseTransactionError([]any{1, 2})
+	assert.NotNil(t, err)
+}
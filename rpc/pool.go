@@ -0,0 +1,439 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+var ErrPoolNoEndpoint = errors.New("rpc: pool has no available endpoint")
+
+// PoolEndpointStatus is a snapshot of an endpoint in the pool
+type PoolEndpointStatus struct {
+	Endpoint string
+	Weight   int
+	// Latency is a moving average of the response time
+	Latency time.Duration
+	// Slot is the slot returned by the last health check
+	Slot uint64
+	// Healthy is false when the endpoint is taken out of rotation
+	Healthy bool
+}
+
+// PoolStrategy picks the endpoint for the next request
+type PoolStrategy interface {
+	// Pick returns an index of candidates. candidates is never empty.
+	Pick(candidates []PoolEndpointStatus) int
+}
+
+type roundRobinStrategy struct {
+	n uint64
+}
+
+// RoundRobinStrategy spreads requests evenly across endpoints
+func RoundRobinStrategy() PoolStrategy {
+	return &roundRobinStrategy{}
+}
+
+func (s *roundRobinStrategy) Pick(candidates []PoolEndpointStatus) int {
+	return int(atomic.AddUint64(&s.n, 1)-1) % len(candidates)
+}
+
+type weightedStrategy struct{}
+
+// WeightedStrategy picks an endpoint randomly in proportion to its weight
+func WeightedStrategy() PoolStrategy {
+	return weightedStrategy{}
+}
+
+func (weightedStrategy) Pick(candidates []PoolEndpointStatus) int {
+	total := 0
+	for _, c := range candidates {
+		total += c.Weight
+	}
+	if total <= 0 {
+		return rand.Intn(len(candidates))
+	}
+	n := rand.Intn(total)
+	for i, c := range candidates {
+		if n < c.Weight {
+			return i
+		}
+		n -= c.Weight
+	}
+	return len(candidates) - 1
+}
+
+type lowestLatencyStrategy struct{}
+
+// LowestLatencyStrategy picks the endpoint which responds fastest
+func LowestLatencyStrategy() PoolStrategy {
+	return lowestLatencyStrategy{}
+}
+
+func (lowestLatencyStrategy) Pick(candidates []PoolEndpointStatus) int {
+	best := 0
+	for i, c := range candidates {
+		if c.Latency < candidates[best].Latency {
+			best = i
+		}
+	}
+	return best
+}
+
+// PoolEndpoint configures an endpoint of the pool
+type PoolEndpoint struct {
+	Endpoint string
+	// Weight is only used by WeightedStrategy. zero means 1.
+	Weight int
+}
+
+type poolEndpoint struct {
+	url    *url.URL
+	client RpcClient
+
+	mu       sync.Mutex
+	status   PoolEndpointStatus
+	failures int
+}
+
+func (e *poolEndpoint) snapshot() PoolEndpointStatus {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.status
+}
+
+func (e *poolEndpoint) observe(latency time.Duration, failed bool, maxFailures int) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.status.Latency == 0 {
+		e.status.Latency = latency
+	} else {
+		e.status.Latency = (e.status.Latency*4 + latency) / 5
+	}
+	if !failed {
+		e.failures = 0
+		return
+	}
+	e.failures++
+	if e.failures >= maxFailures {
+		e.status.Healthy = false
+	}
+}
+
+// Pool spreads requests over several endpoints and fails over when one of them
+// errors. It is an HttpClient, so it is plugged into a RpcClient by WithPool.
+type Pool struct {
+	endpoints           []*poolEndpoint
+	httpClient          HttpClient
+	strategy            PoolStrategy
+	healthCheckInterval time.Duration
+	maxSlotLag          uint64
+	maxFailures         int
+}
+
+// PoolOption is a configuration type for the Pool
+type PoolOption func(*Pool)
+
+// WithPoolStrategy sets how the endpoint of a request is chosen. default: RoundRobinStrategy
+func WithPoolStrategy(strategy PoolStrategy) PoolOption {
+	return func(p *Pool) {
+		p.strategy = strategy
+	}
+}
+
+// WithPoolHttpClient sets the http client used to reach every endpoint
+func WithPoolHttpClient(client HttpClient) PoolOption {
+	return func(p *Pool) {
+		p.httpClient = client
+	}
+}
+
+// WithPoolHealthCheckInterval sets how often the endpoints are probed. default: 10s
+func WithPoolHealthCheckInterval(d time.Duration) PoolOption {
+	return func(p *Pool) {
+		p.healthCheckInterval = d
+	}
+}
+
+// WithPoolMaxSlotLag sets how many slots an endpoint can fall behind the best one
+// before it is taken out of rotation. default: 50
+func WithPoolMaxSlotLag(slots uint64) PoolOption {
+	return func(p *Pool) {
+		p.maxSlotLag = slots
+	}
+}
+
+// WithPoolMaxFailures sets how many consecutive failed requests take an endpoint
+// out of rotation until the next successful health check. default: 3
+func WithPoolMaxFailures(n int) PoolOption {
+	return func(p *Pool) {
+		p.maxFailures = n
+	}
+}
+
+// NewPool creates a pool. all endpoints are in rotation until the first health check.
+func NewPool(endpoints []PoolEndpoint, opts ...PoolOption) (*Pool, error) {
+	if len(endpoints) == 0 {
+		return nil, ErrPoolNoEndpoint
+	}
+
+	p := &Pool{
+		httpClient:          &http.Client{},
+		strategy:            RoundRobinStrategy(),
+		healthCheckInterval: 10 * time.Second,
+		maxSlotLag:          50,
+		maxFailures:         3,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	for _, e := range endpoints {
+		u, err := url.Parse(e.Endpoint)
+		if err != nil {
+			return nil, fmt.Errorf("rpc: failed to parse endpoint %v, err: %v", e.Endpoint, err)
+		}
+		weight := e.Weight
+		if weight <= 0 {
+			weight = 1
+		}
+		p.endpoints = append(p.endpoints, &poolEndpoint{
+			url:    u,
+			client: New(WithEndpoint(e.Endpoint), WithHttpClient(p.httpClient)),
+			status: PoolEndpointStatus{
+				Endpoint: e.Endpoint,
+				Weight:   weight,
+				Healthy:  true,
+			},
+		})
+	}
+
+	return p, nil
+}
+
+// WithPool is an Option that sends every request through the pool
+func WithPool(p *Pool) Option {
+	return func(r *RpcClient) {
+		r.endpoint = p.endpoints[0].status.Endpoint
+		r.httpClient = p
+	}
+}
+
+// Status returns a snapshot of all endpoints
+func (p *Pool) Status() []PoolEndpointStatus {
+	output := make([]PoolEndpointStatus, 0, len(p.endpoints))
+	for _, e := range p.endpoints {
+		output = append(output, e.snapshot())
+	}
+	return output
+}
+
+// Start probes the endpoints periodically until ctx is done
+func (p *Pool) Start(ctx context.Context) {
+	go func() {
+		p.CheckHealth(ctx)
+		ticker := time.NewTicker(p.healthCheckInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				p.CheckHealth(ctx)
+			}
+		}
+	}()
+}
+
+// CheckHealth probes every endpoint with `getHealth` and `getSlot` once. an endpoint
+// stays in rotation if it is healthy and within the max slot lag of the best one.
+func (p *Pool) CheckHealth(ctx context.Context) {
+	type probe struct {
+		ok      bool
+		slot    uint64
+		latency time.Duration
+	}
+	probes := make([]probe, len(p.endpoints))
+
+	var wg sync.WaitGroup
+	for i, e := range p.endpoints {
+		wg.Add(1)
+		go func(i int, e *poolEndpoint) {
+			defer wg.Done()
+			start := time.Now()
+			health, err := e.client.GetHealth(ctx)
+			if err != nil || health.Error != nil || health.Result != "ok" {
+				return
+			}
+			slot, err := e.client.GetSlot(ctx)
+			if err != nil || slot.Error != nil {
+				return
+			}
+			probes[i] = probe{ok: true, slot: slot.Result, latency: time.Since(start) / 2}
+		}(i, e)
+	}
+	wg.Wait()
+
+	var best uint64
+	for _, pr := range probes {
+		if pr.ok && pr.slot > best {
+			best = pr.slot
+		}
+	}
+
+	for i, e := range p.endpoints {
+		pr := probes[i]
+		e.mu.Lock()
+		e.status.Healthy = pr.ok && best-pr.slot <= p.maxSlotLag
+		if pr.ok {
+			e.status.Slot = pr.slot
+			e.failures = 0
+			if e.status.Latency == 0 {
+				e.status.Latency = pr.latency
+			} else {
+				e.status.Latency = (e.status.Latency*4 + pr.latency) / 5
+			}
+		}
+		e.mu.Unlock()
+	}
+}
+
+// candidates returns the endpoints in rotation which are not in excluded. if all
+// endpoints are out of rotation, the rest of them are returned as a last resort.
+func (p *Pool) candidates(excluded map[*poolEndpoint]bool) ([]*poolEndpoint, []PoolEndpointStatus) {
+	var endpoints, fallback []*poolEndpoint
+	var statuses, fallbackStatuses []PoolEndpointStatus
+	for _, e := range p.endpoints {
+		if excluded[e] {
+			continue
+		}
+		s := e.snapshot()
+		if s.Healthy {
+			endpoints = append(endpoints, e)
+			statuses = append(statuses, s)
+		} else {
+			fallback = append(fallback, e)
+			fallbackStatuses = append(fallbackStatuses, s)
+		}
+	}
+	if len(endpoints) == 0 {
+		return fallback, fallbackStatuses
+	}
+	return endpoints, statuses
+}
+
+// Do sends the request to an endpoint picked by the strategy. on a transport error,
+// 429 or 5xx, it fails over to the next endpoint until every endpoint is tried.
+// a request which changes the state of the chain, like sendTransaction, only fails
+// over when it certainly never reached a node, so it is never executed twice.
+func (p *Pool) Do(req *http.Request) (*http.Response, error) {
+	idempotent := false
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		idempotent = isIdempotentBody(body)
+	}
+
+	tried := map[*poolEndpoint]bool{}
+	var lastRes *http.Response
+	var lastErr error
+
+	for {
+		endpoints, statuses := p.candidates(tried)
+		if len(endpoints) == 0 {
+			break
+		}
+		e := endpoints[p.strategy.Pick(statuses)]
+		tried[e] = true
+
+		r := req.Clone(req.Context())
+		r.URL = e.url
+		r.Host = e.url.Host
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			r.Body = body
+		}
+
+		start := time.Now()
+		res, err := p.httpClient.Do(r)
+		failed := err != nil || res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= 500
+		e.observe(time.Since(start), failed, p.maxFailures)
+		if !failed {
+			return res, nil
+		}
+
+		if lastRes != nil {
+			lastRes.Body.Close()
+		}
+		lastRes, lastErr = res, err
+
+		if req.Context().Err() != nil || (req.GetBody == nil && req.Body != nil) {
+			break
+		}
+		if !idempotent && !notDelivered(res, err) {
+			break
+		}
+	}
+
+	if lastRes == nil && lastErr == nil {
+		return nil, ErrPoolNoEndpoint
+	}
+	return lastRes, lastErr
+}
+
+// isIdempotentBody reports whether every json-rpc request in the body is idempotent.
+// a body which can't be parsed is not.
+func isIdempotentBody(body io.ReadCloser) bool {
+	defer body.Close()
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return false
+	}
+
+	type request struct {
+		Method string `json:"method"`
+	}
+	var requests []request
+	if len(b) > 0 && b[0] == '[' {
+		if json.Unmarshal(b, &requests) != nil {
+			return false
+		}
+	} else {
+		var r request
+		if json.Unmarshal(b, &r) != nil {
+			return false
+		}
+		requests = append(requests, r)
+	}
+	for _, r := range requests {
+		if r.Method == "" || !isIdempotent(r.Method) {
+			return false
+		}
+	}
+	return true
+}
+
+// notDelivered reports whether the request certainly never reached a node
+func notDelivered(res *http.Response, err error) bool {
+	if err == nil {
+		return res.StatusCode == http.StatusTooManyRequests
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) || errors.Is(err, syscall.ECONNREFUSED)
+}
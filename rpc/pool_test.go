@@ -0,0 +1,205 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeNode struct {
+	server  *httptest.Server
+	slot    uint64
+	status  int
+	balance uint64
+	calls   int32
+}
+
+func newFakeNode(t *testing.T, slot uint64, balance uint64) *fakeNode {
+	n := &fakeNode{slot: slot, status: http.StatusOK, balance: balance}
+	n.server = httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		body, err := io.ReadAll(req.Body)
+		assert.Nil(t, err)
+		var r JsonRpcRequest
+		assert.Nil(t, json.Unmarshal(body, &r))
+
+		if r.Method == "getBalance" || r.Method == "requestAirdrop" {
+			atomic.AddInt32(&n.calls, 1)
+		}
+		if n.status != http.StatusOK {
+			rw.WriteHeader(n.status)
+			return
+		}
+		switch r.Method {
+		case "getHealth":
+			fmt.Fprint(rw, `{"jsonrpc":"2.0","result":"ok","id":1}`)
+		case "getSlot":
+			fmt.Fprintf(rw, `{"jsonrpc":"2.0","result":%d,"id":1}`, n.slot)
+		case "getBalance":
+			fmt.Fprintf(rw, `{"jsonrpc":"2.0","result":{"context":{"slot":%d},"value":%d},"id":1}`, n.slot, n.balance)
+		case "requestAirdrop":
+			fmt.Fprint(rw, `{"jsonrpc":"2.0","result":"3Fq8ZGNVcMWGnr7ZE8qUG6TTk7WHgAcFeyG37ibzQqBy5pUCiNzHePdv8kQUs2bypkfiaHstFxpcv4gZ94ZrYLza","id":1}`)
+		}
+	}))
+	return n
+}
+
+func newTestPool(t *testing.T, nodes []*fakeNode, opts ...PoolOption) *Pool {
+	endpoints := make([]PoolEndpoint, 0, len(nodes))
+	for _, n := range nodes {
+		endpoints = append(endpoints, PoolEndpoint{Endpoint: n.server.URL})
+	}
+	p, err := NewPool(endpoints, opts...)
+	require.Nil(t, err)
+	return p
+}
+
+func TestPool_RoundRobin(t *testing.T) {
+	nodes := []*fakeNode{newFakeNode(t, 100, 1), newFakeNode(t, 100, 2), newFakeNode(t, 100, 3)}
+	for _, n := range nodes {
+		defer n.server.Close()
+	}
+
+	c := New(WithPool(newTestPool(t, nodes)))
+	got := []uint64{}
+	for i := 0; i < 6; i++ {
+		res, err := c.GetBalance(context.Background(), "RNfp4xTbBb4C3kcv2KqtAj8mu4YhMHxqm1Skg9uchZ7")
+		require.Nil(t, err)
+		got = append(got, res.Result.Value)
+	}
+	assert.Equal(t, []uint64{1, 2, 3, 1, 2, 3}, got)
+}
+
+func TestPool_Failover(t *testing.T) {
+	nodes := []*fakeNode{newFakeNode(t, 100, 1), newFakeNode(t, 100, 2)}
+	for _, n := range nodes {
+		defer n.server.Close()
+	}
+	nodes[0].status = http.StatusServiceUnavailable
+
+	p := newTestPool(t, nodes, WithPoolMaxFailures(1))
+	c := New(WithPool(p))
+	for i := 0; i < 3; i++ {
+		res, err := c.GetBalance(context.Background(), "RNfp4xTbBb4C3kcv2KqtAj8mu4YhMHxqm1Skg9uchZ7")
+		require.Nil(t, err)
+		assert.Equal(t, uint64(2), res.Result.Value)
+	}
+	// the broken endpoint is taken out of rotation after the first failure
+	assert.Equal(t, int32(1), atomic.LoadInt32(&nodes[0].calls))
+	assert.False(t, p.Status()[0].Healthy)
+
+	// and comes back after a successful health check
+	nodes[0].status = http.StatusOK
+	p.CheckHealth(context.Background())
+	assert.True(t, p.Status()[0].Healthy)
+}
+
+func TestPool_FailoverNonIdempotent(t *testing.T) {
+	airdrop := func(p *Pool) error {
+		c := New(WithPool(p))
+		_, err := c.RequestAirdrop(context.Background(), "RNfp4xTbBb4C3kcv2KqtAj8mu4YhMHxqm1Skg9uchZ7", 1)
+		return err
+	}
+
+	t.Run("reached a node", func(t *testing.T) {
+		nodes := []*fakeNode{newFakeNode(t, 100, 1), newFakeNode(t, 100, 2)}
+		for _, n := range nodes {
+			defer n.server.Close()
+		}
+		nodes[0].status = http.StatusBadGateway
+
+		assert.NotNil(t, airdrop(newTestPool(t, nodes)))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&nodes[0].calls))
+		assert.Equal(t, int32(0), atomic.LoadInt32(&nodes[1].calls), "the airdrop may have been executed")
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		nodes := []*fakeNode{newFakeNode(t, 100, 1), newFakeNode(t, 100, 2)}
+		for _, n := range nodes {
+			defer n.server.Close()
+		}
+		nodes[0].status = http.StatusTooManyRequests
+
+		assert.Nil(t, airdrop(newTestPool(t, nodes)))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&nodes[1].calls))
+	})
+
+	t.Run("connection refused", func(t *testing.T) {
+		nodes := []*fakeNode{newFakeNode(t, 100, 1), newFakeNode(t, 100, 2)}
+		defer nodes[1].server.Close()
+		p := newTestPool(t, nodes)
+		nodes[0].server.Close()
+
+		assert.Nil(t, airdrop(p))
+		assert.Equal(t, int32(1), atomic.LoadInt32(&nodes[1].calls))
+	})
+}
+
+func TestPool_AllFailed(t *testing.T) {
+	nodes := []*fakeNode{newFakeNode(t, 100, 1), newFakeNode(t, 100, 2)}
+	for _, n := range nodes {
+		defer n.server.Close()
+		n.status = http.StatusBadGateway
+	}
+
+	c := New(WithPool(newTestPool(t, nodes)))
+	_, err := c.GetBalance(context.Background(), "RNfp4xTbBb4C3kcv2KqtAj8mu4YhMHxqm1Skg9uchZ7")
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&nodes[0].calls))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&nodes[1].calls))
+}
+
+func TestPool_SlotLag(t *testing.T) {
+	nodes := []*fakeNode{newFakeNode(t, 1000, 1), newFakeNode(t, 900, 2), newFakeNode(t, 990, 3)}
+	for _, n := range nodes {
+		defer n.server.Close()
+	}
+
+	p := newTestPool(t, nodes, WithPoolMaxSlotLag(20))
+	p.CheckHealth(context.Background())
+
+	status := p.Status()
+	assert.True(t, status[0].Healthy)
+	assert.False(t, status[1].Healthy)
+	assert.True(t, status[2].Healthy)
+	assert.Equal(t, uint64(900), status[1].Slot)
+
+	c := New(WithPool(p))
+	for i := 0; i < 4; i++ {
+		res, err := c.GetBalance(context.Background(), "RNfp4xTbBb4C3kcv2KqtAj8mu4YhMHxqm1Skg9uchZ7")
+		require.Nil(t, err)
+		assert.NotEqual(t, uint64(2), res.Result.Value)
+	}
+	assert.Equal(t, int32(0), atomic.LoadInt32(&nodes[1].calls))
+}
+
+func TestPoolStrategy(t *testing.T) {
+	candidates := []PoolEndpointStatus{
+		{Endpoint: "a", Weight: 1, Latency: 30 * time.Millisecond},
+		{Endpoint: "b", Weight: 0, Latency: 10 * time.Millisecond},
+		{Endpoint: "c", Weight: 3, Latency: 20 * time.Millisecond},
+	}
+
+	assert.Equal(t, 1, LowestLatencyStrategy().Pick(candidates))
+
+	counts := make([]int, len(candidates))
+	s := WeightedStrategy()
+	for i := 0; i < 400; i++ {
+		counts[s.Pick(candidates)]++
+	}
+	assert.Equal(t, 0, counts[1])
+	assert.Greater(t, counts[2], counts[0])
+}
+
+func TestNewPool(t *testing.T) {
+	_, err := NewPool(nil)
+	assert.ErrorIs(t, err, ErrPoolNoEndpoint)
+}
@@ -0,0 +1,197 @@
+package rpc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy describes when and how often a failed call is sent again
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one
+	MaxAttempts int
+	// InitialBackoff is the wait before the second attempt
+	InitialBackoff time.Duration
+	// MaxBackoff caps the exponential backoff. it doesn't cap `Retry-After`
+	MaxBackoff time.Duration
+	// Multiplier grows the backoff after every attempt
+	Multiplier float64
+	// Jitter is the fraction, in [0, 1], of the backoff which is randomized
+	Jitter float64
+	// RetryableStatusCodes are the http status codes considered transient
+	RetryableStatusCodes []int
+	// RetryableRpcErrorCodes are the json-rpc error codes considered transient
+	RetryableRpcErrorCodes []int
+	// RetryNonIdempotent allows retrying `sendTransaction` and other writes on
+	// any transient failure. by default they are only retried on 429, which the
+	// node replies without processing the request.
+	RetryNonIdempotent bool
+}
+
+// DefaultRetryPolicy retries 429, 502, 503, 504 and "node is behind" up to 4 times
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts:    4,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     5 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+		RetryableStatusCodes: []int{
+			http.StatusTooManyRequests,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+		RetryableRpcErrorCodes: []int{
+			ErrorCodeNodeUnhealthy,
+			ErrorCodeMinContextSlotNotReached,
+		},
+	}
+}
+
+// WithRetryPolicy is an Option that retries transient http and json-rpc failures
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(r *RpcClient) {
+		r.retryPolicy = &policy
+	}
+}
+
+// methods which change the state of the chain
+var nonIdempotentMethods = map[string]bool{
+	"sendTransaction": true,
+	"sendBundle":      true,
+	"requestAirdrop":  true,
+}
+
+func isIdempotent(method string) bool {
+	return !nonIdempotentMethods[method]
+}
+
+// next decides whether the attempt should be retried and how long to wait before that
+func (p *RetryPolicy) next(ctx context.Context, attempt int, idempotent bool, res *http.Response, body []byte, err error) (time.Duration, bool) {
+	if attempt >= p.MaxAttempts || ctx.Err() != nil {
+		return 0, false
+	}
+
+	var retryAfter time.Duration
+	switch {
+	case err != nil && res == nil:
+		// transport error, the node may or may not have received the request
+		if !idempotent && !p.RetryNonIdempotent {
+			return 0, false
+		}
+	case err != nil:
+		if !containsInt(p.RetryableStatusCodes, res.StatusCode) {
+			return 0, false
+		}
+		if !idempotent && !p.RetryNonIdempotent && res.StatusCode != http.StatusTooManyRequests {
+			return 0, false
+		}
+		retryAfter = parseRetryAfter(res.Header.Get("Retry-After"))
+	default:
+		if !idempotent && !p.RetryNonIdempotent {
+			return 0, false
+		}
+		var r struct {
+			Error *JsonRpcError `json:"error"`
+		}
+		if json.Unmarshal(body, &r) != nil || r.Error == nil || !containsInt(p.RetryableRpcErrorCodes, r.Error.Code) {
+			return 0, false
+		}
+	}
+
+	wait := p.backoff(attempt)
+	if retryAfter > wait {
+		wait = retryAfter
+	}
+
+	// give up early if the deadline will pass before the next attempt
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(wait).After(deadline) {
+		return 0, false
+	}
+
+	return wait, true
+}
+
+func (p *RetryPolicy) backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	d := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && d > float64(p.MaxBackoff) {
+		d = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		d -= d * p.Jitter * rand.Float64()
+	}
+	return time.Duration(d)
+}
+
+// parseRetryAfter supports both delay-seconds and http-date
+func parseRetryAfter(s string) time.Duration {
+	if s == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(s); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+	if t, err := http.ParseTime(s); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+func containsInt(list []int, v int) bool {
+	for _, e := range list {
+		if e == v {
+			return true
+		}
+	}
+	return false
+}
+
+// AttemptError is passed to the http response modifiers when a request fails
+type AttemptError struct {
+	Attempt int
+	Err     error
+}
+
+func (e *AttemptError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *AttemptError) Unwrap() error {
+	return e.Err
+}
+
+type attemptContextKey struct{}
+
+func withAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptContextKey{}, attempt)
+}
+
+// AttemptFromHttpResponse returns which attempt, starting from 1, the http response
+// modifier is called for. it returns 0 if the attempt is unknown.
+func AttemptFromHttpResponse(res *http.Response, err error) int {
+	var attemptErr *AttemptError
+	if errors.As(err, &attemptErr) {
+		return attemptErr.Attempt
+	}
+	if res != nil && res.Request != nil {
+		if attempt, ok := res.Request.Context().Value(attemptContextKey{}).(int); ok {
+			return attempt
+		}
+	}
+	return 0
+}
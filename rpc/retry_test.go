@@ -0,0 +1,174 @@
+package rpc
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type scriptedResponse struct {
+	status     int
+	retryAfter string
+	body       string
+}
+
+func newScriptedServer(t *testing.T, responses []scriptedResponse) (*httptest.Server, *int32) {
+	var count int32
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		i := atomic.AddInt32(&count, 1) - 1
+		if int(i) >= len(responses) {
+			t.Errorf("unexpected attempt #%d", i+1)
+			rw.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		r := responses[i]
+		if r.retryAfter != "" {
+			rw.Header().Set("Retry-After", r.retryAfter)
+		}
+		rw.WriteHeader(r.status)
+		_, _ = rw.Write([]byte(r.body))
+	}))
+	return server, &count
+}
+
+func testRetryPolicy() RetryPolicy {
+	p := DefaultRetryPolicy()
+	p.InitialBackoff = time.Millisecond
+	p.MaxBackoff = 5 * time.Millisecond
+	return p
+}
+
+func TestRetryPolicy_RetryTransientStatus(t *testing.T) {
+	server, count := newScriptedServer(t, []scriptedResponse{
+		{status: http.StatusTooManyRequests, retryAfter: "0"},
+		{status: http.StatusServiceUnavailable},
+		{status: http.StatusOK, body: `{"jsonrpc":"2.0","result":100,"id":1}`},
+	})
+	defer server.Close()
+
+	attempts := []int{}
+	c := New(
+		WithEndpoint(server.URL),
+		WithRetryPolicy(testRetryPolicy()),
+		WithHttpResponseModifier(func(res *http.Response, err error) {
+			attempts = append(attempts, AttemptFromHttpResponse(res, err))
+		}),
+	)
+	res, err := c.GetSlot(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(100), res.Result)
+	assert.Equal(t, int32(3), atomic.LoadInt32(count))
+	assert.Equal(t, []int{1, 2, 3}, attempts)
+}
+
+func TestRetryPolicy_RetryRpcError(t *testing.T) {
+	server, count := newScriptedServer(t, []scriptedResponse{
+		{status: http.StatusOK, body: `{"jsonrpc":"2.0","error":{"code":-32005,"message":"Node is behind by 42 slots"},"id":1}`},
+		{status: http.StatusOK, body: `{"jsonrpc":"2.0","result":"ok","id":1}`},
+	})
+	defer server.Close()
+
+	c := New(WithEndpoint(server.URL), WithRetryPolicy(testRetryPolicy()))
+	res, err := c.GetHealth(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, "ok", res.Result)
+	assert.Equal(t, int32(2), atomic.LoadInt32(count))
+}
+
+func TestRetryPolicy_GiveUpAfterMaxAttempts(t *testing.T) {
+	server, count := newScriptedServer(t, []scriptedResponse{
+		{status: http.StatusOK, body: `{"jsonrpc":"2.0","error":{"code":-32005,"message":"Node is behind by 42 slots"},"id":1}`},
+		{status: http.StatusOK, body: `{"jsonrpc":"2.0","error":{"code":-32005,"message":"Node is behind by 43 slots"},"id":1}`},
+	})
+	defer server.Close()
+
+	p := testRetryPolicy()
+	p.MaxAttempts = 2
+	c := New(WithEndpoint(server.URL), WithRetryPolicy(p))
+	res, err := c.GetHealth(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, &JsonRpcError{Code: -32005, Message: "Node is behind by 43 slots"}, res.Error)
+	assert.Equal(t, int32(2), atomic.LoadInt32(count))
+}
+
+func TestRetryPolicy_NonIdempotent(t *testing.T) {
+	// 503 may have been processed, it should not be sent twice
+	server, count := newScriptedServer(t, []scriptedResponse{
+		{status: http.StatusServiceUnavailable},
+	})
+	c := New(WithEndpoint(server.URL), WithRetryPolicy(testRetryPolicy()))
+	_, err := c.SendTransaction(context.Background(), "tx")
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(count))
+	server.Close()
+
+	// 429 is rejected before processing
+	server, count = newScriptedServer(t, []scriptedResponse{
+		{status: http.StatusTooManyRequests},
+		{status: http.StatusOK, body: `{"jsonrpc":"2.0","result":"sig","id":1}`},
+	})
+	c = New(WithEndpoint(server.URL), WithRetryPolicy(testRetryPolicy()))
+	res, err := c.SendTransaction(context.Background(), "tx")
+	assert.Nil(t, err)
+	assert.Equal(t, "sig", res.Result)
+	assert.Equal(t, int32(2), atomic.LoadInt32(count))
+	server.Close()
+
+	// opt in
+	server, count = newScriptedServer(t, []scriptedResponse{
+		{status: http.StatusServiceUnavailable},
+		{status: http.StatusOK, body: `{"jsonrpc":"2.0","result":"sig","id":1}`},
+	})
+	p := testRetryPolicy()
+	p.RetryNonIdempotent = true
+	c = New(WithEndpoint(server.URL), WithRetryPolicy(p))
+	res, err = c.SendTransaction(context.Background(), "tx")
+	assert.Nil(t, err)
+	assert.Equal(t, "sig", res.Result)
+	assert.Equal(t, int32(2), atomic.LoadInt32(count))
+	server.Close()
+}
+
+func TestRetryPolicy_RespectDeadline(t *testing.T) {
+	server, count := newScriptedServer(t, []scriptedResponse{
+		{status: http.StatusTooManyRequests, retryAfter: "10"},
+	})
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	c := New(WithEndpoint(server.URL), WithRetryPolicy(testRetryPolicy()))
+	start := time.Now()
+	_, err := c.GetSlot(ctx)
+	assert.NotNil(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(count))
+}
+
+func TestRetryPolicy_NoPolicy(t *testing.T) {
+	server, count := newScriptedServer(t, []scriptedResponse{
+		{status: http.StatusTooManyRequests},
+	})
+	defer server.Close()
+
+	c := New(WithEndpoint(server.URL))
+	_, err := c.GetSlot(context.Background())
+	assert.NotNil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(count))
+}
+
+func Test_parseRetryAfter(t *testing.T) {
+	assert.Equal(t, time.Duration(0), parseRetryAfter(""))
+	assert.Equal(t, 3*time.Second, parseRetryAfter("3"))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("-1"))
+	assert.Equal(t, time.Duration(0), parseRetryAfter("Wed, 21 Oct 2015 07:28:00 GMT"))
+
+	d := parseRetryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	assert.Greater(t, d, 50*time.Second)
+}
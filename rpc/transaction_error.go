@@ -0,0 +1,275 @@
+package rpc
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// transaction error kinds, see solana-sdk TransactionError
+const (
+	TransactionErrorAccountInUse                          = "AccountInUse"
+	TransactionErrorAccountLoadedTwice                    = "AccountLoadedTwice"
+	TransactionErrorAccountNotFound                       = "AccountNotFound"
+	TransactionErrorProgramAccountNotFound                = "ProgramAccountNotFound"
+	TransactionErrorInsufficientFundsForFee               = "InsufficientFundsForFee"
+	TransactionErrorInvalidAccountForFee                  = "InvalidAccountForFee"
+	TransactionErrorAlreadyProcessed                      = "AlreadyProcessed"
+	TransactionErrorBlockhashNotFound                     = "BlockhashNotFound"
+	TransactionErrorInstructionError                      = "InstructionError"
+	TransactionErrorCallChainTooDeep                      = "CallChainTooDeep"
+	TransactionErrorMissingSignatureForFee                = "MissingSignatureForFee"
+	TransactionErrorInvalidAccountIndex                   = "InvalidAccountIndex"
+	TransactionErrorSignatureFailure                      = "SignatureFailure"
+	TransactionErrorInvalidProgramForExecution            = "InvalidProgramForExecution"
+	TransactionErrorSanitizeFailure                       = "SanitizeFailure"
+	TransactionErrorClusterMaintenance                    = "ClusterMaintenance"
+	TransactionErrorAccountBorrowOutstanding              = "AccountBorrowOutstanding"
+	TransactionErrorWouldExceedMaxBlockCostLimit          = "WouldExceedMaxBlockCostLimit"
+	TransactionErrorUnsupportedVersion                    = "UnsupportedVersion"
+	TransactionErrorInvalidWritableAccount                = "InvalidWritableAccount"
+	TransactionErrorWouldExceedMaxAccountCostLimit        = "WouldExceedMaxAccountCostLimit"
+	TransactionErrorWouldExceedAccountDataBlockLimit      = "WouldExceedAccountDataBlockLimit"
+	TransactionErrorTooManyAccountLocks                   = "TooManyAccountLocks"
+	TransactionErrorAddressLookupTableNotFound            = "AddressLookupTableNotFound"
+	TransactionErrorInvalidAddressLookupTableOwner        = "InvalidAddressLookupTableOwner"
+	TransactionErrorInvalidAddressLookupTableData         = "InvalidAddressLookupTableData"
+	TransactionErrorInvalidAddressLookupTableIndex        = "InvalidAddressLookupTableIndex"
+	TransactionErrorInvalidRentPayingAccount              = "InvalidRentPayingAccount"
+	TransactionErrorWouldExceedMaxVoteCostLimit           = "WouldExceedMaxVoteCostLimit"
+	TransactionErrorWouldExceedAccountDataTotalLimit      = "WouldExceedAccountDataTotalLimit"
+	TransactionErrorDuplicateInstruction                  = "DuplicateInstruction"
+	TransactionErrorInsufficientFundsForRent              = "InsufficientFundsForRent"
+	TransactionErrorMaxLoadedAccountsDataSizeExceeded     = "MaxLoadedAccountsDataSizeExceeded"
+	TransactionErrorInvalidLoadedAccountsDataSizeLimit    = "InvalidLoadedAccountsDataSizeLimit"
+	TransactionErrorResanitizationNeeded                  = "ResanitizationNeeded"
+	TransactionErrorProgramExecutionTemporarilyRestricted = "ProgramExecutionTemporarilyRestricted"
+	TransactionErrorUnbalancedTransaction                 = "UnbalancedTransaction"
+	TransactionErrorProgramCacheHitMaxLimit               = "ProgramCacheHitMaxLimit"
+)
+
+// sentinel errors of TransactionError, use them with errors.Is
+var (
+	ErrTransactionAccountInUse                 = errors.New("transaction: account in use")
+	ErrTransactionAccountNotFound              = errors.New("transaction: account not found")
+	ErrTransactionInsufficientFundsForFee      = errors.New("transaction: insufficient funds for fee")
+	ErrTransactionAlreadyProcessed             = errors.New("transaction: already processed")
+	ErrTransactionBlockhashNotFound            = errors.New("transaction: blockhash not found")
+	ErrTransactionInstructionError             = errors.New("transaction: instruction error")
+	ErrTransactionSignatureFailure             = errors.New("transaction: signature failure")
+	ErrTransactionInsufficientFundsForRent     = errors.New("transaction: insufficient funds for rent")
+	ErrTransactionWouldExceedMaxBlockCostLimit = errors.New("transaction: would exceed max block cost limit")
+)
+
+var transactionErrorKindToSentinel = map[string]error{
+	TransactionErrorAccountInUse:                 ErrTransactionAccountInUse,
+	TransactionErrorAccountNotFound:              ErrTransactionAccountNotFound,
+	TransactionErrorInsufficientFundsForFee:      ErrTransactionInsufficientFundsForFee,
+	TransactionErrorAlreadyProcessed:             ErrTransactionAlreadyProcessed,
+	TransactionErrorBlockhashNotFound:            ErrTransactionBlockhashNotFound,
+	TransactionErrorInstructionError:             ErrTransactionInstructionError,
+	TransactionErrorSignatureFailure:             ErrTransactionSignatureFailure,
+	TransactionErrorInsufficientFundsForRent:     ErrTransactionInsufficientFundsForRent,
+	TransactionErrorWouldExceedMaxBlockCostLimit: ErrTransactionWouldExceedMaxBlockCostLimit,
+}
+
+// TransactionError is the `err` of a transaction status, simulation or preflight failure.
+// the node encodes it as a string for unit variants, e.g. "BlockhashNotFound", or as
+// an object for variants with data, e.g. {"InstructionError":[0,{"Custom":1}]}.
+type TransactionError struct {
+	Kind string
+	// InstructionError is set when Kind is InstructionError
+	InstructionError *InstructionError
+	// Index is the instruction index of DuplicateInstruction
+	Index *uint8
+	// AccountIndex is the account index of InsufficientFundsForRent and
+	// ProgramExecutionTemporarilyRestricted
+	AccountIndex *uint8
+	// Data is the raw payload of a variant this package doesn't know
+	Data json.RawMessage
+}
+
+// ParseTransactionError converts a decoded `err` field into a TransactionError.
+// it returns nil if v is nil.
+func ParseTransactionError(v any) (*TransactionError, error) {
+	if v == nil {
+		return nil, nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal transaction error, err: %v", err)
+	}
+	var e TransactionError
+	if err := json.Unmarshal(b, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (e *TransactionError) Error() string {
+	switch {
+	case e.InstructionError != nil:
+		return e.InstructionError.Error()
+	case e.Index != nil:
+		return fmt.Sprintf("%v at instruction #%v", e.Kind, *e.Index)
+	case e.AccountIndex != nil:
+		return fmt.Sprintf("%v at account #%v", e.Kind, *e.AccountIndex)
+	case len(e.Data) > 0:
+		return fmt.Sprintf("%v: %s", e.Kind, e.Data)
+	}
+	return e.Kind
+}
+
+func (e *TransactionError) Is(target error) bool {
+	sentinel, ok := transactionErrorKindToSentinel[e.Kind]
+	return ok && sentinel == target
+}
+
+func (e *TransactionError) Unwrap() error {
+	if e.InstructionError == nil {
+		return nil
+	}
+	return e.InstructionError
+}
+
+func (e *TransactionError) UnmarshalJSON(b []byte) error {
+	*e = TransactionError{}
+
+	var kind string
+	if err := json.Unmarshal(b, &kind); err == nil {
+		e.Kind = kind
+		return nil
+	}
+
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil || len(m) != 1 {
+		return fmt.Errorf("unexpected transaction error: %s", b)
+	}
+	for k, v := range m {
+		e.Kind = k
+		switch k {
+		case TransactionErrorInstructionError:
+			var ie InstructionError
+			if err := json.Unmarshal(v, &ie); err != nil {
+				return err
+			}
+			e.InstructionError = &ie
+		case TransactionErrorDuplicateInstruction:
+			var index uint8
+			if err := json.Unmarshal(v, &index); err != nil {
+				return fmt.Errorf("failed to parse %v, err: %v", k, err)
+			}
+			e.Index = &index
+		case TransactionErrorInsufficientFundsForRent, TransactionErrorProgramExecutionTemporarilyRestricted:
+			var data struct {
+				AccountIndex uint8 `json:"account_index"`
+			}
+			if err := json.Unmarshal(v, &data); err != nil {
+				return fmt.Errorf("failed to parse %v, err: %v", k, err)
+			}
+			e.AccountIndex = &data.AccountIndex
+		default:
+			e.Data = append(json.RawMessage{}, v...)
+		}
+	}
+	return nil
+}
+
+func (e TransactionError) MarshalJSON() ([]byte, error) {
+	var payload any
+	switch {
+	case e.InstructionError != nil:
+		payload = e.InstructionError
+	case e.Index != nil:
+		payload = *e.Index
+	case e.AccountIndex != nil:
+		payload = map[string]uint8{"account_index": *e.AccountIndex}
+	case len(e.Data) > 0:
+		payload = e.Data
+	default:
+		return json.Marshal(e.Kind)
+	}
+	return json.Marshal(map[string]any{e.Kind: payload})
+}
+
+// instruction error kinds which carry data
+const (
+	InstructionErrorCustom       = "Custom"
+	InstructionErrorBorshIoError = "BorshIoError"
+)
+
+// InstructionError is the error of a failed instruction, encoded by the node as
+// [index, kind], e.g. [0,"InvalidAccountData"] or [1,{"Custom":6001}]
+type InstructionError struct {
+	// Index is the index of the failed instruction in the message
+	Index uint8
+	Kind  string
+	// Custom is the program defined error code when Kind is Custom
+	Custom *uint32
+	// Message is set when Kind is BorshIoError
+	Message string
+	// Data is the raw payload of a variant this package doesn't know
+	Data json.RawMessage
+}
+
+func (e *InstructionError) Error() string {
+	switch {
+	case e.Custom != nil:
+		return fmt.Sprintf("instruction #%v failed: custom program error: %#x", e.Index, *e.Custom)
+	case e.Message != "":
+		return fmt.Sprintf("instruction #%v failed: %v: %v", e.Index, e.Kind, e.Message)
+	}
+	return fmt.Sprintf("instruction #%v failed: %v", e.Index, e.Kind)
+}
+
+func (e *InstructionError) UnmarshalJSON(b []byte) error {
+	*e = InstructionError{}
+
+	var tuple []json.RawMessage
+	if err := json.Unmarshal(b, &tuple); err != nil || len(tuple) != 2 {
+		return fmt.Errorf("unexpected instruction error: %s", b)
+	}
+	if err := json.Unmarshal(tuple[0], &e.Index); err != nil {
+		return fmt.Errorf("failed to parse instruction index, err: %v", err)
+	}
+
+	if err := json.Unmarshal(tuple[1], &e.Kind); err == nil {
+		return nil
+	}
+
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(tuple[1], &m); err != nil || len(m) != 1 {
+		return fmt.Errorf("unexpected instruction error: %s", tuple[1])
+	}
+	for k, v := range m {
+		e.Kind = k
+		switch k {
+		case InstructionErrorCustom:
+			var code uint32
+			if err := json.Unmarshal(v, &code); err != nil {
+				return fmt.Errorf("failed to parse custom error code, err: %v", err)
+			}
+			e.Custom = &code
+		case InstructionErrorBorshIoError:
+			if err := json.Unmarshal(v, &e.Message); err != nil {
+				return fmt.Errorf("failed to parse %v, err: %v", k, err)
+			}
+		default:
+			e.Data = append(json.RawMessage{}, v...)
+		}
+	}
+	return nil
+}
+
+func (e InstructionError) MarshalJSON() ([]byte, error) {
+	var kind any = e.Kind
+	switch {
+	case e.Custom != nil:
+		kind = map[string]uint32{e.Kind: *e.Custom}
+	case e.Kind == InstructionErrorBorshIoError:
+		kind = map[string]string{e.Kind: e.Message}
+	case len(e.Data) > 0:
+		kind = map[string]json.RawMessage{e.Kind: e.Data}
+	}
+	return json.Marshal([]any{e.Index, kind})
+}
@@ -7,6 +7,8 @@ import (
 	"fmt"
 
 	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/pkg/bip39"
+	"github.com/labyla/solana-go-sdk/pkg/hdwallet"
 	"github.com/mr-tron/base58"
 )
 
@@ -64,6 +66,34 @@ func AccountFromSeed(seed []byte) (Account, error) {
 	return AccountFromBytes(pk)
 }
 
+// AccountFromHDSeed generate a account by a bip39 seed and a derivation path, e.g. m/44'/501'/0'/0'
+// which is the first account of phantom. an empty path uses the first 32 bytes of the seed
+// as the key, like solana-keygen does without a derivation path.
+func AccountFromHDSeed(seed []byte, path string) (Account, error) {
+	if path == "" {
+		if len(seed) < ed25519.SeedSize {
+			return Account{}, fmt.Errorf("%w, expected at least: %v, got: %v", ErrAccountPrivateKeyLengthMismatch, ed25519.SeedSize, len(seed))
+		}
+		return AccountFromSeed(seed[:ed25519.SeedSize])
+	}
+	key, err := hdwallet.Derived(path, seed)
+	if err != nil {
+		return Account{}, err
+	}
+	return AccountFromSeed(key.PrivateKey)
+}
+
+// AccountFromMnemonic generate a account by an English bip39 mnemonic, an optional passphrase
+// and a derivation path, see AccountFromHDSeed. use bip39.Wordlist.NewSeed and
+// AccountFromHDSeed for other languages.
+func AccountFromMnemonic(mnemonic, passphrase, path string) (Account, error) {
+	seed, err := bip39.English.NewSeed(mnemonic, passphrase)
+	if err != nil {
+		return Account{}, err
+	}
+	return AccountFromHDSeed(seed, path)
+}
+
 func (a Account) Sign(message []byte) []byte {
 	return ed25519.Sign(a.PrivateKey, message)
 }
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/labyla/solana-go-sdk/common"
+	"github.com/labyla/solana-go-sdk/pkg/bip39"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -132,3 +133,33 @@ func TestAccount_Sign(t *testing.T) {
 		})
 	}
 }
+
+func TestAccountFromMnemonic(t *testing.T) {
+	// phantom
+	account, err := AccountFromMnemonic("neither lonely flavor argue grass remind eye tag avocado spot unusual intact", "", "m/44'/501'/0'/0'")
+	assert.Nil(t, err)
+	assert.Equal(t, "5vftMkHL72JaJG6ExQfGAsT2uGVHpRR7oTNUPMs68Y2N", account.PublicKey.ToBase58())
+	account, err = AccountFromMnemonic("neither lonely flavor argue grass remind eye tag avocado spot unusual intact", "", "m/44'/501'/9'/0'")
+	assert.Nil(t, err)
+	assert.Equal(t, "6frdqXQAgJMyKwmZxkLYbdGjnYTvUceh6LNhkQt2siQp", account.PublicKey.ToBase58())
+
+	// solana-keygen without a derivation path
+	seed := bip39.NewSeed("pill tomorrow foster begin walnut borrow virtual kick shift mutual shoe scatter", "")
+	want, err := AccountFromSeed(seed[:32])
+	assert.Nil(t, err)
+	account, err = AccountFromMnemonic("pill tomorrow foster begin walnut borrow virtual kick shift mutual shoe scatter", "", "")
+	assert.Nil(t, err)
+	assert.Equal(t, want, account)
+
+	// a passphrase gives another account
+	other, err := AccountFromMnemonic("pill tomorrow foster begin walnut borrow virtual kick shift mutual shoe scatter", "passphrase", "")
+	assert.Nil(t, err)
+	assert.NotEqual(t, account.PublicKey, other.PublicKey)
+
+	_, err = AccountFromMnemonic("pill tomorrow foster begin walnut borrow virtual kick shift mutual shoe abandon", "", "")
+	assert.ErrorIs(t, err, bip39.ErrInvalidChecksum)
+	_, err = AccountFromMnemonic("pill tomorrow foster begin walnut borrow virtual kick shift mutual shoe scatter", "", "m/44/501")
+	assert.Error(t, err)
+	_, err = AccountFromHDSeed(seed[:31], "")
+	assert.ErrorIs(t, err, ErrAccountPrivateKeyLengthMismatch)
+}
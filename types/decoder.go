@@ -0,0 +1,83 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrDeserializeUnexpectedEnd     = errors.New("unexpected end of data")
+	ErrDeserializeTrailingData      = errors.New("trailing data")
+	ErrDeserializeInvalidCompactU16 = errors.New("invalid compact-u16")
+)
+
+// decoder reads the wire format of transactions. every read is bounds checked.
+type decoder struct {
+	data   []byte
+	offset int
+}
+
+func (d *decoder) remaining() int {
+	return len(d.data) - d.offset
+}
+
+func (d *decoder) readByte() (byte, error) {
+	if d.remaining() < 1 {
+		return 0, ErrDeserializeUnexpectedEnd
+	}
+	b := d.data[d.offset]
+	d.offset++
+	return b, nil
+}
+
+func (d *decoder) readBytes(n int) ([]byte, error) {
+	if n < 0 || d.remaining() < n {
+		return nil, fmt.Errorf("%w: need %v bytes, %v left", ErrDeserializeUnexpectedEnd, n, d.remaining())
+	}
+	b := d.data[d.offset : d.offset+n : d.offset+n]
+	d.offset += n
+	return b, nil
+}
+
+// readCompactU16 reads a length in the short_vec encoding, at most 3 bytes. the encoding
+// must be the shortest one, the same as the validator.
+func (d *decoder) readCompactU16() (int, error) {
+	v := 0
+	for i := 0; i < 3; i++ {
+		b, err := d.readByte()
+		if err != nil {
+			return 0, err
+		}
+		if i == 2 && b > 0x03 {
+			return 0, fmt.Errorf("%w: overflow", ErrDeserializeInvalidCompactU16)
+		}
+		v |= int(b&0x7f) << (7 * i)
+		if b&0x80 == 0 {
+			if b == 0 && i > 0 {
+				return 0, fmt.Errorf("%w: not the shortest encoding", ErrDeserializeInvalidCompactU16)
+			}
+			return v, nil
+		}
+	}
+	return 0, fmt.Errorf("%w: too long", ErrDeserializeInvalidCompactU16)
+}
+
+// readLength reads a compact-u16 length of items which take at least itemSize bytes each,
+// so a forged length can't make the caller allocate more than the data could hold
+func (d *decoder) readLength(itemSize int) (int, error) {
+	n, err := d.readCompactU16()
+	if err != nil {
+		return 0, err
+	}
+	if n*itemSize > d.remaining() {
+		return 0, fmt.Errorf("%w: %v items of at least %v bytes, %v bytes left", ErrDeserializeUnexpectedEnd, n, itemSize, d.remaining())
+	}
+	return n, nil
+}
+
+func (d *decoder) finish() error {
+	if d.remaining() != 0 {
+		return fmt.Errorf("%w: %v bytes", ErrDeserializeTrailingData, d.remaining())
+	}
+	return nil
+}
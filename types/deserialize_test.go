@@ -0,0 +1,116 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestV0Transaction(t *testing.T) []byte {
+	feePayer := NewAccount()
+	keys := []common.PublicKey{NewAccount().PublicKey, NewAccount().PublicKey, NewAccount().PublicKey}
+	message := NewMessage(NewMessageParam{
+		FeePayer: feePayer.PublicKey,
+		Instructions: []Instruction{
+			{
+				ProgramID: common.MemoProgramID,
+				Accounts: []AccountMeta{
+					{PubKey: keys[0], IsWritable: true},
+					{PubKey: keys[1], IsWritable: false},
+					{PubKey: keys[2], IsWritable: true},
+				},
+				Data: []byte("hello"),
+			},
+		},
+		RecentBlockhash: "9rAtxuhtKn8qagc3UtZFyhLrw5zkh6etv43TibaXuSKo",
+		AddressLookupTableAccounts: []AddressLookupTableAccount{
+			{Key: NewAccount().PublicKey, Addresses: keys[:2]},
+		},
+	})
+	tx, err := NewTransaction(NewTransactionParam{Message: message, Signers: []Signer{feePayer}})
+	assert.Nil(t, err)
+	b, err := tx.Serialize()
+	assert.Nil(t, err)
+	return b
+}
+
+// adversarial inputs, each must fail with the error, or any error if nil
+var deserializeCorpus = []struct {
+	name string
+	data []byte
+	err  error
+}{
+	{name: "empty", data: []byte{}, err: ErrDeserializeUnexpectedEnd},
+	{name: "no signature", data: []byte{0}},
+	{name: "signature count larger than data", data: append([]byte{2}, make([]byte, 64)...), err: ErrDeserializeUnexpectedEnd},
+	{name: "huge signature count", data: []byte{0xff, 0xff, 0x03}, err: ErrDeserializeUnexpectedEnd},
+	{name: "compact-u16 not shortest", data: []byte{0x81, 0x00}, err: ErrDeserializeInvalidCompactU16},
+	{name: "compact-u16 overflow", data: []byte{0xff, 0xff, 0x04}, err: ErrDeserializeInvalidCompactU16},
+	{name: "compact-u16 too long", data: []byte{0x80, 0x80, 0x80, 0x01}, err: ErrDeserializeInvalidCompactU16},
+	{name: "message missing", data: append([]byte{1}, make([]byte, 64)...)},
+	{name: "unknown version", data: append(append([]byte{1}, make([]byte, 64)...), 0x81, 1, 0, 0), err: ErrMessageUnsupportedVersion},
+	{name: "max version", data: append(append([]byte{1}, make([]byte, 64)...), 0xff, 1, 0, 0), err: ErrMessageUnsupportedVersion},
+	{name: "header truncated", data: append(append([]byte{1}, make([]byte, 64)...), 1, 0), err: ErrDeserializeUnexpectedEnd},
+	{name: "huge account count", data: append(append([]byte{1}, make([]byte, 64)...), 1, 0, 0, 0xff, 0xff, 0x03), err: ErrDeserializeUnexpectedEnd},
+	{name: "account truncated", data: append(append(append([]byte{1}, make([]byte, 64)...), 1, 0, 0, 1), make([]byte, 31)...), err: ErrDeserializeUnexpectedEnd},
+	{name: "blockhash truncated", data: append(append(append([]byte{1}, make([]byte, 64)...), 1, 0, 0, 1), make([]byte, 40)...), err: ErrDeserializeUnexpectedEnd},
+	{name: "huge instruction count", data: append(append(append([]byte{1}, make([]byte, 64)...), 1, 0, 0, 1), append(make([]byte, 64), 0xff, 0xff, 0x03)...), err: ErrDeserializeUnexpectedEnd},
+	{name: "instruction data truncated", data: append(append(append([]byte{1}, make([]byte, 64)...), 1, 0, 0, 1), append(make([]byte, 64), 1, 0, 0, 10, 1, 2)...), err: ErrDeserializeUnexpectedEnd},
+	{name: "instruction accounts truncated", data: append(append(append([]byte{1}, make([]byte, 64)...), 1, 0, 0, 1), append(make([]byte, 64), 1, 0, 5, 0)...), err: ErrDeserializeUnexpectedEnd},
+	{name: "signature count mismatch", data: append(append(append([]byte{1}, make([]byte, 64)...), 2, 0, 0, 1), append(make([]byte, 64), 0)...)},
+	{name: "legacy trailing byte", data: append(append(append([]byte{1}, make([]byte, 64)...), 1, 0, 0, 1), append(make([]byte, 64), 0, 0)...), err: ErrDeserializeTrailingData},
+	{name: "v0 without lookup table count", data: append(append(append([]byte{1}, make([]byte, 64)...), 0x80, 1, 0, 0, 1), append(make([]byte, 64), 0)...), err: ErrDeserializeUnexpectedEnd},
+	{name: "v0 huge lookup table count", data: append(append(append([]byte{1}, make([]byte, 64)...), 0x80, 1, 0, 0, 1), append(make([]byte, 64), 0, 0xff, 0x01)...), err: ErrDeserializeUnexpectedEnd},
+	{name: "v0 lookup table indexes truncated", data: append(append(append([]byte{1}, make([]byte, 64)...), 0x80, 1, 0, 0, 1), append(make([]byte, 64), append([]byte{0, 1}, append(make([]byte, 32), 3, 1)...)...)...), err: ErrDeserializeUnexpectedEnd},
+}
+
+func TestTransactionDeserialize_Corpus(t *testing.T) {
+	for _, tt := range deserializeCorpus {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := TransactionDeserialize(tt.data)
+			assert.Error(t, err)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestTransactionDeserialize_Truncated(t *testing.T) {
+	b := newTestV0Transaction(t)
+	_, err := TransactionDeserialize(b)
+	assert.Nil(t, err)
+
+	for i := 0; i < len(b); i++ {
+		_, err := TransactionDeserialize(b[:i])
+		assert.Error(t, err, "truncated at %v", i)
+	}
+
+	_, err = TransactionDeserialize(append(b, 0))
+	assert.ErrorIs(t, err, ErrDeserializeTrailingData)
+
+	// message alone
+	_, err = MessageDeserialize(append(b[65:], 0))
+	assert.ErrorIs(t, err, ErrDeserializeTrailingData)
+}
+
+func FuzzTransactionDeserialize(f *testing.F) {
+	for _, tt := range deserializeCorpus {
+		f.Add(tt.data)
+	}
+	f.Fuzz(func(t *testing.T, data []byte) {
+		tx, err := TransactionDeserialize(data)
+		if err != nil {
+			return
+		}
+		// whatever decodes must encode and decode again
+		b, err := tx.Serialize()
+		if err != nil {
+			t.Fatalf("failed to serialize a decoded tx, err: %v", err)
+		}
+		if _, err := TransactionDeserialize(b); err != nil {
+			t.Fatalf("failed to decode a serialized tx, err: %v", err)
+		}
+	})
+}
@@ -0,0 +1,96 @@
+package types
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+var ErrAccountPublicKeyMismatch = errors.New("public key mismatch")
+
+// AccountFromKeypairJSON generate a account by the content of a keypair file of solana-keygen,
+// a json array of the 64 bytes private key, e.g. [12,34,...]
+func AccountFromKeypairJSON(data []byte) (Account, error) {
+	// json decodes a []byte from a base64 string, not from an array
+	var numbers []json.Number
+	if err := json.Unmarshal(data, &numbers); err != nil {
+		return Account{}, fmt.Errorf("failed to parse keypair, err: %v", err)
+	}
+	key := make([]byte, 0, len(numbers))
+	defer zeroize(key[:cap(key)])
+	for _, n := range numbers {
+		v, err := strconv.ParseUint(n.String(), 10, 8)
+		if err != nil {
+			return Account{}, fmt.Errorf("failed to parse keypair, invalid byte %v", n)
+		}
+		key = append(key, byte(v))
+	}
+
+	if len(key) != ed25519.PrivateKeySize {
+		return Account{}, fmt.Errorf("%w, expected: %v, got: %v", ErrAccountPrivateKeyLengthMismatch, ed25519.PrivateKeySize, len(key))
+	}
+	account, err := AccountFromSeed(key[:ed25519.SeedSize])
+	if err != nil {
+		return Account{}, err
+	}
+	if !bytes.Equal(account.PublicKey.Bytes(), key[ed25519.SeedSize:]) {
+		account.Zeroize()
+		return Account{}, fmt.Errorf("%w, the keypair doesn't belong to its private key", ErrAccountPublicKeyMismatch)
+	}
+	return account, nil
+}
+
+// AccountFromKeypairFile generate a account by a keypair file of solana-keygen, e.g. ~/.config/solana/id.json
+func AccountFromKeypairFile(path string) (Account, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Account{}, err
+	}
+	defer zeroize(data)
+	return AccountFromKeypairJSON(data)
+}
+
+// KeypairJSON returns the private key in the format of a solana-keygen keypair file
+func (a Account) KeypairJSON() []byte {
+	b := make([]byte, 0, 4*len(a.PrivateKey)+2)
+	b = append(b, '[')
+	for i, v := range a.PrivateKey {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		b = strconv.AppendUint(b, uint64(v), 10)
+	}
+	return append(b, ']')
+}
+
+// SaveKeypairFile writes the account as a keypair file of solana-keygen, readable by the owner only.
+// an existing file is not overwritten.
+func (a Account) SaveKeypairFile(path string) error {
+	data := a.KeypairJSON()
+	defer zeroize(data)
+
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Zeroize overwrites the private key, the account can't sign anymore
+func (a Account) Zeroize() {
+	zeroize(a.PrivateKey)
+}
+
+func zeroize(b []byte) {
+	for i := range b {
+		b[i] = 0
+	}
+}
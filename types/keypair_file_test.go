@@ -0,0 +1,66 @@
+package types
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountFromKeypairFile(t *testing.T) {
+	// testdata/keypair.json was not written by this package, it is a fresh ed25519-dalek keypair serialized with
+	// serde_json::to_string as solana-keygen does. the public key is the one ed25519-dalek derived for it.
+	account, err := AccountFromKeypairFile("testdata/keypair.json")
+	require.Nil(t, err)
+	assert.Equal(t, "9gWpGtTb6xXgcPw8FEhpR35T3Z8xCBMkRR11LR36QCAQ", account.PublicKey.ToBase58())
+
+	// it is written back byte for byte
+	data, err := os.ReadFile("testdata/keypair.json")
+	require.Nil(t, err)
+	assert.Equal(t, string(data), string(account.KeypairJSON()))
+
+	path := filepath.Join(t.TempDir(), "id.json")
+	require.Nil(t, account.SaveKeypairFile(path))
+	info, err := os.Stat(path)
+	require.Nil(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+	saved, err := AccountFromKeypairFile(path)
+	require.Nil(t, err)
+	assert.Equal(t, account, saved)
+	assert.Error(t, account.SaveKeypairFile(path), "never overwrite a key")
+
+	saved.Zeroize()
+	assert.Equal(t, make([]byte, 64), []byte(saved.PrivateKey))
+}
+
+func TestAccountFromKeypairJSON_Invalid(t *testing.T) {
+	data, err := os.ReadFile("testdata/keypair.json")
+	require.Nil(t, err)
+	// flip a byte of the public key
+	tampered := append([]byte{}, data...)
+	tampered[len(tampered)-2] = '0'
+
+	tests := []struct {
+		name string
+		data string
+		err  error
+	}{
+		{name: "mismatch", data: string(tampered), err: ErrAccountPublicKeyMismatch},
+		{name: "short", data: "[1,2,3]", err: ErrAccountPrivateKeyLengthMismatch},
+		{name: "out of range", data: "[256]"},
+		{name: "negative", data: "[-1]"},
+		{name: "base64", data: `"AAAA"`},
+		{name: "empty", data: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := AccountFromKeypairJSON([]byte(tt.data))
+			assert.Error(t, err)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
@@ -105,30 +105,144 @@ func (m *Message) Serialize() ([]byte, error) {
 	return b, nil
 }
 
-// DecompileInstructions hasn't support v0 message decode
+// TransactionSize returns the size of a serialized transaction of the message, with all the
+// required signatures. an empty recent blockhash is counted as 32 bytes.
+func (m *Message) TransactionSize() (int, error) {
+	message := *m
+	if message.RecentBlockHash == "" {
+		message.RecentBlockHash = common.PublicKey{}.ToBase58()
+	}
+	b, err := message.Serialize()
+	if err != nil {
+		return 0, err
+	}
+	size := len(b)
+	numSignatures := uint64(m.Header.NumRequireSignatures)
+	return size + len(bincode.UintToVarLenBytes(numSignatures)) + int(numSignatures)*64, nil
+}
+
+// NumAccounts returns the number of accounts the message references, static and loaded
+func (m *Message) NumAccounts() int {
+	n := len(m.Accounts)
+	for _, lookup := range m.AddressLookupTables {
+		n += len(lookup.WritableIndexes) + len(lookup.ReadonlyIndexes)
+	}
+	return n
+}
+
+var (
+	ErrMessageInvalidHeader           = errors.New("invalid message header")
+	ErrMessageIndexOutOfRange         = errors.New("index out of range")
+	ErrMessageLoadedAddressesMismatch = errors.New("loaded addresses mismatch")
+	ErrMessageLookupTableNotFound     = errors.New("address lookup table not found")
+	ErrMessageUnsupportedVersion      = errors.New("unsupported message version")
+)
+
+// LoadedAddresses are the addresses loaded from the address lookup tables of a v0 message.
+// all writable addresses of every table come first, then the readonly ones, which is also
+// how they are returned in the meta of getTransaction.
+type LoadedAddresses struct {
+	Writable []common.PublicKey
+	Readonly []common.PublicKey
+}
+
+// DecompileInstructions converts compiled instructions back to instructions.
+// it returns nil if the message is malformed or is a v0 message which loads addresses.
+//
+// Deprecated: use DecompileInstructionsWithLoadedAddresses, which reports why the message can't be decompiled.
 func (m *Message) DecompileInstructions() []Instruction {
-	switch m.Version {
-	case MessageVersionLegacy:
-		return m.decompileLegacyMessageInstructions()
-	case MessageVersionV0:
-		panic("hasn't supported")
-	default:
-		return m.decompileLegacyMessageInstructions()
+	instructions, err := m.DecompileInstructionsWithLoadedAddresses(LoadedAddresses{})
+	if err != nil {
+		return nil
+	}
+	return instructions
+}
+
+// ResolveLoadedAddresses looks up the addresses of the message in the given lookup table accounts
+func (m Message) ResolveLoadedAddresses(addressLookupTableAccounts []AddressLookupTableAccount) (LoadedAddresses, error) {
+	tables := make(map[common.PublicKey][]common.PublicKey, len(addressLookupTableAccounts))
+	for _, account := range addressLookupTableAccounts {
+		tables[account.Key] = account.Addresses
+	}
+
+	loaded := LoadedAddresses{}
+	for _, lookup := range m.AddressLookupTables {
+		addresses, ok := tables[lookup.AccountKey]
+		if !ok {
+			return LoadedAddresses{}, fmt.Errorf("%w: %v", ErrMessageLookupTableNotFound, lookup.AccountKey)
+		}
+		for _, idx := range lookup.WritableIndexes {
+			if int(idx) >= len(addresses) {
+				return LoadedAddresses{}, fmt.Errorf("%w: table %v has %v addresses, writable index %v", ErrMessageIndexOutOfRange, lookup.AccountKey, len(addresses), idx)
+			}
+			loaded.Writable = append(loaded.Writable, addresses[idx])
+		}
+	}
+	for _, lookup := range m.AddressLookupTables {
+		addresses := tables[lookup.AccountKey]
+		for _, idx := range lookup.ReadonlyIndexes {
+			if int(idx) >= len(addresses) {
+				return LoadedAddresses{}, fmt.Errorf("%w: table %v has %v addresses, readonly index %v", ErrMessageIndexOutOfRange, lookup.AccountKey, len(addresses), idx)
+			}
+			loaded.Readonly = append(loaded.Readonly, addresses[idx])
+		}
 	}
+	return loaded, nil
 }
 
-func (m Message) decompileLegacyMessageInstructions() []Instruction {
+// DecompileInstructionsWithLoadedAddresses converts compiled instructions back to instructions.
+// loaded are the addresses of the lookup tables, which come from the transaction meta or from
+// ResolveLoadedAddresses. it works for legacy messages as well, loaded must be empty then.
+func (m Message) DecompileInstructionsWithLoadedAddresses(loaded LoadedAddresses) ([]Instruction, error) {
+	numStatic := len(m.Accounts)
+	if int(m.Header.NumRequireSignatures) > numStatic ||
+		m.Header.NumReadonlySignedAccounts > m.Header.NumRequireSignatures ||
+		int(m.Header.NumReadonlyUnsignedAccounts) > numStatic-int(m.Header.NumRequireSignatures) {
+		return nil, fmt.Errorf("%w: %+v with %v accounts", ErrMessageInvalidHeader, m.Header, numStatic)
+	}
+
+	numWritable, numReadonly := 0, 0
+	for _, lookup := range m.AddressLookupTables {
+		numWritable += len(lookup.WritableIndexes)
+		numReadonly += len(lookup.ReadonlyIndexes)
+	}
+	if len(loaded.Writable) != numWritable || len(loaded.Readonly) != numReadonly {
+		return nil, fmt.Errorf("%w: message loads %v writable and %v readonly, got %v and %v",
+			ErrMessageLoadedAddressesMismatch, numWritable, numReadonly, len(loaded.Writable), len(loaded.Readonly))
+	}
+
+	numAccounts := numStatic + numWritable + numReadonly
+	accountMeta := func(idx int) (AccountMeta, error) {
+		switch {
+		case idx < 0 || idx >= numAccounts:
+			return AccountMeta{}, fmt.Errorf("%w: account index %v, %v accounts", ErrMessageIndexOutOfRange, idx, numAccounts)
+		case idx < numStatic:
+			return AccountMeta{
+				PubKey:   m.Accounts[idx],
+				IsSigner: idx < int(m.Header.NumRequireSignatures),
+				IsWritable: idx < int(m.Header.NumRequireSignatures-m.Header.NumReadonlySignedAccounts) ||
+					(idx >= int(m.Header.NumRequireSignatures) && idx < numStatic-int(m.Header.NumReadonlyUnsignedAccounts)),
+			}, nil
+		case idx < numStatic+numWritable:
+			return AccountMeta{PubKey: loaded.Writable[idx-numStatic], IsWritable: true}, nil
+		default:
+			return AccountMeta{PubKey: loaded.Readonly[idx-numStatic-numWritable]}, nil
+		}
+	}
+
 	instructions := make([]Instruction, 0, len(m.Instructions))
-	for _, cins := range m.Instructions {
+	for i, cins := range m.Instructions {
+		// the program must be a static account
+		if cins.ProgramIDIndex < 0 || cins.ProgramIDIndex >= numStatic {
+			return nil, fmt.Errorf("instruction #%v: %w: program id index %v, %v static accounts", i, ErrMessageIndexOutOfRange, cins.ProgramIDIndex, numStatic)
+		}
 		accounts := make([]AccountMeta, 0, len(cins.Accounts))
-		for i := 0; i < len(cins.Accounts); i++ {
-			accounts = append(accounts, AccountMeta{
-				PubKey:   m.Accounts[cins.Accounts[i]],
-				IsSigner: cins.Accounts[i] < int(m.Header.NumRequireSignatures),
-				IsWritable: cins.Accounts[i] < int(m.Header.NumRequireSignatures-m.Header.NumReadonlySignedAccounts) ||
-					(cins.Accounts[i] >= int(m.Header.NumRequireSignatures) &&
-						cins.Accounts[i] < len(m.Accounts)-int(m.Header.NumReadonlyUnsignedAccounts)),
-			})
+		for _, idx := range cins.Accounts {
+			meta, err := accountMeta(idx)
+			if err != nil {
+				return nil, fmt.Errorf("instruction #%v: %w", i, err)
+			}
+			accounts = append(accounts, meta)
 		}
 		instructions = append(instructions, Instruction{
 			ProgramID: m.Accounts[cins.ProgramIDIndex],
@@ -136,82 +250,88 @@ func (m Message) decompileLegacyMessageInstructions() []Instruction {
 			Data:      cins.Data,
 		})
 	}
-	return instructions
+	return instructions, nil
 }
 
+// MessageDeserialize decodes a legacy or v0 message. the data must be exactly one message.
 func MessageDeserialize(messageData []byte) (Message, error) {
-	if len(messageData) == 0 {
+	d := &decoder{data: messageData}
+	message, err := deserializeMessage(d)
+	if err != nil {
+		return Message{}, err
+	}
+	if err := d.finish(); err != nil {
+		return Message{}, err
+	}
+	return message, nil
+}
+
+func deserializeMessage(d *decoder) (Message, error) {
+	if d.remaining() == 0 {
 		return Message{}, errors.New("empty message data")
 	}
 
-	var version MessageVersion
-	if v := uint8(messageData[0]); v > 127 {
-		version = MessageVersion(fmt.Sprintf("v%v", v-128))
-		messageData = messageData[1:]
-	} else {
-		version = MessageVersionLegacy
+	var version MessageVersion = MessageVersionLegacy
+	if prefix := d.data[d.offset]; prefix&0x80 != 0 {
+		d.offset++
+		if v := prefix & 0x7f; v != 0 {
+			return Message{}, fmt.Errorf("%w: v%v", ErrMessageUnsupportedVersion, v)
+		}
+		version = MessageVersionV0
 	}
 
-	var numRequireSignatures, numReadonlySignedAccounts, numReadonlyUnsignedAccounts uint8
-	var t uint64
-	var err error
-	list := []*uint8{&numRequireSignatures, &numReadonlySignedAccounts, &numReadonlyUnsignedAccounts}
-	for i := 0; i < len(list); i++ {
-		t, err = parseUvarint(&messageData)
-		if err != nil || t > 255 {
-			return Message{}, fmt.Errorf("message header #%d parse error: %v", i+1, err)
+	var header [3]uint8
+	for i := range header {
+		b, err := d.readByte()
+		if err != nil {
+			return Message{}, fmt.Errorf("message header #%d parse error: %w", i+1, err)
 		}
-		*list[i] = uint8(t)
+		header[i] = b
 	}
 
-	accountCount, err := parseUvarint(&messageData)
+	accountCount, err := d.readLength(32)
 	if err != nil {
-		return Message{}, fmt.Errorf("falied to parse count of account, err: %v", err)
-	}
-	if len(messageData) < int(accountCount)*32 {
-		return Message{}, errors.New("parse account error")
+		return Message{}, fmt.Errorf("failed to parse count of account, err: %w", err)
 	}
 	accounts := make([]common.PublicKey, 0, accountCount)
-	for i := 0; i < int(accountCount); i++ {
-		accounts = append(accounts, common.PublicKeyFromBytes(messageData[:32]))
-		messageData = messageData[32:]
+	for i := 0; i < accountCount; i++ {
+		b, _ := d.readBytes(32)
+		accounts = append(accounts, common.PublicKeyFromBytes(b))
 	}
 
-	if len(messageData) < 32 {
-		return Message{}, errors.New("parse blockhash error")
+	blockHash, err := d.readBytes(32)
+	if err != nil {
+		return Message{}, fmt.Errorf("parse blockhash error: %w", err)
 	}
-	blockHash := base58.Encode(messageData[:32])
-	messageData = messageData[32:]
 
-	instructionCount, err := parseUvarint(&messageData)
+	// an instruction is at least a program id index and two lengths
+	instructionCount, err := d.readLength(3)
 	if err != nil {
-		return Message{}, fmt.Errorf("parse instruction count error: %v", err)
+		return Message{}, fmt.Errorf("parse instruction count error: %w", err)
 	}
-
 	instructions := make([]CompiledInstruction, 0, instructionCount)
-	for i := 0; i < int(instructionCount); i++ {
-		programID, err := parseUvarint(&messageData)
+	for i := 0; i < instructionCount; i++ {
+		programID, err := d.readByte()
 		if err != nil {
-			return Message{}, fmt.Errorf("parse instruction #%d programID error: %v", i+1, err)
+			return Message{}, fmt.Errorf("parse instruction #%d programID error: %w", i+1, err)
 		}
-		accountCount, err := parseUvarint(&messageData)
+		accountCount, err := d.readLength(1)
 		if err != nil {
-			return Message{}, fmt.Errorf("parse instruction #%d account count error: %v", i+1, err)
+			return Message{}, fmt.Errorf("parse instruction #%d account count error: %w", i+1, err)
 		}
+		accountIdxs, _ := d.readBytes(accountCount)
 		accounts := make([]int, 0, accountCount)
-		for j := 0; j < int(accountCount); j++ {
-			accountIdx, err := parseUvarint(&messageData)
-			if err != nil {
-				return Message{}, fmt.Errorf("parse instruction #%d account #%d idx error: %v", i+1, j+1, err)
-			}
-			accounts = append(accounts, int(accountIdx))
+		for _, idx := range accountIdxs {
+			accounts = append(accounts, int(idx))
 		}
-		dataLen, err := parseUvarint(&messageData)
+		dataLen, err := d.readCompactU16()
 		if err != nil {
-			return Message{}, fmt.Errorf("parse instruction #%d data length error: %v", i+1, err)
+			return Message{}, fmt.Errorf("parse instruction #%d data length error: %w", i+1, err)
+		}
+		data, err := d.readBytes(dataLen)
+		if err != nil {
+			return Message{}, fmt.Errorf("parse instruction #%d data error: %w", i+1, err)
 		}
-		var data []byte
-		data, messageData = messageData[:dataLen], messageData[dataLen:]
 
 		instructions = append(instructions, CompiledInstruction{
 			ProgramIDIndex: int(programID),
@@ -222,33 +342,31 @@ func MessageDeserialize(messageData []byte) (Message, error) {
 
 	compiledAddressLookupTables := []CompiledAddressLookupTable{}
 	if version == MessageVersionV0 {
-		addressLookupTableCount, err := parseUvarint(&messageData)
+		// a table is at least a key and two lengths
+		addressLookupTableCount, err := d.readLength(32 + 2)
 		if err != nil {
-			return Message{}, fmt.Errorf("parse instruction count error: %v", err)
+			return Message{}, fmt.Errorf("failed to parse address lookup table count, err: %w", err)
 		}
 
-		for i := uint64(0); i < addressLookupTableCount; i++ {
-			addressLookupTablePubkey := common.PublicKeyFromBytes(messageData[:32])
-			messageData = messageData[32:]
+		for i := 0; i < addressLookupTableCount; i++ {
+			addressLookupTablePubkey, _ := d.readBytes(32)
 
-			writableAccountIdxCount, err := parseUvarint(&messageData)
+			writableAccountIdxCount, err := d.readLength(1)
 			if err != nil {
-				return Message{}, fmt.Errorf("failed to parse address lookup table writable account idx count, err: %v", err)
+				return Message{}, fmt.Errorf("failed to parse address lookup table writable account idx count, err: %w", err)
 			}
-			var writableAccountIdxList []uint8
-			writableAccountIdxList, messageData = messageData[:writableAccountIdxCount], messageData[writableAccountIdxCount:]
+			writableAccountIdxList, _ := d.readBytes(writableAccountIdxCount)
 
-			readOnlyAccountIdxCount, err := parseUvarint(&messageData)
+			readOnlyAccountIdxCount, err := d.readLength(1)
 			if err != nil {
-				return Message{}, fmt.Errorf("failed to parse address lookup table readOnly account idx count, err: %v", err)
+				return Message{}, fmt.Errorf("failed to parse address lookup table readOnly account idx count, err: %w", err)
 			}
-			var readOnlyAccountIdxList []uint8
-			readOnlyAccountIdxList, messageData = messageData[:readOnlyAccountIdxCount], messageData[readOnlyAccountIdxCount:]
+			readOnlyAccountIdxList, _ := d.readBytes(readOnlyAccountIdxCount)
 
 			compiledAddressLookupTables = append(
 				compiledAddressLookupTables,
 				CompiledAddressLookupTable{
-					AccountKey:      addressLookupTablePubkey,
+					AccountKey:      common.PublicKeyFromBytes(addressLookupTablePubkey),
 					WritableIndexes: writableAccountIdxList,
 					ReadonlyIndexes: readOnlyAccountIdxList,
 				},
@@ -259,12 +377,12 @@ func MessageDeserialize(messageData []byte) (Message, error) {
 	return Message{
 		Version: version,
 		Header: MessageHeader{
-			NumRequireSignatures:        numRequireSignatures,
-			NumReadonlySignedAccounts:   numReadonlySignedAccounts,
-			NumReadonlyUnsignedAccounts: numReadonlyUnsignedAccounts,
+			NumRequireSignatures:        header[0],
+			NumReadonlySignedAccounts:   header[1],
+			NumReadonlyUnsignedAccounts: header[2],
 		},
 		Accounts:            accounts,
-		RecentBlockHash:     blockHash,
+		RecentBlockHash:     base58.Encode(blockHash),
 		Instructions:        instructions,
 		AddressLookupTables: compiledAddressLookupTables,
 	}, nil
@@ -351,6 +469,10 @@ func NewMessage(param NewMessageParam) Message {
 	for _, addressLookupTableAccount := range param.AddressLookupTableAccounts {
 		m := map[common.PublicKey]uint8{}
 		for i, address := range addressLookupTableAccount.Addresses {
+			// the rest can't be referenced by a u8 index
+			if i >= maxLookupTableAddresses {
+				break
+			}
 			m[address] = uint8(i)
 		}
 		addressLookupTableMaps = append(addressLookupTableMaps, m)
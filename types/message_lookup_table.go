@@ -0,0 +1,179 @@
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/bits"
+	"sort"
+
+	"github.com/labyla/solana-go-sdk/common"
+)
+
+var (
+	ErrMessageTooLarge        = errors.New("message too large")
+	ErrMessageTooManyAccounts = errors.New("too many accounts")
+)
+
+const (
+	// a table costs its key and the two lengths of its index lists
+	lookupTableOverhead = 32 + 1 + 1
+	// an account moved from the static keys to a table costs an index instead of a key
+	lookupTableSaving = 32 - 1
+	// candidates are searched exhaustively up to this number, greedily above it
+	maxExhaustiveLookupTables = 16
+	// a message references a table address by a u8 index
+	maxLookupTableAddresses = 256
+)
+
+// NewMessageWithAutoLookupTables is NewMessage with AddressLookupTableAccounts as candidates.
+// only the tables which shrink the message are used, picked to make it as small as possible.
+// it returns ErrMessageTooLarge or ErrMessageTooManyAccounts if the transaction still doesn't fit.
+func NewMessageWithAutoLookupTables(param NewMessageParam) (Message, error) {
+	param.AddressLookupTableAccounts = SelectAddressLookupTables(param.FeePayer, param.Instructions, param.AddressLookupTableAccounts)
+	message := NewMessage(param)
+
+	if n := message.NumAccounts(); n > common.MaxTransactionAccounts {
+		return Message{}, fmt.Errorf("%w: %v accounts, max %v", ErrMessageTooManyAccounts, n, common.MaxTransactionAccounts)
+	}
+	size, err := message.TransactionSize()
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to serialize message, err: %v", err)
+	}
+	if size > common.MaxTransactionSize {
+		return Message{}, fmt.Errorf("%w: %v bytes, max %v", ErrMessageTooLarge, size, common.MaxTransactionSize)
+	}
+	return message, nil
+}
+
+// SelectAddressLookupTables returns the candidates which minimize the size of the message,
+// in order of priority. an account found in several tables is loaded from the first one.
+// only the first 256 addresses of a table can be referenced, the rest are ignored like
+// NewMessage does. the result only depends on the arguments.
+func SelectAddressLookupTables(feePayer common.PublicKey, instructions []Instruction, candidates []AddressLookupTableAccount) []AddressLookupTableAccount {
+	// only accounts which are not signers or programs can be loaded, same as NewMessage
+	compiledKeys := NewCompiledKeys(instructions, &feePayer)
+	keys := make([]common.PublicKey, 0, len(compiledKeys.KeyMetaMap))
+	for key, meta := range compiledKeys.KeyMetaMap {
+		if key != feePayer && !meta.IsSigner && !meta.IsInvoked {
+			keys = append(keys, key)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return bytes.Compare(keys[i].Bytes(), keys[j].Bytes()) < 0
+	})
+	loadable := make(map[common.PublicKey]int, len(keys))
+	for i, key := range keys {
+		loadable[key] = i
+	}
+
+	// a table with less than 2 loadable accounts can't save more than its overhead
+	type table struct {
+		account  AddressLookupTableAccount
+		accounts accountSet
+	}
+	tables := []table{}
+	seen := map[common.PublicKey]bool{}
+	for _, candidate := range candidates {
+		if seen[candidate.Key] {
+			continue
+		}
+		seen[candidate.Key] = true
+		set := newAccountSet(len(loadable))
+		for i, address := range candidate.Addresses {
+			if i >= maxLookupTableAddresses {
+				break
+			}
+			if i, ok := loadable[address]; ok {
+				set.add(i)
+			}
+		}
+		if set.count()*lookupTableSaving > lookupTableOverhead {
+			tables = append(tables, table{account: candidate, accounts: set})
+		}
+	}
+
+	var picked []int
+	if len(tables) <= maxExhaustiveLookupTables {
+		best, bestCost := 0, 0
+		for subset := 1; subset < 1<<len(tables); subset++ {
+			covered := newAccountSet(len(loadable))
+			for i := range tables {
+				if subset&(1<<i) != 0 {
+					covered.union(tables[i].accounts)
+				}
+			}
+			cost := bits.OnesCount(uint(subset))*lookupTableOverhead - covered.count()*lookupTableSaving
+			if cost < bestCost || (cost == bestCost && bits.OnesCount(uint(subset)) < bits.OnesCount(uint(best))) {
+				best, bestCost = subset, cost
+			}
+		}
+		for i := range tables {
+			if best&(1<<i) != 0 {
+				picked = append(picked, i)
+			}
+		}
+	} else {
+		covered := newAccountSet(len(loadable))
+		used := make([]bool, len(tables))
+		for {
+			best, bestSaving := -1, 0
+			for i := range tables {
+				if used[i] {
+					continue
+				}
+				saving := covered.countNew(tables[i].accounts)*lookupTableSaving - lookupTableOverhead
+				if saving > bestSaving {
+					best, bestSaving = i, saving
+				}
+			}
+			if best < 0 {
+				break
+			}
+			used[best] = true
+			covered.union(tables[best].accounts)
+			picked = append(picked, best)
+		}
+	}
+
+	selected := make([]AddressLookupTableAccount, 0, len(picked))
+	for _, i := range picked {
+		selected = append(selected, tables[i].account)
+	}
+	return selected
+}
+
+// accountSet is a bitset of account indexes. the instructions may reference more accounts
+// than a message can hold, NewMessageWithAutoLookupTables reports that after the selection.
+type accountSet []uint64
+
+func newAccountSet(n int) accountSet {
+	return make(accountSet, (n+63)/64)
+}
+
+func (s accountSet) add(i int) {
+	s[i/64] |= 1 << (i % 64)
+}
+
+func (s accountSet) union(o accountSet) {
+	for i := range s {
+		s[i] |= o[i]
+	}
+}
+
+func (s accountSet) count() int {
+	n := 0
+	for _, w := range s {
+		n += bits.OnesCount64(w)
+	}
+	return n
+}
+
+// countNew returns the number of accounts in o but not in s
+func (s accountSet) countNew(o accountSet) int {
+	n := 0
+	for i := range s {
+		n += bits.OnesCount64(o[i] &^ s[i])
+	}
+	return n
+}
@@ -0,0 +1,170 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestKeys(n int) []common.PublicKey {
+	keys := make([]common.PublicKey, 0, n)
+	for i := 0; i < n; i++ {
+		keys = append(keys, NewAccount().PublicKey)
+	}
+	return keys
+}
+
+func newTestInstruction(accounts []common.PublicKey) Instruction {
+	metas := make([]AccountMeta, 0, len(accounts))
+	for i, account := range accounts {
+		metas = append(metas, AccountMeta{PubKey: account, IsWritable: i%2 == 0})
+	}
+	return Instruction{
+		ProgramID: common.MemoProgramID,
+		Accounts:  metas,
+		Data:      []byte{1},
+	}
+}
+
+func TestSelectAddressLookupTables(t *testing.T) {
+	feePayer := NewAccount().PublicKey
+	keys := newTestKeys(6)
+	instructions := []Instruction{newTestInstruction(keys)}
+
+	small := AddressLookupTableAccount{Key: NewAccount().PublicKey, Addresses: keys[:3]}
+	large := AddressLookupTableAccount{Key: NewAccount().PublicKey, Addresses: append([]common.PublicKey{common.SystemProgramID}, keys[:5]...)}
+	// one account doesn't pay for the table
+	single := AddressLookupTableAccount{Key: NewAccount().PublicKey, Addresses: keys[5:]}
+	// programs and the fee payer can't be loaded
+	unusable := AddressLookupTableAccount{Key: NewAccount().PublicKey, Addresses: []common.PublicKey{feePayer, common.MemoProgramID}}
+
+	assert.Equal(t,
+		[]AddressLookupTableAccount{large},
+		SelectAddressLookupTables(feePayer, instructions, []AddressLookupTableAccount{small, single, unusable, large}),
+	)
+	assert.Empty(t, SelectAddressLookupTables(feePayer, instructions, []AddressLookupTableAccount{single, unusable}))
+
+	// disjoint tables are all worth it, with the greedy search as well
+	var tables []AddressLookupTableAccount
+	keys = newTestKeys(2 * (maxExhaustiveLookupTables + 2))
+	for i := 0; i < len(keys); i += 2 {
+		tables = append(tables, AddressLookupTableAccount{Key: NewAccount().PublicKey, Addresses: keys[i : i+2]})
+	}
+	assert.Equal(t, tables, SelectAddressLookupTables(feePayer, []Instruction{newTestInstruction(keys)}, tables))
+	assert.Equal(t, tables[:4], SelectAddressLookupTables(feePayer, []Instruction{newTestInstruction(keys)}, tables[:4]))
+}
+
+func TestSelectAddressLookupTables_Deterministic(t *testing.T) {
+	// more loadable accounts than a message can hold
+	feePayer := NewAccount().PublicKey
+	keys := newTestKeys(300)
+	instructions := []Instruction{newTestInstruction(keys)}
+	tables := []AddressLookupTableAccount{
+		{Key: NewAccount().PublicKey, Addresses: keys[:150]},
+		{Key: NewAccount().PublicKey, Addresses: keys[150:298]},
+		// only worth it if both accounts count
+		{Key: NewAccount().PublicKey, Addresses: keys[298:]},
+	}
+	for i := 0; i < 20; i++ {
+		assert.Equal(t, tables, SelectAddressLookupTables(feePayer, instructions, tables))
+	}
+}
+
+func TestSelectAddressLookupTables_AddressIndexOutOfRange(t *testing.T) {
+	feePayer := NewAccount().PublicKey
+	keys := newTestKeys(4)
+	instructions := []Instruction{newTestInstruction(keys)}
+	// the accounts are past the addresses a message can reference
+	table := AddressLookupTableAccount{Key: NewAccount().PublicKey, Addresses: append(newTestKeys(maxLookupTableAddresses), keys...)}
+
+	assert.Empty(t, SelectAddressLookupTables(feePayer, instructions, []AddressLookupTableAccount{table}))
+
+	message := NewMessage(NewMessageParam{
+		FeePayer:                   feePayer,
+		Instructions:               instructions,
+		RecentBlockhash:            "9rAtxuhtKn8qagc3UtZFyhLrw5zkh6etv43TibaXuSKo",
+		AddressLookupTableAccounts: []AddressLookupTableAccount{table},
+	})
+	assert.Empty(t, message.AddressLookupTables)
+	assert.Len(t, message.Accounts, 6)
+}
+
+func TestNewMessageWithAutoLookupTables(t *testing.T) {
+	feePayer := NewAccount().PublicKey
+	keys := newTestKeys(40)
+	table := AddressLookupTableAccount{Key: NewAccount().PublicKey, Addresses: keys}
+	param := NewMessageParam{
+		FeePayer:        feePayer,
+		Instructions:    []Instruction{newTestInstruction(keys)},
+		RecentBlockhash: "9rAtxuhtKn8qagc3UtZFyhLrw5zkh6etv43TibaXuSKo",
+		AddressLookupTableAccounts: []AddressLookupTableAccount{
+			{Key: NewAccount().PublicKey, Addresses: keys[:1]},
+			table,
+		},
+	}
+
+	// 40 static accounts don't fit
+	_, err := NewMessageWithAutoLookupTables(NewMessageParam{
+		FeePayer:        param.FeePayer,
+		Instructions:    param.Instructions,
+		RecentBlockhash: param.RecentBlockhash,
+	})
+	assert.ErrorIs(t, err, ErrMessageTooLarge)
+
+	message, err := NewMessageWithAutoLookupTables(param)
+	assert.Nil(t, err)
+	assert.Equal(t, MessageVersion(MessageVersionV0), message.Version)
+	assert.Len(t, message.AddressLookupTables, 1)
+	assert.Equal(t, table.Key, message.AddressLookupTables[0].AccountKey)
+	assert.Equal(t, 42, message.NumAccounts())
+
+	loaded, err := message.ResolveLoadedAddresses(param.AddressLookupTableAccounts)
+	assert.Nil(t, err)
+	instructions, err := message.DecompileInstructionsWithLoadedAddresses(loaded)
+	assert.Nil(t, err)
+	assert.Equal(t, param.Instructions, instructions)
+
+	// more than 256 accounts
+	keys = newTestKeys(300)
+	_, err = NewMessageWithAutoLookupTables(NewMessageParam{
+		FeePayer:        feePayer,
+		Instructions:    []Instruction{newTestInstruction(keys)},
+		RecentBlockhash: param.RecentBlockhash,
+		AddressLookupTableAccounts: []AddressLookupTableAccount{
+			{Key: NewAccount().PublicKey, Addresses: keys[:150]},
+			{Key: NewAccount().PublicKey, Addresses: keys[150:]},
+		},
+	})
+	assert.ErrorIs(t, err, ErrMessageTooManyAccounts)
+}
+
+func TestMessage_TransactionSize(t *testing.T) {
+	feePayer := NewAccount()
+	signer := NewAccount()
+	message := NewMessage(NewMessageParam{
+		FeePayer: feePayer.PublicKey,
+		Instructions: []Instruction{
+			{
+				ProgramID: common.MemoProgramID,
+				Accounts:  []AccountMeta{{PubKey: signer.PublicKey, IsSigner: true}},
+				Data:      []byte("hello"),
+			},
+		},
+		RecentBlockhash: "9rAtxuhtKn8qagc3UtZFyhLrw5zkh6etv43TibaXuSKo",
+	})
+	tx, err := NewTransaction(NewTransactionParam{Message: message, Signers: []Signer{feePayer, signer}})
+	assert.Nil(t, err)
+	b, err := tx.Serialize()
+	assert.Nil(t, err)
+
+	size, err := message.TransactionSize()
+	assert.Nil(t, err)
+	assert.Equal(t, len(b), size)
+
+	// without a blockhash
+	message.RecentBlockHash = ""
+	size, err = message.TransactionSize()
+	assert.Nil(t, err)
+	assert.Equal(t, len(b), size)
+}
@@ -1,7 +1,6 @@
 package types
 
 import (
-	"fmt"
 	"testing"
 
 	"github.com/labyla/solana-go-sdk/common"
@@ -21,7 +20,6 @@ func TestMessage_DecompileInstructions(t *testing.T) {
 		name   string
 		fields fields
 		want   []Instruction
-		panic  string
 	}{
 		{
 			fields: fields{
@@ -110,7 +108,16 @@ func TestMessage_DecompileInstructions(t *testing.T) {
 					},
 				},
 			},
-			panic: "hasn't supported",
+			want: []Instruction{
+				{
+					ProgramID: common.SystemProgramID,
+					Accounts: []AccountMeta{
+						{PubKey: common.PublicKeyFromString("9aE476sH92Vz7DMPyq5WLPkrKWivxeuTKEFKd2sZZcde"), IsSigner: true, IsWritable: true},
+						{PubKey: common.PublicKeyFromString("2xNweLHLqrbx4zo1waDvgWJHgsUpPj8Y8icbAFeR4a8i"), IsSigner: false, IsWritable: true},
+					},
+					Data: []byte{2, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0},
+				},
+			},
 		},
 		{
 			fields: fields{
@@ -141,7 +148,16 @@ func TestMessage_DecompileInstructions(t *testing.T) {
 					},
 				},
 			},
-			panic: "hasn't supported",
+			want: []Instruction{
+				{
+					ProgramID: common.SystemProgramID,
+					Accounts: []AccountMeta{
+						{PubKey: common.PublicKeyFromString("9aE476sH92Vz7DMPyq5WLPkrKWivxeuTKEFKd2sZZcde"), IsSigner: true, IsWritable: true},
+						{PubKey: common.PublicKeyFromString("2xNweLHLqrbx4zo1waDvgWJHgsUpPj8Y8icbAFeR4a8i"), IsSigner: false, IsWritable: true},
+					},
+					Data: []byte{2, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0},
+				},
+			},
 		},
 		{
 			fields: fields{
@@ -171,7 +187,49 @@ func TestMessage_DecompileInstructions(t *testing.T) {
 					},
 				},
 			},
-			panic: "hasn't supported",
+			want: nil,
+		},
+		{
+			name: "program id index out of range",
+			fields: fields{
+				Header: MessageHeader{
+					NumRequireSignatures: 1,
+				},
+				Accounts: []common.PublicKey{
+					common.PublicKeyFromString("9aE476sH92Vz7DMPyq5WLPkrKWivxeuTKEFKd2sZZcde"),
+				},
+				RecentBlockHash: "5EvWPqKeYfN2P7SAQZ2TLnXhV3Ltjn6qEhK1F279dUUW",
+				Instructions: []CompiledInstruction{
+					{
+						ProgramIDIndex: 5,
+						Accounts:       []int{},
+						Data:           []byte{},
+					},
+				},
+			},
+			want: nil,
+		},
+		{
+			name: "account index out of range",
+			fields: fields{
+				Header: MessageHeader{
+					NumRequireSignatures:        1,
+					NumReadonlyUnsignedAccounts: 1,
+				},
+				Accounts: []common.PublicKey{
+					common.PublicKeyFromString("9aE476sH92Vz7DMPyq5WLPkrKWivxeuTKEFKd2sZZcde"),
+					common.SystemProgramID,
+				},
+				RecentBlockHash: "5EvWPqKeYfN2P7SAQZ2TLnXhV3Ltjn6qEhK1F279dUUW",
+				Instructions: []CompiledInstruction{
+					{
+						ProgramIDIndex: 1,
+						Accounts:       []int{0, 2},
+						Data:           []byte{2, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0},
+					},
+				},
+			},
+			want: nil,
 		},
 	}
 	for _, tt := range tests {
@@ -184,13 +242,7 @@ func TestMessage_DecompileInstructions(t *testing.T) {
 				Instructions:        tt.fields.Instructions,
 				AddressLookupTables: tt.fields.AddressLookupTables,
 			}
-			if len(tt.panic) == 0 {
-				assert.Equal(t, tt.want, m.DecompileInstructions())
-			} else {
-				assert.PanicsWithValue(t, tt.panic, func() {
-					m.DecompileInstructions()
-				})
-			}
+			assert.Equal(t, tt.want, m.DecompileInstructions())
 		})
 	}
 }
@@ -842,14 +894,198 @@ func TestMessageDeserialize(t *testing.T) {
 		{
 			args: args{messageData: []byte{128}},
 			want: Message{},
-			err:  fmt.Errorf("message header #1 parse error: data is empty"),
+			err:  ErrDeserializeUnexpectedEnd,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got, err := MessageDeserialize(tt.args.messageData)
 			assert.Equal(t, tt.want, got)
-			assert.Equal(t, tt.err, err)
+			if tt.err == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.err)
+			}
+		})
+	}
+}
+
+func TestMessage_DecompileInstructionsWithLoadedAddresses(t *testing.T) {
+	feePayer := common.PublicKeyFromString("RNfp4xTbBb4C3kcv2KqtAj8mu4YhMHxqm1Skg9uchZ7")
+	signer := common.PublicKeyFromString("9aE476sH92Vz7DMPyq5WLPkrKWivxeuTKEFKd2sZZcde")
+	writable := common.PublicKeyFromString("2xNweLHLqrbx4zo1waDvgWJHgsUpPj8Y8icbAFeR4a8i")
+	loadedWritable := common.PublicKeyFromString("3Yvq7e9UXLoFK4PKyxrpEA3y3TKmFK2Wb1f5tVFUgwPu")
+	loadedReadonly := common.PublicKeyFromString("F1rcBbZB6tQZUTR2z8jKQxaAwUUkxnghSh941Q62hMi8")
+	table := AddressLookupTableAccount{
+		Key:       common.PublicKeyFromString("HEhDGuxaxGr9LuNtBdvbX2uggyAKoxYgHFaAiqxVu8UY"),
+		Addresses: []common.PublicKey{loadedReadonly, common.SystemProgramID, loadedWritable},
+	}
+	instructions := []Instruction{
+		{
+			ProgramID: common.TokenProgramID,
+			Accounts: []AccountMeta{
+				{PubKey: loadedWritable, IsSigner: false, IsWritable: true},
+				{PubKey: loadedReadonly, IsSigner: false, IsWritable: false},
+				{PubKey: writable, IsSigner: false, IsWritable: true},
+				{PubKey: signer, IsSigner: true, IsWritable: false},
+				{PubKey: feePayer, IsSigner: true, IsWritable: true},
+			},
+			Data: []byte{1, 2, 3},
+		},
+	}
+	message := NewMessage(NewMessageParam{
+		FeePayer:                   feePayer,
+		Instructions:               instructions,
+		RecentBlockhash:            "9rAtxuhtKn8qagc3UtZFyhLrw5zkh6etv43TibaXuSKo",
+		AddressLookupTableAccounts: []AddressLookupTableAccount{table},
+	})
+	assert.Equal(t, MessageVersion(MessageVersionV0), message.Version)
+
+	loaded, err := message.ResolveLoadedAddresses([]AddressLookupTableAccount{table})
+	assert.Nil(t, err)
+	assert.Equal(t, LoadedAddresses{
+		Writable: []common.PublicKey{loadedWritable},
+		Readonly: []common.PublicKey{loadedReadonly},
+	}, loaded)
+
+	got, err := message.DecompileInstructionsWithLoadedAddresses(loaded)
+	assert.Nil(t, err)
+	assert.Equal(t, instructions, got)
+
+	// legacy
+	legacy := NewMessage(NewMessageParam{
+		FeePayer:        feePayer,
+		Instructions:    instructions,
+		RecentBlockhash: "9rAtxuhtKn8qagc3UtZFyhLrw5zkh6etv43TibaXuSKo",
+	})
+	got, err = legacy.DecompileInstructionsWithLoadedAddresses(LoadedAddresses{})
+	assert.Nil(t, err)
+	assert.Equal(t, instructions, got)
+}
+
+func TestMessage_DecompileInstructionsWithLoadedAddresses_Error(t *testing.T) {
+	newMessage := func() Message {
+		return Message{
+			Version: MessageVersionV0,
+			Header: MessageHeader{
+				NumRequireSignatures:        1,
+				NumReadonlySignedAccounts:   0,
+				NumReadonlyUnsignedAccounts: 1,
+			},
+			Accounts: []common.PublicKey{
+				common.PublicKeyFromString("9aE476sH92Vz7DMPyq5WLPkrKWivxeuTKEFKd2sZZcde"),
+				common.SystemProgramID,
+			},
+			RecentBlockHash: "5EvWPqKeYfN2P7SAQZ2TLnXhV3Ltjn6qEhK1F279dUUW",
+			Instructions: []CompiledInstruction{
+				{
+					ProgramIDIndex: 1,
+					Accounts:       []int{0, 2},
+					Data:           []byte{2, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0},
+				},
+			},
+			AddressLookupTables: []CompiledAddressLookupTable{
+				{
+					AccountKey:      common.PublicKeyFromString("HEhDGuxaxGr9LuNtBdvbX2uggyAKoxYgHFaAiqxVu8UY"),
+					WritableIndexes: []uint8{1},
+					ReadonlyIndexes: []uint8{},
+				},
+			},
+		}
+	}
+	loaded := LoadedAddresses{
+		Writable: []common.PublicKey{common.PublicKeyFromString("2xNweLHLqrbx4zo1waDvgWJHgsUpPj8Y8icbAFeR4a8i")},
+	}
+
+	tests := []struct {
+		name   string
+		modify func(m *Message, l *LoadedAddresses)
+		err    error
+	}{
+		{
+			name:   "ok",
+			modify: func(m *Message, l *LoadedAddresses) {},
+		},
+		{
+			name:   "missing loaded addresses",
+			modify: func(m *Message, l *LoadedAddresses) { l.Writable = nil },
+			err:    ErrMessageLoadedAddressesMismatch,
+		},
+		{
+			name:   "account index",
+			modify: func(m *Message, l *LoadedAddresses) { m.Instructions[0].Accounts = []int{0, 3} },
+			err:    ErrMessageIndexOutOfRange,
+		},
+		{
+			name:   "negative account index",
+			modify: func(m *Message, l *LoadedAddresses) { m.Instructions[0].Accounts = []int{-1} },
+			err:    ErrMessageIndexOutOfRange,
+		},
+		{
+			name:   "program id is a loaded address",
+			modify: func(m *Message, l *LoadedAddresses) { m.Instructions[0].ProgramIDIndex = 2 },
+			err:    ErrMessageIndexOutOfRange,
+		},
+		{
+			name:   "header",
+			modify: func(m *Message, l *LoadedAddresses) { m.Header.NumReadonlySignedAccounts = 2 },
+			err:    ErrMessageInvalidHeader,
+		},
+		{
+			name:   "header exceeds accounts",
+			modify: func(m *Message, l *LoadedAddresses) { m.Header.NumReadonlyUnsignedAccounts = 2 },
+			err:    ErrMessageInvalidHeader,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, l := newMessage(), loaded
+			tt.modify(&m, &l)
+			_, err := m.DecompileInstructionsWithLoadedAddresses(l)
+			if tt.err == nil {
+				assert.Nil(t, err)
+			} else {
+				assert.ErrorIs(t, err, tt.err)
+			}
 		})
 	}
 }
+
+func TestMessage_DecompileInstructions_ProgramIDIndexOutOfRange(t *testing.T) {
+	// a legacy message with one account and an instruction calling the program at index 5
+	data := []byte{1, 0, 0, 1}
+	data = append(data, common.PublicKeyFromString("9aE476sH92Vz7DMPyq5WLPkrKWivxeuTKEFKd2sZZcde").Bytes()...)
+	data = append(data, make([]byte, 32)...)
+	data = append(data, 1, 5, 0, 0)
+
+	m, err := MessageDeserialize(data)
+	assert.Nil(t, err)
+
+	assert.NotPanics(t, func() {
+		assert.Nil(t, m.DecompileInstructions())
+	})
+	_, err = m.DecompileInstructionsWithLoadedAddresses(LoadedAddresses{})
+	assert.ErrorIs(t, err, ErrMessageIndexOutOfRange)
+}
+
+func TestMessage_ResolveLoadedAddresses_Error(t *testing.T) {
+	tableKey := common.PublicKeyFromString("HEhDGuxaxGr9LuNtBdvbX2uggyAKoxYgHFaAiqxVu8UY")
+	m := Message{
+		Version: MessageVersionV0,
+		AddressLookupTables: []CompiledAddressLookupTable{
+			{
+				AccountKey:      tableKey,
+				WritableIndexes: []uint8{0},
+				ReadonlyIndexes: []uint8{1},
+			},
+		},
+	}
+
+	_, err := m.ResolveLoadedAddresses(nil)
+	assert.ErrorIs(t, err, ErrMessageLookupTableNotFound)
+
+	_, err = m.ResolveLoadedAddresses([]AddressLookupTableAccount{
+		{Key: tableKey, Addresses: []common.PublicKey{common.SystemProgramID}},
+	})
+	assert.ErrorIs(t, err, ErrMessageIndexOutOfRange)
+}
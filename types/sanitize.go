@@ -0,0 +1,143 @@
+package types
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+
+	"github.com/labyla/solana-go-sdk/common"
+)
+
+var (
+	ErrMessageDuplicateAccount          = errors.New("duplicate account")
+	ErrMessageInvalidProgramID          = errors.New("invalid program id")
+	ErrMessageInvalidAddressLookupTable = errors.New("invalid address lookup table")
+
+	ErrTransactionSignatureCountMismatch = errors.New("signature count mismatch")
+	ErrTransactionInvalidSignature       = errors.New("invalid transaction signature")
+)
+
+// Sanitize checks the message is well-formed the way the validator does before loading it.
+// the addresses of the lookup tables are not known here, so a duplicate between a loaded
+// address and a static one is only detected on chain.
+func (m Message) Sanitize() error {
+	switch m.Version {
+	case "", MessageVersionLegacy:
+		if len(m.AddressLookupTables) > 0 {
+			return fmt.Errorf("%w: legacy message with %v lookup tables", ErrMessageInvalidAddressLookupTable, len(m.AddressLookupTables))
+		}
+	case MessageVersionV0:
+	default:
+		return fmt.Errorf("%w: %v", ErrMessageUnsupportedVersion, m.Version)
+	}
+
+	numStatic := len(m.Accounts)
+	// the fee payer is the first signer and must be writable
+	if m.Header.NumRequireSignatures == 0 ||
+		m.Header.NumReadonlySignedAccounts >= m.Header.NumRequireSignatures ||
+		int(m.Header.NumRequireSignatures)+int(m.Header.NumReadonlyUnsignedAccounts) > numStatic {
+		return fmt.Errorf("%w: %+v with %v accounts", ErrMessageInvalidHeader, m.Header, numStatic)
+	}
+
+	seen := make(map[common.PublicKey]struct{}, numStatic)
+	for _, account := range m.Accounts {
+		if _, ok := seen[account]; ok {
+			return fmt.Errorf("%w: %v", ErrMessageDuplicateAccount, account)
+		}
+		seen[account] = struct{}{}
+	}
+
+	type tableIndex struct {
+		table common.PublicKey
+		index uint8
+	}
+	loaded := map[tableIndex]struct{}{}
+	numLoaded := 0
+	for _, lookup := range m.AddressLookupTables {
+		if len(lookup.WritableIndexes) == 0 && len(lookup.ReadonlyIndexes) == 0 {
+			return fmt.Errorf("%w: %v loads no address", ErrMessageInvalidAddressLookupTable, lookup.AccountKey)
+		}
+		for _, indexes := range [][]uint8{lookup.WritableIndexes, lookup.ReadonlyIndexes} {
+			for _, idx := range indexes {
+				key := tableIndex{table: lookup.AccountKey, index: idx}
+				if _, ok := loaded[key]; ok {
+					return fmt.Errorf("%w: table %v index %v is loaded twice", ErrMessageDuplicateAccount, lookup.AccountKey, idx)
+				}
+				loaded[key] = struct{}{}
+			}
+		}
+		numLoaded += len(lookup.WritableIndexes) + len(lookup.ReadonlyIndexes)
+	}
+	numAccounts := numStatic + numLoaded
+	if numAccounts > common.MaxTransactionAccounts {
+		return fmt.Errorf("%w: %v accounts, max %v", ErrMessageTooManyAccounts, numAccounts, common.MaxTransactionAccounts)
+	}
+
+	for i, instruction := range m.Instructions {
+		// the program can't be the fee payer or a loaded account
+		if instruction.ProgramIDIndex <= 0 || instruction.ProgramIDIndex >= numStatic {
+			return fmt.Errorf("instruction #%v: %w: index %v, %v static accounts", i, ErrMessageInvalidProgramID, instruction.ProgramIDIndex, numStatic)
+		}
+		for _, idx := range instruction.Accounts {
+			if idx < 0 || idx >= numAccounts {
+				return fmt.Errorf("instruction #%v: %w: account index %v, %v accounts", i, ErrMessageIndexOutOfRange, idx, numAccounts)
+			}
+		}
+	}
+	return nil
+}
+
+// Sanitize checks the message and that there is one signature for each signer
+func (tx *Transaction) Sanitize() error {
+	if err := tx.Message.Sanitize(); err != nil {
+		return err
+	}
+	if len(tx.Signatures) != int(tx.Message.Header.NumRequireSignatures) {
+		return fmt.Errorf("%w: %v signatures, %v signers", ErrTransactionSignatureCountMismatch, len(tx.Signatures), tx.Message.Header.NumRequireSignatures)
+	}
+	return nil
+}
+
+// SignatureVerification is the result of verifying the signature of a signer
+type SignatureVerification struct {
+	Signer    common.PublicKey
+	Signature Signature
+	Valid     bool
+}
+
+// VerifySignatures sanitizes the tx and verifies the signature of every signer, in the
+// order of the signers. the error is only returned when the tx can't be verified at all.
+func (tx *Transaction) VerifySignatures() ([]SignatureVerification, error) {
+	if err := tx.Sanitize(); err != nil {
+		return nil, err
+	}
+	data, err := tx.Message.Serialize()
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize message, err: %v", err)
+	}
+
+	results := make([]SignatureVerification, 0, len(tx.Signatures))
+	for i, sig := range tx.Signatures {
+		signer := tx.Message.Accounts[i]
+		results = append(results, SignatureVerification{
+			Signer:    signer,
+			Signature: sig,
+			Valid:     len(sig) == ed25519.SignatureSize && ed25519.Verify(signer.Bytes(), data, sig),
+		})
+	}
+	return results, nil
+}
+
+// Verify sanitizes the tx and checks all signatures are valid
+func (tx *Transaction) Verify() error {
+	results, err := tx.VerifySignatures()
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		if !result.Valid {
+			return fmt.Errorf("%w, signer: %v", ErrTransactionInvalidSignature, result.Signer)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,187 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestSanitizeMessage() Message {
+	return Message{
+		Version: MessageVersionV0,
+		Header: MessageHeader{
+			NumRequireSignatures:        2,
+			NumReadonlySignedAccounts:   1,
+			NumReadonlyUnsignedAccounts: 1,
+		},
+		Accounts:        newTestKeys(4),
+		RecentBlockHash: "FwRYtTPRk5N4wUeP87rTw9kQVSwigB6kbikGzzeCMrW5",
+		Instructions: []CompiledInstruction{
+			{ProgramIDIndex: 3, Accounts: []int{0, 1, 2, 4, 5}, Data: []byte{1}},
+		},
+		AddressLookupTables: []CompiledAddressLookupTable{
+			{AccountKey: NewAccount().PublicKey, WritableIndexes: []uint8{0}, ReadonlyIndexes: []uint8{1}},
+		},
+	}
+}
+
+func TestMessage_Sanitize(t *testing.T) {
+	assert.Nil(t, newTestSanitizeMessage().Sanitize())
+
+	tests := []struct {
+		name   string
+		modify func(m *Message)
+		err    error
+	}{
+		{
+			name:   "unknown version",
+			modify: func(m *Message) { m.Version = "v1" },
+			err:    ErrMessageUnsupportedVersion,
+		},
+		{
+			name:   "legacy with lookup tables",
+			modify: func(m *Message) { m.Version = MessageVersionLegacy },
+			err:    ErrMessageInvalidAddressLookupTable,
+		},
+		{
+			name:   "no signer",
+			modify: func(m *Message) { m.Header = MessageHeader{} },
+			err:    ErrMessageInvalidHeader,
+		},
+		{
+			name:   "readonly fee payer",
+			modify: func(m *Message) { m.Header.NumReadonlySignedAccounts = 2 },
+			err:    ErrMessageInvalidHeader,
+		},
+		{
+			name:   "more signers than accounts",
+			modify: func(m *Message) { m.Header.NumRequireSignatures = 5 },
+			err:    ErrMessageInvalidHeader,
+		},
+		{
+			name:   "readonly overlaps signers",
+			modify: func(m *Message) { m.Header.NumReadonlyUnsignedAccounts = 3 },
+			err:    ErrMessageInvalidHeader,
+		},
+		{
+			name:   "duplicate account",
+			modify: func(m *Message) { m.Accounts[2] = m.Accounts[1] },
+			err:    ErrMessageDuplicateAccount,
+		},
+		{
+			name: "duplicate loaded account",
+			modify: func(m *Message) {
+				m.AddressLookupTables = append(m.AddressLookupTables, CompiledAddressLookupTable{
+					AccountKey:      m.AddressLookupTables[0].AccountKey,
+					ReadonlyIndexes: []uint8{0},
+				})
+			},
+			err: ErrMessageDuplicateAccount,
+		},
+		{
+			name: "empty lookup table",
+			modify: func(m *Message) {
+				m.AddressLookupTables = append(m.AddressLookupTables, CompiledAddressLookupTable{AccountKey: NewAccount().PublicKey})
+			},
+			err: ErrMessageInvalidAddressLookupTable,
+		},
+		{
+			name: "too many accounts",
+			modify: func(m *Message) {
+				indexes := make([]uint8, 0, 253)
+				for i := 0; i < 253; i++ {
+					indexes = append(indexes, uint8(i))
+				}
+				m.AddressLookupTables[0].WritableIndexes = indexes
+				m.AddressLookupTables[0].ReadonlyIndexes = nil
+			},
+			err: ErrMessageTooManyAccounts,
+		},
+		{
+			name:   "fee payer as program",
+			modify: func(m *Message) { m.Instructions[0].ProgramIDIndex = 0 },
+			err:    ErrMessageInvalidProgramID,
+		},
+		{
+			name:   "loaded program",
+			modify: func(m *Message) { m.Instructions[0].ProgramIDIndex = 4 },
+			err:    ErrMessageInvalidProgramID,
+		},
+		{
+			name:   "account index out of range",
+			modify: func(m *Message) { m.Instructions[0].Accounts = []int{6} },
+			err:    ErrMessageIndexOutOfRange,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestSanitizeMessage()
+			tt.modify(&m)
+			assert.ErrorIs(t, m.Sanitize(), tt.err)
+		})
+	}
+}
+
+func TestTransaction_VerifySignatures(t *testing.T) {
+	feePayer := NewAccount()
+	signer := NewAccount()
+	tx, err := NewTransaction(NewTransactionParam{
+		Message: NewMessage(NewMessageParam{
+			FeePayer: feePayer.PublicKey,
+			Instructions: []Instruction{
+				{
+					ProgramID: common.MemoProgramID,
+					Accounts:  []AccountMeta{{PubKey: signer.PublicKey, IsSigner: true}},
+					Data:      []byte("memo"),
+				},
+			},
+			RecentBlockhash: "FwRYtTPRk5N4wUeP87rTw9kQVSwigB6kbikGzzeCMrW5",
+		}),
+		Signers: []Signer{feePayer},
+	})
+	require.Nil(t, err)
+
+	// the second signature is still empty
+	results, err := tx.VerifySignatures()
+	require.Nil(t, err)
+	assert.Equal(t, []SignatureVerification{
+		{Signer: feePayer.PublicKey, Signature: tx.Signatures[0], Valid: true},
+		{Signer: signer.PublicKey, Signature: tx.Signatures[1], Valid: false},
+	}, results)
+	assert.ErrorIs(t, tx.Verify(), ErrTransactionInvalidSignature)
+
+	data, err := tx.Message.Serialize()
+	require.Nil(t, err)
+	require.Nil(t, tx.AddSignature(signer.Sign(data)))
+	assert.Nil(t, tx.Verify())
+
+	// a round trip keeps the signatures valid
+	raw, err := tx.Serialize()
+	require.Nil(t, err)
+	decoded, err := TransactionDeserialize(raw)
+	require.Nil(t, err)
+	assert.Nil(t, decoded.Verify())
+
+	// a tampered message invalidates every signature
+	decoded.Message.Instructions[0].Data = []byte("meme")
+	results, err = decoded.VerifySignatures()
+	require.Nil(t, err)
+	assert.False(t, results[0].Valid)
+	assert.False(t, results[1].Valid)
+
+	// a signature of another signer
+	tx.Signatures[1] = tx.Signatures[0]
+	assert.ErrorIs(t, tx.Verify(), ErrTransactionInvalidSignature)
+
+	// a short signature
+	tx.Signatures[1] = tx.Signatures[1][:63]
+	results, err = tx.VerifySignatures()
+	require.Nil(t, err)
+	assert.False(t, results[1].Valid)
+
+	tx.Signatures = tx.Signatures[:1]
+	_, err = tx.VerifySignatures()
+	assert.ErrorIs(t, err, ErrTransactionSignatureCountMismatch)
+}
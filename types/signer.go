@@ -0,0 +1,27 @@
+package types
+
+import (
+	"context"
+	"errors"
+
+	"github.com/labyla/solana-go-sdk/common"
+)
+
+var ErrSignerInvalidSignature = errors.New("invalid signature")
+
+// Signer signs messages on behalf of a public key. the private key doesn't have to be
+// in memory, e.g. it can live in a kms, a remote service or a hardware wallet.
+type Signer interface {
+	Public() common.PublicKey
+	SignMessage(ctx context.Context, message []byte) ([]byte, error)
+}
+
+// Public returns the public key of the account
+func (a Account) Public() common.PublicKey {
+	return a.PublicKey
+}
+
+// SignMessage signs the message with the private key of the account
+func (a Account) SignMessage(_ context.Context, message []byte) ([]byte, error) {
+	return a.Sign(message), nil
+}
@@ -0,0 +1,92 @@
+package types
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/stretchr/testify/assert"
+)
+
+type testSigner struct {
+	publicKey common.PublicKey
+	sign      func(message []byte) ([]byte, error)
+}
+
+func (s testSigner) Public() common.PublicKey { return s.publicKey }
+
+func (s testSigner) SignMessage(_ context.Context, message []byte) ([]byte, error) {
+	return s.sign(message)
+}
+
+func TestNewTransaction_Signer(t *testing.T) {
+	feePayer := NewAccount()
+	other := NewAccount()
+	message := NewMessage(NewMessageParam{
+		FeePayer:        feePayer.PublicKey,
+		Instructions:    []Instruction{},
+		RecentBlockhash: "FwRYtTPRk5N4wUeP87rTw9kQVSwigB6kbikGzzeCMrW5",
+	})
+	errBusy := errors.New("busy")
+
+	tests := []struct {
+		name   string
+		signer Signer
+		err    error
+	}{
+		{
+			name:   "account",
+			signer: feePayer,
+		},
+		{
+			name: "sign error",
+			signer: testSigner{
+				publicKey: feePayer.PublicKey,
+				sign:      func([]byte) ([]byte, error) { return nil, errBusy },
+			},
+			err: errBusy,
+		},
+		{
+			name: "wrong key",
+			signer: testSigner{
+				publicKey: feePayer.PublicKey,
+				sign:      func(message []byte) ([]byte, error) { return other.Sign(message), nil },
+			},
+			err: ErrSignerInvalidSignature,
+		},
+		{
+			name: "short signature",
+			signer: testSigner{
+				publicKey: feePayer.PublicKey,
+				sign:      func([]byte) ([]byte, error) { return []byte{1, 2, 3}, nil },
+			},
+			err: ErrSignerInvalidSignature,
+		},
+		{
+			name:   "not a signer",
+			signer: other,
+			err:    ErrTransactionAddNotNecessarySignatures,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx, err := NewTransaction(NewTransactionParam{
+				Message: message,
+				Signers: []Signer{tt.signer},
+			})
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, []Signature{feePayer.Sign(mustSerialize(t, message))}, tx.Signatures)
+		})
+	}
+}
+
+func mustSerialize(t *testing.T, message Message) []byte {
+	b, err := message.Serialize()
+	assert.Nil(t, err)
+	return b
+}
@@ -1,8 +1,8 @@
 package types
 
 import (
+	"context"
 	"crypto/ed25519"
-	"encoding/binary"
 	"errors"
 	"fmt"
 
@@ -23,11 +23,16 @@ type Transaction struct {
 
 type NewTransactionParam struct {
 	Message Message
-	Signers []Account
+	Signers []Signer
 }
 
 // NewTransaction create a new tx by message and signer. it will reserve signatures slot.
 func NewTransaction(param NewTransactionParam) (Transaction, error) {
+	return NewTransactionWithContext(context.Background(), param)
+}
+
+// NewTransactionWithContext is NewTransaction with a context passed to the signers
+func NewTransactionWithContext(ctx context.Context, param NewTransactionParam) (Transaction, error) {
 	signatures := make([]Signature, 0, param.Message.Header.NumRequireSignatures)
 	for i := uint8(0); i < param.Message.Header.NumRequireSignatures; i++ {
 		signatures = append(signatures, make([]byte, 64))
@@ -43,11 +48,19 @@ func NewTransaction(param NewTransactionParam) (Transaction, error) {
 		return Transaction{}, fmt.Errorf("failed to serialize message, err: %v", err)
 	}
 	for _, signer := range param.Signers {
-		idx, ok := m[signer.PublicKey]
+		publicKey := signer.Public()
+		idx, ok := m[publicKey]
 		if !ok {
-			return Transaction{}, fmt.Errorf("%w, %v is not a signer", ErrTransactionAddNotNecessarySignatures, signer.PublicKey)
+			return Transaction{}, fmt.Errorf("%w, %v is not a signer", ErrTransactionAddNotNecessarySignatures, publicKey)
+		}
+		sig, err := signer.SignMessage(ctx, data)
+		if err != nil {
+			return Transaction{}, fmt.Errorf("failed to sign by %v, err: %w", publicKey, err)
 		}
-		signatures[idx] = signer.Sign(data)
+		if !ed25519.Verify(publicKey.Bytes(), data, sig) {
+			return Transaction{}, fmt.Errorf("%w, signer: %v", ErrSignerInvalidSignature, publicKey)
+		}
+		signatures[idx] = sig
 	}
 
 	return Transaction{
@@ -94,30 +107,31 @@ func (tx *Transaction) Serialize() ([]byte, error) {
 	return output, nil
 }
 
-// TransactionDeserialize can deserialize a tx from byte array
+// TransactionDeserialize can deserialize a tx from byte array. the data must be exactly one tx.
 func TransactionDeserialize(tx []byte) (Transaction, error) {
-	signatureCount, err := parseUvarint(&tx)
+	d := &decoder{data: tx}
+	signatureCount, err := d.readLength(64)
 	if err != nil {
-		return Transaction{}, fmt.Errorf("parse signature count error: %v", err)
+		return Transaction{}, fmt.Errorf("parse signature count error: %w", err)
 	}
 	if signatureCount < 1 {
 		return Transaction{}, errors.New("signature count must be greater than or equal to 1")
 	}
-	if len(tx) < int(signatureCount)*64 {
-		return Transaction{}, errors.New("parse signature error")
-	}
 	signatures := make([]Signature, 0, signatureCount)
-	for i := 0; i < int(signatureCount); i++ {
-		signatures = append(signatures, tx[:64])
-		tx = tx[64:]
+	for i := 0; i < signatureCount; i++ {
+		sig, _ := d.readBytes(64)
+		signatures = append(signatures, sig)
 	}
 
-	message, err := MessageDeserialize(tx)
+	message, err := deserializeMessage(d)
 	if err != nil {
-		return Transaction{}, fmt.Errorf("failed to parse message, err: %v", err)
+		return Transaction{}, fmt.Errorf("failed to parse message, err: %w", err)
+	}
+	if err := d.finish(); err != nil {
+		return Transaction{}, err
 	}
 
-	if uint64(message.Header.NumRequireSignatures) != signatureCount {
+	if message.Header.NumRequireSignatures != uint8(signatureCount) || signatureCount > 255 {
 		return Transaction{}, errors.New("numRequireSignatures is not equal to signatureCount")
 	}
 
@@ -135,15 +149,3 @@ func MustTransactionDeserialize(data []byte) Transaction {
 	}
 	return tx
 }
-
-func parseUvarint(tx *[]byte) (uint64, error) {
-	if len(*tx) == 0 {
-		return 0, errors.New("data is empty")
-	}
-	u, n := binary.Uvarint(*tx)
-	if n <= 0 {
-		return 0, errors.New("format error")
-	}
-	*tx = (*tx)[n:]
-	return u, nil
-}
@@ -0,0 +1,201 @@
+package types
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/mr-tron/base58"
+)
+
+var ErrPartiallySignedTransactionMismatch = errors.New("partially signed transaction mismatch")
+
+// PartiallySignedTransaction is a portable encoding of a tx signed by several parties, in the
+// spirit of the output of `solana --sign-only`. it is passed from signer to signer, each one
+// adds its signatures, and the tx is sent once no signer is absent.
+type PartiallySignedTransaction struct {
+	// Message is the serialized message in base64, the data every signer signs
+	Message string `json:"message"`
+	// Blockhash is the recent blockhash of the message, for a quick look
+	Blockhash string `json:"blockhash"`
+	// Signers are the valid signatures as "<public key>=<signature>" in base58
+	Signers []string `json:"signers"`
+	// Absent are the signers without a signature
+	Absent []string `json:"absent"`
+	// BadSig are the signers with an invalid signature
+	BadSig []string `json:"badSig"`
+}
+
+func isEmptySignature(sig Signature) bool {
+	for _, b := range sig {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (tx *Transaction) checkSignatureSlots() error {
+	if len(tx.Signatures) != int(tx.Message.Header.NumRequireSignatures) ||
+		len(tx.Message.Accounts) < int(tx.Message.Header.NumRequireSignatures) {
+		return fmt.Errorf("%w: %v signatures, %v signers", ErrTransactionSignatureCountMismatch, len(tx.Signatures), tx.Message.Header.NumRequireSignatures)
+	}
+	return nil
+}
+
+// MissingSigners returns the signers whose signature slot is still empty, in order
+func (tx *Transaction) MissingSigners() []common.PublicKey {
+	missing := []common.PublicKey{}
+	for i := 0; i < int(tx.Message.Header.NumRequireSignatures) && i < len(tx.Message.Accounts); i++ {
+		if i >= len(tx.Signatures) || isEmptySignature(tx.Signatures[i]) {
+			missing = append(missing, tx.Message.Accounts[i])
+		}
+	}
+	return missing
+}
+
+// AddSignatureOf verifies a detached signature of the public key and puts it into its slot
+func (tx *Transaction) AddSignatureOf(publicKey common.PublicKey, sig []byte) error {
+	if err := tx.checkSignatureSlots(); err != nil {
+		return err
+	}
+	for i := 0; i < int(tx.Message.Header.NumRequireSignatures); i++ {
+		if tx.Message.Accounts[i] != publicKey {
+			continue
+		}
+		data, err := tx.Message.Serialize()
+		if err != nil {
+			return fmt.Errorf("failed to serialize message, err: %v", err)
+		}
+		if len(sig) != ed25519.SignatureSize || !ed25519.Verify(publicKey.Bytes(), data, sig) {
+			return fmt.Errorf("%w, signer: %v", ErrSignerInvalidSignature, publicKey)
+		}
+		tx.Signatures[i] = sig
+		return nil
+	}
+	return fmt.Errorf("%w, %v is not a signer", ErrTransactionAddNotNecessarySignatures, publicKey)
+}
+
+// PartialSign signs the tx by the signers and keeps the existing signatures of the others
+func (tx *Transaction) PartialSign(ctx context.Context, signers ...Signer) error {
+	if err := tx.checkSignatureSlots(); err != nil {
+		return err
+	}
+	data, err := tx.Message.Serialize()
+	if err != nil {
+		return fmt.Errorf("failed to serialize message, err: %v", err)
+	}
+	for _, signer := range signers {
+		publicKey := signer.Public()
+		sig, err := signer.SignMessage(ctx, data)
+		if err != nil {
+			return fmt.Errorf("failed to sign by %v, err: %w", publicKey, err)
+		}
+		if err := tx.AddSignatureOf(publicKey, sig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ToPartiallySigned exports the tx with the signatures it has so far
+func (tx *Transaction) ToPartiallySigned() (PartiallySignedTransaction, error) {
+	if err := tx.checkSignatureSlots(); err != nil {
+		return PartiallySignedTransaction{}, err
+	}
+	data, err := tx.Message.Serialize()
+	if err != nil {
+		return PartiallySignedTransaction{}, fmt.Errorf("failed to serialize message, err: %v", err)
+	}
+
+	p := PartiallySignedTransaction{
+		Message:   base64.StdEncoding.EncodeToString(data),
+		Blockhash: tx.Message.RecentBlockHash,
+		Signers:   []string{},
+		Absent:    []string{},
+		BadSig:    []string{},
+	}
+	for i, sig := range tx.Signatures {
+		signer := tx.Message.Accounts[i]
+		switch {
+		case isEmptySignature(sig):
+			p.Absent = append(p.Absent, signer.ToBase58())
+		case len(sig) != ed25519.SignatureSize || !ed25519.Verify(signer.Bytes(), data, sig):
+			p.BadSig = append(p.BadSig, signer.ToBase58())
+		default:
+			p.Signers = append(p.Signers, signer.ToBase58()+"="+base58.Encode(sig))
+		}
+	}
+	return p, nil
+}
+
+// Transaction decodes the message and fills in the signatures of Signers. the slots of the
+// other signers are left empty.
+func (p PartiallySignedTransaction) Transaction() (Transaction, error) {
+	data, err := base64.StdEncoding.DecodeString(p.Message)
+	if err != nil {
+		return Transaction{}, fmt.Errorf("failed to decode message, err: %v", err)
+	}
+	message, err := MessageDeserialize(data)
+	if err != nil {
+		return Transaction{}, fmt.Errorf("failed to parse message, err: %w", err)
+	}
+	if p.Blockhash != "" && p.Blockhash != message.RecentBlockHash {
+		return Transaction{}, fmt.Errorf("%w: blockhash %v, message has %v", ErrPartiallySignedTransactionMismatch, p.Blockhash, message.RecentBlockHash)
+	}
+
+	signatures := make([]Signature, 0, message.Header.NumRequireSignatures)
+	for i := uint8(0); i < message.Header.NumRequireSignatures; i++ {
+		signatures = append(signatures, make([]byte, 64))
+	}
+	tx := Transaction{
+		Signatures: signatures,
+		Message:    message,
+	}
+	if err := tx.addEncodedSignatures(p.Signers); err != nil {
+		return Transaction{}, err
+	}
+	return tx, nil
+}
+
+// MergePartiallySigned adds the signatures of p, which must be for the same message
+func (tx *Transaction) MergePartiallySigned(p PartiallySignedTransaction) error {
+	data, err := base64.StdEncoding.DecodeString(p.Message)
+	if err != nil {
+		return fmt.Errorf("failed to decode message, err: %v", err)
+	}
+	message, err := tx.Message.Serialize()
+	if err != nil {
+		return fmt.Errorf("failed to serialize message, err: %v", err)
+	}
+	if !bytes.Equal(data, message) {
+		return fmt.Errorf("%w: different message", ErrPartiallySignedTransactionMismatch)
+	}
+	return tx.addEncodedSignatures(p.Signers)
+}
+
+func (tx *Transaction) addEncodedSignatures(signers []string) error {
+	for _, signer := range signers {
+		publicKey, sig, ok := strings.Cut(signer, "=")
+		if !ok {
+			return fmt.Errorf("invalid signer %q, expect <public key>=<signature>", signer)
+		}
+		key, err := base58.Decode(publicKey)
+		if err != nil || len(key) != common.PublicKeyLength {
+			return fmt.Errorf("invalid public key %q", publicKey)
+		}
+		b, err := base58.Decode(sig)
+		if err != nil {
+			return fmt.Errorf("invalid signature of %v, err: %v", publicKey, err)
+		}
+		if err := tx.AddSignatureOf(common.PublicKeyFromBytes(key), b); err != nil {
+			return err
+		}
+	}
+	return nil
+}
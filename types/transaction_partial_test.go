@@ -0,0 +1,118 @@
+package types
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	"github.com/labyla/solana-go-sdk/common"
+	"github.com/mr-tron/base58"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransaction_PartiallySigned(t *testing.T) {
+	feePayer := NewAccount()
+	cold1 := NewAccount()
+	cold2 := NewAccount()
+	message := NewMessage(NewMessageParam{
+		FeePayer: feePayer.PublicKey,
+		Instructions: []Instruction{
+			{
+				ProgramID: common.MemoProgramID,
+				Accounts: []AccountMeta{
+					{PubKey: cold1.PublicKey, IsSigner: true},
+					{PubKey: cold2.PublicKey, IsSigner: true},
+				},
+				Data: []byte("memo"),
+			},
+		},
+		RecentBlockhash: "FwRYtTPRk5N4wUeP87rTw9kQVSwigB6kbikGzzeCMrW5",
+	})
+
+	// the hot side signs what it can and exports the rest
+	tx, err := NewTransaction(NewTransactionParam{Message: message})
+	require.Nil(t, err)
+	require.Nil(t, tx.PartialSign(context.Background(), feePayer))
+	assert.ElementsMatch(t, []common.PublicKey{cold1.PublicKey, cold2.PublicKey}, tx.MissingSigners())
+
+	exported, err := tx.ToPartiallySigned()
+	require.Nil(t, err)
+	assert.Equal(t, message.RecentBlockHash, exported.Blockhash)
+	assert.Equal(t, []string{feePayer.PublicKey.ToBase58() + "=" + base58.Encode(tx.Signatures[0])}, exported.Signers)
+	assert.ElementsMatch(t, []string{cold1.PublicKey.ToBase58(), cold2.PublicKey.ToBase58()}, exported.Absent)
+	assert.Empty(t, exported.BadSig)
+
+	// it survives json
+	b, err := json.Marshal(exported)
+	require.Nil(t, err)
+	var decoded PartiallySignedTransaction
+	require.Nil(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, exported, decoded)
+
+	// a cold signer only needs the message bytes
+	data, err := base64.StdEncoding.DecodeString(decoded.Message)
+	require.Nil(t, err)
+	require.Nil(t, tx.AddSignatureOf(cold1.PublicKey, cold1.Sign(data)))
+
+	// another one works on its own copy
+	cold2Tx, err := decoded.Transaction()
+	require.Nil(t, err)
+	assert.ElementsMatch(t, []common.PublicKey{cold1.PublicKey, cold2.PublicKey}, cold2Tx.MissingSigners())
+	require.Nil(t, cold2Tx.PartialSign(context.Background(), cold2))
+	cold2Signed, err := cold2Tx.ToPartiallySigned()
+	require.Nil(t, err)
+
+	require.Nil(t, tx.MergePartiallySigned(cold2Signed))
+	assert.Empty(t, tx.MissingSigners())
+	assert.Nil(t, tx.Verify())
+}
+
+func TestTransaction_PartiallySigned_Error(t *testing.T) {
+	feePayer := NewAccount()
+	other := NewAccount()
+	message := NewMessage(NewMessageParam{
+		FeePayer:        feePayer.PublicKey,
+		Instructions:    []Instruction{},
+		RecentBlockhash: "FwRYtTPRk5N4wUeP87rTw9kQVSwigB6kbikGzzeCMrW5",
+	})
+	tx, err := NewTransaction(NewTransactionParam{Message: message})
+	require.Nil(t, err)
+	data, err := message.Serialize()
+	require.Nil(t, err)
+
+	assert.ErrorIs(t, tx.AddSignatureOf(other.PublicKey, other.Sign(data)), ErrTransactionAddNotNecessarySignatures)
+	assert.ErrorIs(t, tx.AddSignatureOf(feePayer.PublicKey, other.Sign(data)), ErrSignerInvalidSignature)
+	assert.ErrorIs(t, tx.PartialSign(context.Background(), other), ErrTransactionAddNotNecessarySignatures)
+
+	// a bad signature is reported and not carried over
+	tx.Signatures[0] = other.Sign(data)
+	exported, err := tx.ToPartiallySigned()
+	require.Nil(t, err)
+	assert.Equal(t, []string{feePayer.PublicKey.ToBase58()}, exported.BadSig)
+	assert.Empty(t, exported.Signers)
+
+	// signatures of another message
+	otherTx, err := NewTransaction(NewTransactionParam{
+		Message: NewMessage(NewMessageParam{
+			FeePayer:        feePayer.PublicKey,
+			Instructions:    []Instruction{},
+			RecentBlockhash: "9rAtxuhtKn8qagc3UtZFyhLrw5zkh6etv43TibaXuSKo",
+		}),
+		Signers: []Signer{feePayer},
+	})
+	require.Nil(t, err)
+	otherExported, err := otherTx.ToPartiallySigned()
+	require.Nil(t, err)
+	assert.ErrorIs(t, tx.MergePartiallySigned(otherExported), ErrPartiallySignedTransactionMismatch)
+
+	otherExported.Blockhash = message.RecentBlockHash
+	_, err = otherExported.Transaction()
+	assert.ErrorIs(t, err, ErrPartiallySignedTransactionMismatch)
+
+	otherExported.Blockhash = ""
+	otherExported.Signers = []string{"invalid"}
+	_, err = otherExported.Transaction()
+	assert.Error(t, err)
+}
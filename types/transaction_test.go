@@ -243,7 +243,7 @@ func TestNewTransaction(t *testing.T) {
 
 	type args struct {
 		message Message
-		signers []Account
+		signers []Signer
 	}
 	tests := []struct {
 		name string
@@ -254,7 +254,7 @@ func TestNewTransaction(t *testing.T) {
 		{
 			args: args{
 				message: msg[0],
-				signers: []Account{},
+				signers: []Signer{},
 			},
 			want: Transaction{
 				Signatures: []Signature{
@@ -266,7 +266,7 @@ func TestNewTransaction(t *testing.T) {
 		{
 			args: args{
 				message: msg[0],
-				signers: []Account{testAccount2},
+				signers: []Signer{testAccount2},
 			},
 			want: Transaction{},
 			err:  ErrTransactionAddNotNecessarySignatures,
@@ -274,7 +274,7 @@ func TestNewTransaction(t *testing.T) {
 		{
 			args: args{
 				message: msg[0],
-				signers: []Account{
+				signers: []Signer{
 					testAccount1,
 				},
 			},
@@ -288,7 +288,7 @@ func TestNewTransaction(t *testing.T) {
 		{
 			args: args{
 				message: msg[1],
-				signers: []Account{},
+				signers: []Signer{},
 			},
 			want: Transaction{
 				Signatures: []Signature{
@@ -301,7 +301,7 @@ func TestNewTransaction(t *testing.T) {
 		{
 			args: args{
 				message: msg[1],
-				signers: []Account{testAccount1},
+				signers: []Signer{testAccount1},
 			},
 			want: Transaction{
 				Signatures: []Signature{
@@ -314,7 +314,7 @@ func TestNewTransaction(t *testing.T) {
 		{
 			args: args{
 				message: msg[1],
-				signers: []Account{testAccount2},
+				signers: []Signer{testAccount2},
 			},
 			want: Transaction{
 				Signatures: []Signature{
@@ -327,7 +327,7 @@ func TestNewTransaction(t *testing.T) {
 		{
 			args: args{
 				message: msg[1],
-				signers: []Account{testAccount2, testAccount1},
+				signers: []Signer{testAccount2, testAccount1},
 			},
 			want: Transaction{
 				Signatures: []Signature{
@@ -340,7 +340,7 @@ func TestNewTransaction(t *testing.T) {
 		{
 			args: args{
 				message: msg[1],
-				signers: []Account{testAccount1, testAccount2},
+				signers: []Signer{testAccount1, testAccount2},
 			},
 			want: Transaction{
 				Signatures: []Signature{
@@ -353,7 +353,7 @@ func TestNewTransaction(t *testing.T) {
 		{
 			args: args{
 				message: msg[2],
-				signers: []Account{testAccount2},
+				signers: []Signer{testAccount2},
 			},
 			want: Transaction{
 				Signatures: []Signature{
@@ -0,0 +1,25 @@
+package ws
+
+import (
+	"context"
+
+	"github.com/labyla/solana-go-sdk/rpc"
+)
+
+type AccountNotification rpc.ValueWithContext[rpc.AccountInfo]
+
+// AccountSubscribeConfig is a option config for `accountSubscribe`
+type AccountSubscribeConfig struct {
+	Commitment rpc.Commitment      `json:"commitment,omitempty"`
+	Encoding   rpc.AccountEncoding `json:"encoding,omitempty"`
+}
+
+// AccountSubscribe notifies when the lamports or data of an account change
+func (c *Client) AccountSubscribe(ctx context.Context, base58Addr string) (*Subscription[AccountNotification], error) {
+	return subscribe[AccountNotification](ctx, c, newSubscription("accountSubscribe", "accountUnsubscribe", []any{base58Addr}, c.bufferSize))
+}
+
+// AccountSubscribeWithConfig notifies when the lamports or data of an account change
+func (c *Client) AccountSubscribeWithConfig(ctx context.Context, base58Addr string, cfg AccountSubscribeConfig) (*Subscription[AccountNotification], error) {
+	return subscribe[AccountNotification](ctx, c, newSubscription("accountSubscribe", "accountUnsubscribe", []any{base58Addr, cfg}, c.bufferSize))
+}
@@ -0,0 +1,59 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/labyla/solana-go-sdk/rpc"
+)
+
+type BlockNotification rpc.ValueWithContext[BlockNotificationValue]
+
+type BlockNotificationValue struct {
+	Slot  uint64        `json:"slot"`
+	Err   any           `json:"err"`
+	Block *rpc.GetBlock `json:"block"`
+}
+
+// BlockSubscribeFilter selects which blocks are delivered
+type BlockSubscribeFilter struct {
+	mentionsAccountOrProgram string
+}
+
+// BlockSubscribeFilterAll subscribes to all blocks
+var BlockSubscribeFilterAll = BlockSubscribeFilter{}
+
+// BlockSubscribeFilterMentions subscribes to blocks which contain a tx mentioning the address
+func BlockSubscribeFilterMentions(base58Addr string) BlockSubscribeFilter {
+	return BlockSubscribeFilter{mentionsAccountOrProgram: base58Addr}
+}
+
+func (f BlockSubscribeFilter) MarshalJSON() ([]byte, error) {
+	if f.mentionsAccountOrProgram == "" {
+		return json.Marshal("all")
+	}
+	return json.Marshal(struct {
+		MentionsAccountOrProgram string `json:"mentionsAccountOrProgram"`
+	}{
+		MentionsAccountOrProgram: f.mentionsAccountOrProgram,
+	})
+}
+
+// BlockSubscribeConfig is a option config for `blockSubscribe`
+type BlockSubscribeConfig struct {
+	Commitment                     rpc.Commitment                       `json:"commitment,omitempty"`
+	Encoding                       rpc.GetBlockConfigEncoding           `json:"encoding,omitempty"`
+	TransactionDetails             rpc.GetBlockConfigTransactionDetails `json:"transactionDetails,omitempty"`
+	ShowRewards                    *bool                                `json:"showRewards,omitempty"`
+	MaxSupportedTransactionVersion *uint8                               `json:"maxSupportedTransactionVersion,omitempty"`
+}
+
+// BlockSubscribe notifies when a new block is confirmed or finalized
+func (c *Client) BlockSubscribe(ctx context.Context, filter BlockSubscribeFilter) (*Subscription[BlockNotification], error) {
+	return subscribe[BlockNotification](ctx, c, newSubscription("blockSubscribe", "blockUnsubscribe", []any{filter}, c.bufferSize))
+}
+
+// BlockSubscribeWithConfig notifies when a new block is confirmed or finalized
+func (c *Client) BlockSubscribeWithConfig(ctx context.Context, filter BlockSubscribeFilter, cfg BlockSubscribeConfig) (*Subscription[BlockNotification], error) {
+	return subscribe[BlockNotification](ctx, c, newSubscription("blockSubscribe", "blockUnsubscribe", []any{filter, cfg}, c.bufferSize))
+}
@@ -0,0 +1,456 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labyla/solana-go-sdk/rpc"
+)
+
+const (
+	LocalnetWSEndpoint = "ws://localhost:8900"
+	DevnetWSEndpoint   = "wss://api.devnet.solana.com"
+	TestnetWSEndpoint  = "wss://api.testnet.solana.com"
+	MainnetWSEndpoint  = "wss://api.mainnet-beta.solana.com"
+)
+
+var (
+	ErrClientClosed        = errors.New("ws: client closed")
+	ErrUnexpectedReconnect = errors.New("ws: connection lost before response")
+)
+
+type jsonRpcMessage struct {
+	JsonRpc string            `json:"jsonrpc"`
+	Id      *uint64           `json:"id,omitempty"`
+	Method  string            `json:"method,omitempty"`
+	Result  json.RawMessage   `json:"result,omitempty"`
+	Error   *rpc.JsonRpcError `json:"error,omitempty"`
+	Params  *struct {
+		Result       json.RawMessage `json:"result"`
+		Subscription uint64          `json:"subscription"`
+	} `json:"params,omitempty"`
+}
+
+type pendingRequest struct {
+	sub *subscription
+	ch  chan pendingResult
+}
+
+type pendingResult struct {
+	msg jsonRpcMessage
+	err error
+}
+
+// Client is a solana pubsub client. It keeps a single websocket connection
+// and transparently reconnects and resubscribes when the connection drops.
+type Client struct {
+	endpoint          string
+	dialer            *websocket.Dialer
+	header            http.Header
+	reconnectInterval time.Duration
+	pingInterval      time.Duration
+	bufferSize        int
+
+	writeMu sync.Mutex
+	conn    *websocket.Conn
+
+	mu       sync.Mutex
+	nextId   uint64
+	pending  map[uint64]*pendingRequest
+	subs     map[*subscription]struct{}
+	byRemote map[uint64]*subscription
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// Connect dials the endpoint and returns a client which is ready to subscribe.
+func Connect(ctx context.Context, endpoint string, opts ...Option) (*Client, error) {
+	c := &Client{
+		endpoint: endpoint,
+		pending:  map[uint64]*pendingRequest{},
+		subs:     map[*subscription]struct{}{},
+		byRemote: map[uint64]*subscription{},
+		closed:   make(chan struct{}),
+	}
+
+	setDefaultOptions(c)
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.conn = conn
+
+	go c.run(conn)
+	go c.ping()
+
+	return c, nil
+}
+
+// Close stops the client and ends all subscriptions.
+func (c *Client) Close() error {
+	var err error
+	c.closeOnce.Do(func() {
+		close(c.closed)
+
+		c.mu.Lock()
+		for sub := range c.subs {
+			sub.stop()
+		}
+		c.subs = map[*subscription]struct{}{}
+		c.byRemote = map[uint64]*subscription{}
+		c.mu.Unlock()
+
+		c.writeMu.Lock()
+		if c.conn != nil {
+			err = c.conn.Close()
+		}
+		c.writeMu.Unlock()
+	})
+	return err
+}
+
+func (c *Client) dial(ctx context.Context) (*websocket.Conn, error) {
+	conn, _, err := c.dialer.DialContext(ctx, c.endpoint, c.header)
+	if err != nil {
+		return nil, fmt.Errorf("ws: failed to dial, err: %v", err)
+	}
+	return conn, nil
+}
+
+func (c *Client) isClosed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// run reads from the connection until the client is closed. every time the
+// connection is lost it dials again and restores all live subscriptions.
+func (c *Client) run(conn *websocket.Conn) {
+	for {
+		c.read(conn)
+		if c.isClosed() {
+			return
+		}
+
+		c.mu.Lock()
+		for id, p := range c.pending {
+			if p.sub == nil {
+				p.ch <- pendingResult{err: ErrUnexpectedReconnect}
+			}
+			delete(c.pending, id)
+		}
+		c.byRemote = map[uint64]*subscription{}
+		c.mu.Unlock()
+
+		conn = c.reconnect()
+		if conn == nil {
+			return
+		}
+	}
+}
+
+func (c *Client) reconnect() *websocket.Conn {
+	for {
+		select {
+		case <-c.closed:
+			return nil
+		case <-time.After(c.reconnectInterval):
+		}
+
+		conn, err := c.dial(context.Background())
+		if err != nil {
+			continue
+		}
+
+		c.writeMu.Lock()
+		if c.isClosed() {
+			c.writeMu.Unlock()
+			conn.Close()
+			return nil
+		}
+		c.conn = conn
+		c.writeMu.Unlock()
+
+		if err := c.resubscribe(); err != nil {
+			conn.Close()
+			continue
+		}
+
+		return conn
+	}
+}
+
+func (c *Client) resubscribe() error {
+	c.mu.Lock()
+	subs := make([]*subscription, 0, len(c.subs))
+	for sub := range c.subs {
+		subs = append(subs, sub)
+	}
+	c.mu.Unlock()
+
+	for _, sub := range subs {
+		if _, err := c.send(sub.method, sub.params, &pendingRequest{sub: sub}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Client) ping() {
+	if c.pingInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(c.pingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.closed:
+			return
+		case <-ticker.C:
+			c.writeMu.Lock()
+			_ = c.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(c.pingInterval))
+			c.writeMu.Unlock()
+		}
+	}
+}
+
+func (c *Client) read(conn *websocket.Conn) {
+	for {
+		_, b, err := conn.ReadMessage()
+		if err != nil {
+			conn.Close()
+			return
+		}
+
+		var msg jsonRpcMessage
+		if err := json.Unmarshal(b, &msg); err != nil {
+			continue
+		}
+
+		if msg.Id != nil {
+			c.handleResponse(msg)
+			continue
+		}
+
+		if msg.Params != nil {
+			c.handleNotification(msg)
+		}
+	}
+}
+
+func (c *Client) handleResponse(msg jsonRpcMessage) {
+	c.mu.Lock()
+	p, ok := c.pending[*msg.Id]
+	delete(c.pending, *msg.Id)
+	if !ok {
+		c.mu.Unlock()
+		return
+	}
+
+	if p.sub == nil {
+		c.mu.Unlock()
+		p.ch <- pendingResult{msg: msg}
+		return
+	}
+
+	// the subscription may have been dropped while we waited for the response
+	if _, live := c.subs[p.sub]; !live {
+		c.mu.Unlock()
+		if msg.Error == nil {
+			var remoteId uint64
+			if json.Unmarshal(msg.Result, &remoteId) == nil {
+				go c.request(context.Background(), p.sub.unsubscribeMethod, []any{remoteId})
+			}
+		}
+		return
+	}
+
+	if msg.Error != nil {
+		delete(c.subs, p.sub)
+		c.mu.Unlock()
+		p.sub.confirm(0, msg.Error)
+		p.sub.stop()
+		return
+	}
+
+	var remoteId uint64
+	if err := json.Unmarshal(msg.Result, &remoteId); err != nil {
+		delete(c.subs, p.sub)
+		c.mu.Unlock()
+		p.sub.confirm(0, fmt.Errorf("ws: failed to decode subscription id, err: %v", err))
+		p.sub.stop()
+		return
+	}
+	c.byRemote[remoteId] = p.sub
+	c.mu.Unlock()
+
+	p.sub.confirm(remoteId, nil)
+}
+
+func (c *Client) handleNotification(msg jsonRpcMessage) {
+	c.mu.Lock()
+	sub, ok := c.byRemote[msg.Params.Subscription]
+	final := ok && sub.isFinal != nil && sub.isFinal(msg.Params.Result)
+	if final {
+		// the node drops one-shot subscriptions by itself after the final notification
+		delete(c.byRemote, msg.Params.Subscription)
+		delete(c.subs, sub)
+	}
+	c.mu.Unlock()
+	if !ok {
+		return
+	}
+
+	sub.deliver(msg.Params.Result)
+	if final {
+		sub.finish()
+	}
+}
+
+func (c *Client) send(method string, params []any, p *pendingRequest) (uint64, error) {
+	c.mu.Lock()
+	c.nextId++
+	id := c.nextId
+	c.pending[id] = p
+	c.mu.Unlock()
+
+	j, err := json.Marshal(rpc.JsonRpcRequest{
+		JsonRpc: "2.0",
+		Id:      id,
+		Method:  method,
+		Params:  params,
+	})
+	if err != nil {
+		c.forget(id)
+		return 0, fmt.Errorf("ws: failed to prepare payload, err: %v", err)
+	}
+
+	c.writeMu.Lock()
+	err = c.conn.WriteMessage(websocket.TextMessage, j)
+	c.writeMu.Unlock()
+	if err != nil {
+		c.forget(id)
+		return 0, fmt.Errorf("ws: failed to write message, err: %v", err)
+	}
+
+	return id, nil
+}
+
+func (c *Client) forget(id uint64) {
+	c.mu.Lock()
+	delete(c.pending, id)
+	c.mu.Unlock()
+}
+
+// request sends a plain request and waits for its response
+func (c *Client) request(ctx context.Context, method string, params []any) (json.RawMessage, error) {
+	if c.isClosed() {
+		return nil, ErrClientClosed
+	}
+
+	p := &pendingRequest{ch: make(chan pendingResult, 1)}
+	id, err := c.send(method, params, p)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case res := <-p.ch:
+		if res.err != nil {
+			return nil, res.err
+		}
+		if res.msg.Error != nil {
+			return nil, res.msg.Error
+		}
+		return res.msg.Result, nil
+	case <-ctx.Done():
+		c.forget(id)
+		return nil, ctx.Err()
+	case <-c.closed:
+		return nil, ErrClientClosed
+	}
+}
+
+// subscribe registers the subscription and waits until the node confirms it
+func (c *Client) subscribe(ctx context.Context, sub *subscription) error {
+	if c.isClosed() {
+		return ErrClientClosed
+	}
+
+	c.mu.Lock()
+	c.subs[sub] = struct{}{}
+	c.mu.Unlock()
+
+	if _, err := c.send(sub.method, sub.params, &pendingRequest{sub: sub}); err != nil {
+		// the connection is broken, the subscription will be sent again after reconnecting
+		if c.isClosed() {
+			c.drop(sub)
+			return err
+		}
+	}
+
+	select {
+	case <-sub.confirmed:
+		return sub.confirmErr
+	case <-ctx.Done():
+		c.drop(sub)
+		return ctx.Err()
+	case <-c.closed:
+		return ErrClientClosed
+	}
+}
+
+func (c *Client) drop(sub *subscription) {
+	c.mu.Lock()
+	delete(c.subs, sub)
+	for id, s := range c.byRemote {
+		if s == sub {
+			delete(c.byRemote, id)
+		}
+	}
+	c.mu.Unlock()
+	sub.stop()
+}
+
+func (c *Client) unsubscribe(ctx context.Context, sub *subscription) error {
+	c.mu.Lock()
+	_, live := c.subs[sub]
+	c.mu.Unlock()
+
+	c.drop(sub)
+	if !live {
+		return nil
+	}
+
+	result, err := c.request(ctx, sub.unsubscribeMethod, []any{sub.remoteId()})
+	if err != nil {
+		if errors.Is(err, ErrClientClosed) {
+			return nil
+		}
+		return err
+	}
+
+	var ok bool
+	if err := json.Unmarshal(result, &ok); err != nil {
+		return fmt.Errorf("ws: failed to decode unsubscribe result, err: %v", err)
+	}
+	if !ok {
+		return fmt.Errorf("ws: %v returned false", sub.unsubscribeMethod)
+	}
+	return nil
+}
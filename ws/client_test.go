@@ -0,0 +1,292 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/labyla/solana-go-sdk/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type request struct {
+	Id     uint64            `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// standIn is a minimal pubsub node. every subscribe request gets a new id and
+// the handler decides what to push after confirming it.
+type standIn struct {
+	t        *testing.T
+	server   *httptest.Server
+	mu       sync.Mutex
+	conns    []*websocket.Conn
+	requests chan request
+	nextSub  uint64
+	reject   map[string]bool
+}
+
+func newStandIn(t *testing.T) *standIn {
+	s := &standIn{
+		t:        t,
+		requests: make(chan request, 16),
+		nextSub:  100,
+		reject:   map[string]bool{},
+	}
+	upgrader := websocket.Upgrader{}
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns = append(s.conns, conn)
+		s.mu.Unlock()
+		for {
+			_, b, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+			var req request
+			assert.Nil(t, json.Unmarshal(b, &req))
+
+			s.mu.Lock()
+			switch {
+			case s.reject[req.Method]:
+				s.write(conn, `{"jsonrpc":"2.0","error":{"code":-32602,"message":"Invalid params"},"id":%d}`, req.Id)
+			case strings.HasSuffix(req.Method, "Unsubscribe"):
+				s.write(conn, `{"jsonrpc":"2.0","result":true,"id":%d}`, req.Id)
+			default:
+				s.nextSub++
+				s.write(conn, `{"jsonrpc":"2.0","result":%d,"id":%d}`, s.nextSub, req.Id)
+			}
+			s.mu.Unlock()
+
+			s.requests <- req
+		}
+	}))
+	return s
+}
+
+func (s *standIn) url() string {
+	return "ws" + strings.TrimPrefix(s.server.URL, "http")
+}
+
+func (s *standIn) write(conn *websocket.Conn, format string, args ...any) {
+	assert.Nil(s.t, conn.WriteMessage(websocket.TextMessage, []byte(fmt.Sprintf(format, args...))))
+}
+
+func (s *standIn) notify(method string, subscription uint64, result string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	conn := s.conns[len(s.conns)-1]
+	s.write(conn, `{"jsonrpc":"2.0","method":"%s","params":{"result":%s,"subscription":%d}}`, method, result, subscription)
+}
+
+func (s *standIn) dropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, conn := range s.conns {
+		conn.Close()
+	}
+}
+
+func (s *standIn) next(t *testing.T) request {
+	select {
+	case req := <-s.requests:
+		return req
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for request")
+	}
+	return request{}
+}
+
+func recv[T any](t *testing.T, s *Subscription[T]) (T, bool) {
+	select {
+	case v, ok := <-s.Recv():
+		return v, ok
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for notification")
+	}
+	var v T
+	return v, false
+}
+
+func TestClient_AccountSubscribe(t *testing.T) {
+	s := newStandIn(t)
+	defer s.server.Close()
+
+	c, err := Connect(context.Background(), s.url())
+	require.Nil(t, err)
+	defer c.Close()
+
+	sub, err := c.AccountSubscribeWithConfig(
+		context.Background(),
+		"RNfp4xTbBb4C3kcv2KqtAj8mu4YhMHxqm1Skg9uchZ7",
+		AccountSubscribeConfig{
+			Commitment: rpc.CommitmentConfirmed,
+			Encoding:   rpc.AccountEncodingBase64,
+		},
+	)
+	require.Nil(t, err)
+
+	req := s.next(t)
+	assert.Equal(t, "accountSubscribe", req.Method)
+	assert.JSONEq(t, `"RNfp4xTbBb4C3kcv2KqtAj8mu4YhMHxqm1Skg9uchZ7"`, string(req.Params[0]))
+	assert.JSONEq(t, `{"commitment":"confirmed","encoding":"base64"}`, string(req.Params[1]))
+
+	s.notify("accountNotification", 101, `{"context":{"slot":5199307},"value":{"data":["","base64"],"executable":false,"lamports":33594,"owner":"11111111111111111111111111111111","rentEpoch":635}}`)
+	got, ok := recv(t, sub)
+	assert.True(t, ok)
+	assert.Equal(t, AccountNotification{
+		Context: rpc.Context{Slot: 5199307},
+		Value: rpc.AccountInfo{
+			Lamports:   33594,
+			Owner:      "11111111111111111111111111111111",
+			RentEpoch:  635,
+			Data:       []any{"", "base64"},
+			Executable: false,
+		},
+	}, got)
+
+	// notifications of unknown subscriptions are ignored
+	s.notify("accountNotification", 999, `{"context":{"slot":1},"value":null}`)
+
+	require.Nil(t, sub.Unsubscribe(context.Background()))
+	req = s.next(t)
+	assert.Equal(t, "accountUnsubscribe", req.Method)
+	assert.JSONEq(t, `101`, string(req.Params[0]))
+
+	_, ok = recv(t, sub)
+	assert.False(t, ok)
+}
+
+func TestClient_SignatureSubscribe(t *testing.T) {
+	s := newStandIn(t)
+	defer s.server.Close()
+
+	c, err := Connect(context.Background(), s.url())
+	require.Nil(t, err)
+	defer c.Close()
+
+	sub, err := c.SignatureSubscribeWithConfig(
+		context.Background(),
+		"2EBVM6cB8vAAD93Ktr6Vd8p67XPbQzCJX47MpReuiCXJAtcjaxpvWpcg9Ege1Nr5Tk3a2GFrByT7WPBjdsTycY9b",
+		SignatureSubscribeConfig{
+			Commitment:                 rpc.CommitmentFinalized,
+			EnableReceivedNotification: true,
+		},
+	)
+	require.Nil(t, err)
+	req := s.next(t)
+	assert.Equal(t, "signatureSubscribe", req.Method)
+
+	s.notify("signatureNotification", 101, `{"context":{"slot":5207624},"value":"receivedSignature"}`)
+	s.notify("signatureNotification", 101, `{"context":{"slot":5207630},"value":{"err":null}}`)
+
+	got, ok := recv(t, sub)
+	assert.True(t, ok)
+	assert.Equal(t, SignatureNotification{Context: rpc.Context{Slot: 5207624}, Value: SignatureNotificationValue{Received: true}}, got)
+
+	got, ok = recv(t, sub)
+	assert.True(t, ok)
+	assert.Equal(t, SignatureNotification{Context: rpc.Context{Slot: 5207630}, Value: SignatureNotificationValue{}}, got)
+
+	// the node ends the subscription by itself
+	_, ok = recv(t, sub)
+	assert.False(t, ok)
+	assert.Nil(t, sub.Unsubscribe(context.Background()))
+}
+
+func TestClient_LogsAndBlockSubscribeParams(t *testing.T) {
+	s := newStandIn(t)
+	defer s.server.Close()
+
+	c, err := Connect(context.Background(), s.url())
+	require.Nil(t, err)
+	defer c.Close()
+
+	_, err = c.LogsSubscribe(context.Background(), LogsSubscribeFilterAll)
+	require.Nil(t, err)
+	req := s.next(t)
+	assert.Equal(t, "logsSubscribe", req.Method)
+	assert.JSONEq(t, `"all"`, string(req.Params[0]))
+
+	_, err = c.LogsSubscribeWithConfig(context.Background(), LogsSubscribeFilterMentions("11111111111111111111111111111111"), LogsSubscribeConfig{Commitment: rpc.CommitmentProcessed})
+	require.Nil(t, err)
+	req = s.next(t)
+	assert.JSONEq(t, `{"mentions":["11111111111111111111111111111111"]}`, string(req.Params[0]))
+	assert.JSONEq(t, `{"commitment":"processed"}`, string(req.Params[1]))
+
+	_, err = c.BlockSubscribe(context.Background(), BlockSubscribeFilterMentions("11111111111111111111111111111111"))
+	require.Nil(t, err)
+	req = s.next(t)
+	assert.Equal(t, "blockSubscribe", req.Method)
+	assert.JSONEq(t, `{"mentionsAccountOrProgram":"11111111111111111111111111111111"}`, string(req.Params[0]))
+
+	slots, err := c.SlotSubscribe(context.Background())
+	require.Nil(t, err)
+	req = s.next(t)
+	assert.Equal(t, "slotSubscribe", req.Method)
+	assert.Len(t, req.Params, 0)
+
+	s.notify("slotNotification", 104, `{"parent":75,"root":44,"slot":76}`)
+	slot, ok := recv(t, slots)
+	assert.True(t, ok)
+	assert.Equal(t, SlotNotification{Parent: 75, Root: 44, Slot: 76}, slot)
+}
+
+func TestClient_SubscribeError(t *testing.T) {
+	s := newStandIn(t)
+	defer s.server.Close()
+	s.reject["programSubscribe"] = true
+
+	c, err := Connect(context.Background(), s.url())
+	require.Nil(t, err)
+	defer c.Close()
+
+	_, err = c.ProgramSubscribe(context.Background(), "11111111111111111111111111111111")
+	assert.Equal(t, &rpc.JsonRpcError{Code: -32602, Message: "Invalid params"}, err)
+}
+
+func TestClient_Resubscribe(t *testing.T) {
+	s := newStandIn(t)
+	defer s.server.Close()
+
+	c, err := Connect(context.Background(), s.url(), WithReconnectInterval(10*time.Millisecond))
+	require.Nil(t, err)
+	defer c.Close()
+
+	roots, err := c.RootSubscribe(context.Background())
+	require.Nil(t, err)
+	s.next(t)
+
+	s.notify("rootNotification", 101, `42`)
+	root, ok := recv(t, roots)
+	assert.True(t, ok)
+	assert.Equal(t, RootNotification(42), root)
+
+	s.dropConnections()
+
+	// the client dials again and restores the subscription under a new id
+	req := s.next(t)
+	assert.Equal(t, "rootSubscribe", req.Method)
+
+	s.notify("rootNotification", 102, `43`)
+	root, ok = recv(t, roots)
+	assert.True(t, ok)
+	assert.Equal(t, RootNotification(43), root)
+
+	require.Nil(t, c.Close())
+	_, ok = recv(t, roots)
+	assert.False(t, ok)
+}
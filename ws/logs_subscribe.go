@@ -0,0 +1,65 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/labyla/solana-go-sdk/rpc"
+)
+
+type LogsNotification rpc.ValueWithContext[LogsNotificationValue]
+
+type LogsNotificationValue struct {
+	Signature string   `json:"signature"`
+	Err       any      `json:"err"`
+	Logs      []string `json:"logs"`
+}
+
+// LogsSubscribeFilter selects which txs' logs are delivered
+type LogsSubscribeFilter struct {
+	all          bool
+	allWithVotes bool
+	mentions     []string
+}
+
+var (
+	// LogsSubscribeFilterAll subscribes to all txs except simple vote txs
+	LogsSubscribeFilterAll = LogsSubscribeFilter{all: true}
+	// LogsSubscribeFilterAllWithVotes subscribes to all txs including simple vote txs
+	LogsSubscribeFilterAllWithVotes = LogsSubscribeFilter{allWithVotes: true}
+)
+
+// LogsSubscribeFilterMentions subscribes to all txs which mention the address
+func LogsSubscribeFilterMentions(base58Addr string) LogsSubscribeFilter {
+	return LogsSubscribeFilter{mentions: []string{base58Addr}}
+}
+
+func (f LogsSubscribeFilter) MarshalJSON() ([]byte, error) {
+	switch {
+	case f.all:
+		return json.Marshal("all")
+	case f.allWithVotes:
+		return json.Marshal("allWithVotes")
+	default:
+		return json.Marshal(struct {
+			Mentions []string `json:"mentions"`
+		}{
+			Mentions: f.mentions,
+		})
+	}
+}
+
+// LogsSubscribeConfig is a option config for `logsSubscribe`
+type LogsSubscribeConfig struct {
+	Commitment rpc.Commitment `json:"commitment,omitempty"`
+}
+
+// LogsSubscribe notifies the logs of every tx which matches the filter
+func (c *Client) LogsSubscribe(ctx context.Context, filter LogsSubscribeFilter) (*Subscription[LogsNotification], error) {
+	return subscribe[LogsNotification](ctx, c, newSubscription("logsSubscribe", "logsUnsubscribe", []any{filter}, c.bufferSize))
+}
+
+// LogsSubscribeWithConfig notifies the logs of every tx which matches the filter
+func (c *Client) LogsSubscribeWithConfig(ctx context.Context, filter LogsSubscribeFilter, cfg LogsSubscribeConfig) (*Subscription[LogsNotification], error) {
+	return subscribe[LogsNotification](ctx, c, newSubscription("logsSubscribe", "logsUnsubscribe", []any{filter, cfg}, c.bufferSize))
+}
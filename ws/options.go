@@ -0,0 +1,56 @@
+package ws
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// Option is a configuration type for the Client
+type Option func(*Client)
+
+// WithDialer is an Option that allows you provide your own websocket dialer
+func WithDialer(dialer *websocket.Dialer) Option {
+	return func(c *Client) {
+		c.dialer = dialer
+	}
+}
+
+// WithHeader is an Option that sets the headers of the websocket handshake
+func WithHeader(header http.Header) Option {
+	return func(c *Client) {
+		c.header = header.Clone()
+	}
+}
+
+// WithReconnectInterval is an Option that sets how long the client waits
+// between two attempts to reconnect
+func WithReconnectInterval(d time.Duration) Option {
+	return func(c *Client) {
+		c.reconnectInterval = d
+	}
+}
+
+// WithPingInterval is an Option that sets how often a ping is sent to keep
+// the connection alive. zero disables pings.
+func WithPingInterval(d time.Duration) Option {
+	return func(c *Client) {
+		c.pingInterval = d
+	}
+}
+
+// WithBufferSize is an Option that sets how many notifications are queued per
+// subscription before the reader waits for the consumer
+func WithBufferSize(n int) Option {
+	return func(c *Client) {
+		c.bufferSize = n
+	}
+}
+
+func setDefaultOptions(c *Client) {
+	c.dialer = websocket.DefaultDialer
+	c.reconnectInterval = time.Second
+	c.pingInterval = 30 * time.Second
+	c.bufferSize = 128
+}
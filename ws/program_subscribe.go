@@ -0,0 +1,26 @@
+package ws
+
+import (
+	"context"
+
+	"github.com/labyla/solana-go-sdk/rpc"
+)
+
+type ProgramNotification rpc.ValueWithContext[rpc.GetProgramAccount]
+
+// ProgramSubscribeConfig is a option config for `programSubscribe`
+type ProgramSubscribeConfig struct {
+	Commitment rpc.Commitment                       `json:"commitment,omitempty"`
+	Encoding   rpc.AccountEncoding                  `json:"encoding,omitempty"`
+	Filters    []rpc.GetProgramAccountsConfigFilter `json:"filters,omitempty"`
+}
+
+// ProgramSubscribe notifies when an account owned by the program changes
+func (c *Client) ProgramSubscribe(ctx context.Context, programId string) (*Subscription[ProgramNotification], error) {
+	return subscribe[ProgramNotification](ctx, c, newSubscription("programSubscribe", "programUnsubscribe", []any{programId}, c.bufferSize))
+}
+
+// ProgramSubscribeWithConfig notifies when an account owned by the program changes
+func (c *Client) ProgramSubscribeWithConfig(ctx context.Context, programId string, cfg ProgramSubscribeConfig) (*Subscription[ProgramNotification], error) {
+	return subscribe[ProgramNotification](ctx, c, newSubscription("programSubscribe", "programUnsubscribe", []any{programId, cfg}, c.bufferSize))
+}
@@ -0,0 +1,13 @@
+package ws
+
+import (
+	"context"
+)
+
+// RootNotification is the latest root slot number
+type RootNotification uint64
+
+// RootSubscribe notifies when a new root is set by the validator
+func (c *Client) RootSubscribe(ctx context.Context) (*Subscription[RootNotification], error) {
+	return subscribe[RootNotification](ctx, c, newSubscription("rootSubscribe", "rootUnsubscribe", nil, c.bufferSize))
+}
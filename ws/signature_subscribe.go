@@ -0,0 +1,63 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/labyla/solana-go-sdk/rpc"
+)
+
+type SignatureNotification rpc.ValueWithContext[SignatureNotificationValue]
+
+// SignatureNotificationValue is either a "receivedSignature" notice or the final result of the tx
+type SignatureNotificationValue struct {
+	Received bool
+	Err      any
+}
+
+func (v *SignatureNotificationValue) UnmarshalJSON(data []byte) error {
+	var s string
+	if json.Unmarshal(data, &s) == nil {
+		*v = SignatureNotificationValue{Received: s == "receivedSignature"}
+		return nil
+	}
+
+	var result struct {
+		Err any `json:"err"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return err
+	}
+	*v = SignatureNotificationValue{Err: result.Err}
+	return nil
+}
+
+// SignatureSubscribeConfig is a option config for `signatureSubscribe`
+type SignatureSubscribeConfig struct {
+	Commitment                 rpc.Commitment `json:"commitment,omitempty"`
+	EnableReceivedNotification bool           `json:"enableReceivedNotification,omitempty"`
+}
+
+// SignatureSubscribe notifies when the tx reaches the commitment. the subscription
+// ends by itself after the first non-received notification.
+func (c *Client) SignatureSubscribe(ctx context.Context, signature string) (*Subscription[SignatureNotification], error) {
+	return subscribe[SignatureNotification](ctx, c, newSignatureSubscription(c, []any{signature}))
+}
+
+// SignatureSubscribeWithConfig notifies when the tx reaches the commitment. the subscription
+// ends by itself after the first non-received notification.
+func (c *Client) SignatureSubscribeWithConfig(ctx context.Context, signature string, cfg SignatureSubscribeConfig) (*Subscription[SignatureNotification], error) {
+	return subscribe[SignatureNotification](ctx, c, newSignatureSubscription(c, []any{signature, cfg}))
+}
+
+func newSignatureSubscription(c *Client, params []any) *subscription {
+	sub := newSubscription("signatureSubscribe", "signatureUnsubscribe", params, c.bufferSize)
+	sub.isFinal = func(raw json.RawMessage) bool {
+		var n SignatureNotification
+		if err := json.Unmarshal(raw, &n); err != nil {
+			return true
+		}
+		return !n.Value.Received
+	}
+	return sub
+}
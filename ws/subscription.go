@@ -0,0 +1,140 @@
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// subscription is the untyped state the client keeps for every live subscription
+type subscription struct {
+	method            string
+	unsubscribeMethod string
+	params            []any
+	isFinal           func(json.RawMessage) bool
+
+	queue    chan json.RawMessage
+	done     chan struct{}
+	stopOnce sync.Once
+
+	confirmed   chan struct{}
+	confirmOnce sync.Once
+	confirmErr  error
+
+	mu     sync.Mutex
+	remote uint64
+}
+
+func newSubscription(method, unsubscribeMethod string, params []any, bufferSize int) *subscription {
+	return &subscription{
+		method:            method,
+		unsubscribeMethod: unsubscribeMethod,
+		params:            params,
+		queue:             make(chan json.RawMessage, bufferSize),
+		done:              make(chan struct{}),
+		confirmed:         make(chan struct{}),
+	}
+}
+
+func (s *subscription) confirm(remoteId uint64, err error) {
+	s.mu.Lock()
+	s.remote = remoteId
+	s.mu.Unlock()
+
+	s.confirmOnce.Do(func() {
+		s.confirmErr = err
+		close(s.confirmed)
+	})
+}
+
+func (s *subscription) remoteId() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.remote
+}
+
+// deliver is only called by the read loop
+func (s *subscription) deliver(raw json.RawMessage) {
+	select {
+	case s.queue <- raw:
+	case <-s.done:
+	}
+}
+
+// finish is only called by the read loop, after the last deliver
+func (s *subscription) finish() {
+	close(s.queue)
+}
+
+func (s *subscription) stop() {
+	s.stopOnce.Do(func() {
+		close(s.done)
+	})
+}
+
+// Subscription delivers typed notifications of a single pubsub subscription.
+// The channel returned by Recv is closed once the subscription ends.
+type Subscription[T any] struct {
+	client *Client
+	sub    *subscription
+	c      chan T
+	err    chan error
+}
+
+func subscribe[T any](ctx context.Context, c *Client, sub *subscription) (*Subscription[T], error) {
+	s := &Subscription[T]{
+		client: c,
+		sub:    sub,
+		c:      make(chan T),
+		err:    make(chan error, 1),
+	}
+	go s.forward()
+
+	if err := c.subscribe(ctx, sub); err != nil {
+		sub.stop()
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *Subscription[T]) forward() {
+	defer close(s.c)
+	for {
+		select {
+		case raw, ok := <-s.sub.queue:
+			if !ok {
+				return
+			}
+			var v T
+			if err := json.Unmarshal(raw, &v); err != nil {
+				select {
+				case s.err <- err:
+				default:
+				}
+				continue
+			}
+			select {
+			case s.c <- v:
+			case <-s.sub.done:
+				return
+			}
+		case <-s.sub.done:
+			return
+		}
+	}
+}
+
+// Recv returns the channel of notifications
+func (s *Subscription[T]) Recv() <-chan T {
+	return s.c
+}
+
+// Err returns a channel which reports notifications that could not be decoded
+func (s *Subscription[T]) Err() <-chan error {
+	return s.err
+}
+
+// Unsubscribe cancels the subscription on the node and closes the Recv channel
+func (s *Subscription[T]) Unsubscribe(ctx context.Context) error {
+	return s.client.unsubscribe(ctx, s.sub)
+}